@@ -0,0 +1,131 @@
+package webwire
+
+import (
+	"context"
+	"net/http"
+	"sync"
+)
+
+// beforeUpgrade invokes the BeforeUpgrade hook and returns its result.
+// If ServerOptions.BeforeUpgradeTimeout is set then the hook is passed
+// a request carrying a context with the according deadline and the upgrade
+// is refused with a 503 (service unavailable) status code if the hook
+// doesn't return in time, in which case nil is returned
+func (srv *server) beforeUpgrade(
+	resp http.ResponseWriter,
+	req *http.Request,
+) ConnectionOptions {
+	impl := srv.implementation()
+	timeout := srv.options.BeforeUpgradeTimeout
+	if timeout < 1 {
+		return impl.BeforeUpgrade(resp, req)
+	}
+
+	ctx, cancel := context.WithTimeout(req.Context(), timeout)
+	defer cancel()
+
+	guardedResp := &guardedResponseWriter{
+		resp:   resp,
+		header: make(http.Header),
+	}
+	result := make(chan ConnectionOptions, 1)
+	srv.spawn(func() {
+		result <- impl.BeforeUpgrade(guardedResp, req.WithContext(ctx))
+	})
+
+	select {
+	case options := <-result:
+		guardedResp.flushHeader()
+		return options
+	case <-ctx.Done():
+	}
+
+	srv.warnLog.Printf(
+		"BeforeUpgrade hook exceeded %s, refusing connection of %s",
+		timeout,
+		req.RemoteAddr,
+	)
+
+	// Respond unless the hook already responded itself
+	if guardedResp.expire() {
+		http.Error(
+			resp,
+			"Connection establishment timed out",
+			http.StatusServiceUnavailable,
+		)
+	}
+	return nil
+}
+
+// guardedResponseWriter wraps the response writer passed to
+// the BeforeUpgrade hook to drop any writes of the hook
+// after it exceeded the timeout
+type guardedResponseWriter struct {
+	lock        sync.Mutex
+	resp        http.ResponseWriter
+	header      http.Header
+	wroteHeader bool
+	expired     bool
+}
+
+// Header implements the http.ResponseWriter interface
+func (wr *guardedResponseWriter) Header() http.Header {
+	return wr.header
+}
+
+// WriteHeader implements the http.ResponseWriter interface
+func (wr *guardedResponseWriter) WriteHeader(statusCode int) {
+	wr.lock.Lock()
+	defer wr.lock.Unlock()
+	if wr.expired || wr.wroteHeader {
+		return
+	}
+	wr.writeHeader(statusCode)
+}
+
+// Write implements the http.ResponseWriter interface
+func (wr *guardedResponseWriter) Write(data []byte) (int, error) {
+	wr.lock.Lock()
+	defer wr.lock.Unlock()
+	if wr.expired {
+		return 0, http.ErrHandlerTimeout
+	}
+	if !wr.wroteHeader {
+		wr.writeHeader(http.StatusOK)
+	}
+	return wr.resp.Write(data)
+}
+
+// writeHeader copies the headers set by the hook
+// to the actual response and writes the status code
+func (wr *guardedResponseWriter) writeHeader(statusCode int) {
+	wr.copyHeader()
+	wr.wroteHeader = true
+	wr.resp.WriteHeader(statusCode)
+}
+
+// flushHeader copies the headers set by the hook to the actual response
+// unless they were already written after the hook returned in time
+func (wr *guardedResponseWriter) flushHeader() {
+	wr.lock.Lock()
+	defer wr.lock.Unlock()
+	if !wr.wroteHeader {
+		wr.copyHeader()
+	}
+}
+
+func (wr *guardedResponseWriter) copyHeader() {
+	header := wr.resp.Header()
+	for name, values := range wr.header {
+		header[name] = values
+	}
+}
+
+// expire drops all further writes and returns true
+// if nothing was written to the actual response yet
+func (wr *guardedResponseWriter) expire() bool {
+	wr.lock.Lock()
+	defer wr.lock.Unlock()
+	wr.expired = true
+	return !wr.wroteHeader
+}
@@ -0,0 +1,190 @@
+package webwire
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// CircuitState represents the state of a circuit breaker
+type CircuitState int
+
+const (
+	// CircuitClosed represents the state of a circuit breaker
+	// passing requests through to the wrapped handler
+	CircuitClosed CircuitState = iota
+
+	// CircuitOpen represents the state of a circuit breaker
+	// failing all requests without invoking the wrapped handler
+	CircuitOpen
+
+	// CircuitHalfOpen represents the state of a circuit breaker
+	// passing a single trial request through to the wrapped handler
+	// to determine whether the circuit can be closed again
+	CircuitHalfOpen
+)
+
+// String stringifies the circuit state
+func (state CircuitState) String() string {
+	switch state {
+	case CircuitClosed:
+		return "closed"
+	case CircuitOpen:
+		return "open"
+	case CircuitHalfOpen:
+		return "half-open"
+	}
+	return ""
+}
+
+// CircuitBreaker fast-fails requests with a ServiceUnavailableErr
+// while the downstream dependency of a request handler is failing
+// instead of letting requests pile up on timeouts.
+//
+// The circuit opens after the configured number of consecutive failures
+// and stays open for the configured reset timeout after which it's half-open,
+// passing a single trial request through. A successful trial request closes
+// the circuit, a failed one opens it again.
+// Any error except for ReqErr is considered a failure because request errors
+// are expected to be caused by the client rather than the downstream.
+// Outcomes of requests that passed the circuit before its last state change
+// are ignored
+type CircuitBreaker struct {
+	lock             sync.Mutex
+	clock            Clock
+	failureThreshold uint
+	resetTimeout     time.Duration
+	state            CircuitState
+	generation       uint64
+	failures         uint
+	openedAt         time.Time
+	trialPending     bool
+}
+
+// NewCircuitBreaker creates a new closed circuit breaker opening after
+// failureThreshold consecutive failures for the duration of resetTimeout.
+// A failureThreshold of 0 is treated as 1. The reset timeout is measured
+// using the optional clock which defaults to the system clock
+func NewCircuitBreaker(
+	failureThreshold uint,
+	resetTimeout time.Duration,
+	clock ...Clock,
+) *CircuitBreaker {
+	if failureThreshold < 1 {
+		failureThreshold = 1
+	}
+	cb := &CircuitBreaker{
+		clock:            NewRealClock(),
+		failureThreshold: failureThreshold,
+		resetTimeout:     resetTimeout,
+		state:            CircuitClosed,
+	}
+	if len(clock) > 0 && clock[0] != nil {
+		cb.clock = clock[0]
+	}
+	return cb
+}
+
+// setState changes the state of the circuit
+// starting a new generation of requests
+func (cb *CircuitBreaker) setState(state CircuitState) {
+	cb.state = state
+	cb.generation++
+	cb.failures = 0
+	cb.trialPending = false
+	if state == CircuitOpen {
+		cb.openedAt = cb.clock.Now()
+	}
+}
+
+// State returns the current state of the circuit
+func (cb *CircuitBreaker) State() CircuitState {
+	cb.lock.Lock()
+	defer cb.lock.Unlock()
+	if cb.state == CircuitOpen &&
+		cb.clock.Now().Sub(cb.openedAt) >= cb.resetTimeout {
+		return CircuitHalfOpen
+	}
+	return cb.state
+}
+
+// allow returns true and the current generation if a request is allowed
+// to pass the circuit, otherwise returns false and the remaining duration
+// the circuit stays open for, which is 0 while a trial request is pending
+func (cb *CircuitBreaker) allow() (bool, uint64, time.Duration) {
+	cb.lock.Lock()
+	defer cb.lock.Unlock()
+
+	openFor := cb.clock.Now().Sub(cb.openedAt)
+	if cb.state == CircuitOpen && openFor >= cb.resetTimeout {
+		cb.setState(CircuitHalfOpen)
+	}
+
+	switch cb.state {
+	case CircuitClosed:
+		return true, cb.generation, 0
+	case CircuitHalfOpen:
+		if cb.trialPending {
+			return false, 0, 0
+		}
+		cb.trialPending = true
+		return true, cb.generation, 0
+	}
+	return false, 0, cb.resetTimeout - openFor
+}
+
+// report records the outcome of a request that passed the circuit
+// in the given generation. Outcomes of earlier generations are ignored
+func (cb *CircuitBreaker) report(generation uint64, err error) {
+	cb.lock.Lock()
+	defer cb.lock.Unlock()
+
+	if generation != cb.generation {
+		return
+	}
+
+	if _, isReqErr := err.(ReqErr); err == nil || isReqErr {
+		if cb.state == CircuitHalfOpen {
+			cb.setState(CircuitClosed)
+		}
+		cb.failures = 0
+		return
+	}
+
+	cb.failures++
+	if cb.state == CircuitHalfOpen || cb.failures >= cb.failureThreshold {
+		cb.setState(CircuitOpen)
+	}
+}
+
+// Wrap wraps the given request handler into a function of the same signature
+// replying with a ServiceUnavailableErr while the circuit is open
+// carrying the remaining duration the circuit stays open for as a hint
+// about when the request may be retried.
+// It's intended to be used in the OnRequest hook
+func (cb *CircuitBreaker) Wrap(
+	handler func(context.Context, Connection, Message) (Payload, error),
+) func(context.Context, Connection, Message) (Payload, error) {
+	return func(
+		ctx context.Context,
+		client Connection,
+		message Message,
+	) (reply Payload, err error) {
+		allowed, generation, retryAfter := cb.allow()
+		if !allowed {
+			return nil, NewServiceUnavailableErr(retryAfter)
+		}
+		defer func() {
+			if recovered := recover(); recovered != nil {
+				cb.report(
+					generation,
+					fmt.Errorf("handler panicked: %v", recovered),
+				)
+				panic(recovered)
+			}
+			cb.report(generation, err)
+		}()
+		return handler(ctx, client, message)
+	}
+}
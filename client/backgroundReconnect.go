@@ -15,7 +15,7 @@ func (clt *client) backgroundReconnect() {
 	clt.connecting = true
 	clt.connectingLock.Unlock()
 	go func() {
-		for {
+		for attempt := uint(1); ; attempt++ {
 			err := clt.connect()
 			switch err := err.(type) {
 			case nil:
@@ -23,14 +23,42 @@ func (clt *client) backgroundReconnect() {
 				clt.backReconn.flush(nil)
 				clt.connecting = false
 				clt.connectingLock.Unlock()
+				clt.reqQueue.flush(nil)
 				return
 			case webwire.DisconnectedErr:
-				time.Sleep(clt.reconnInterval)
+				delay := clt.reconnectDelay(attempt)
+				if clt.onReconnSchedule != nil {
+					clt.onReconnSchedule(attempt+1, delay)
+				}
+				time.Sleep(delay)
 			default:
 				// Unexpected error
 				clt.backReconn.flush(err)
+				clt.reqQueue.flush(err)
 				return
 			}
 		}
 	}()
 }
+
+// reconnectDelay returns the delay before the reconnection attempt
+// following the given failed attempt. The reconnection interval is doubled
+// after each failed attempt up to the maximum interval
+// and reduced by a random fraction of up to the jitter factor.
+// The random source of the client isn't synchronized and is only used
+// by the single background reconnection goroutine
+func (clt *client) reconnectDelay(failedAttempt uint) time.Duration {
+	delay := clt.reconnInterval
+	for i := uint(1); i < failedAttempt && delay < clt.reconnMaxInterval; i++ {
+		delay *= 2
+	}
+	if delay > clt.reconnMaxInterval {
+		delay = clt.reconnMaxInterval
+	}
+	if clt.reconnJitter > 0 {
+		delay -= time.Duration(
+			float64(delay) * clt.reconnJitter * clt.reconnRand.Float64(),
+		)
+	}
+	return delay
+}
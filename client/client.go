@@ -6,6 +6,7 @@ import (
 
 	"fmt"
 	"log"
+	"math/rand"
 	"sync"
 	"time"
 
@@ -15,7 +16,7 @@ import (
 	reqman "github.com/qbeon/webwire-go/requestManager"
 )
 
-const supportedProtocolVersion = "1.4"
+const supportedProtocolVersion = "1.5"
 
 // Status represents the status of a client instance
 type Status = int32
@@ -47,13 +48,23 @@ const (
 
 // client represents an instance of one of the servers clients
 type client struct {
+	// serverMaxMessageSize represents the maximum message size announced
+	// by the server, it's accessed atomically and must therefore
+	// remain the first field to guarantee its 64-bit alignment
+	serverMaxMessageSize int64
+
 	serverAddr        string
 	impl              Implementation
 	sessionInfoParser webwire.SessionInfoParser
 	status            Status
 	defaultReqTimeout time.Duration
 	reconnInterval    time.Duration
+	reconnMaxInterval time.Duration
+	reconnJitter      float64
+	reconnRand        *rand.Rand
+	onReconnSchedule  func(attempt uint, delay time.Duration)
 	autoconnect       autoconnectStatus
+	nameValidator     msg.NameValidator
 
 	sessionLock sync.RWMutex
 	session     *webwire.Session
@@ -83,6 +94,43 @@ type client struct {
 
 	requestManager reqman.RequestManager
 
+	// reqQueue queues requests issued while the client is reconnecting
+	reqQueue *requestQueue
+
+	// signalsPaused is true while the server is requested
+	// to suppress non-critical signals, it's protected by signalFlowLock
+	signalsPaused  bool
+	signalFlowLock sync.Mutex
+
+	// requestVersions represents the supported request payload
+	// schema versions while agreedVersions represents the versions
+	// agreed on with the server, protected by agreedVersionsLock
+	requestVersions    map[string][]int
+	agreedVersions     map[string]int
+	agreedVersionsLock sync.RWMutex
+
+	// enforceMaxMsgSize is true if messages exceeding serverMaxMessageSize
+	// are to be rejected before they're sent
+	enforceMaxMsgSize bool
+
+	// replayIdempotent is true if pending idempotent requests are to be
+	// replayed after a reconnect, see Options.ReplayIdempotentRequests
+	replayIdempotent bool
+
+	// idempotencyScope is sent with each connection for the server
+	// to recognize idempotency keys across reconnects,
+	// see webwire.IdempotencyScopeHeader
+	idempotencyScope string
+
+	// maxReplySize is the maximum size of streamed replies in bytes,
+	// see Options.MaxReplySize
+	maxReplySize int
+
+	// signalFilter contains the names of the signals subscribed to,
+	// it's protected by signalFilterLock
+	signalFilter     map[string]struct{}
+	signalFilterLock sync.RWMutex
+
 	// Loggers
 	warningLog *log.Logger
 	errorLog   *log.Logger
@@ -125,8 +173,15 @@ func (clt *client) Request(
 	clt.apiLock.RLock()
 	defer clt.apiLock.RUnlock()
 
-	if err := clt.tryAutoconnect(ctx, clt.defaultReqTimeout); err != nil {
-		return nil, err
+	// Queue the request until the connection is reestablished
+	var sent func()
+	if atomic.LoadInt32(&clt.status) != Connected {
+		queued, err := clt.queueRequest(ctx, clt.defaultReqTimeout)
+		if err != nil {
+			return nil, err
+		}
+		defer queued.done()
+		sent = queued.done
 	}
 
 	return clt.sendRequest(
@@ -135,6 +190,7 @@ func (clt *client) Request(
 		name,
 		payload,
 		clt.defaultReqTimeout,
+		sent,
 	)
 }
 
@@ -159,21 +215,50 @@ func (clt *client) Signal(name string, payload webwire.Payload) error {
 		)
 	}
 
+	if err := clt.nameValidator(name); err != nil {
+		return webwire.NewProtocolErr(err)
+	}
+
 	// Initialize payload encoding & data
 	var encoding webwire.PayloadEncoding
 	var data []byte
+	var contentType string
 	if payload != nil {
 		encoding = payload.Encoding()
 		data = payload.Data()
+
+		var err error
+		if contentType, err = webwire.PayloadContentType(payload); err != nil {
+			return webwire.NewProtocolErr(err)
+		}
+	}
+
+	if contentType != "" {
+		return clt.writeSignal(msg.NewTypedSignalMessage(
+			name,
+			contentType,
+			data,
+			clt.nameValidator,
+		))
 	}
 
-	return clt.conn.Write(msg.NewSignalMessage(
+	return clt.writeSignal(msg.NewSignalMessage(
 		name,
 		encoding,
 		data,
+		clt.nameValidator,
 	))
 }
 
+// writeSignal sends the given signal message
+// unless it exceeds the maximum message size
+func (clt *client) writeSignal(message []byte) error {
+	if err := clt.checkMessageSize(message); err != nil {
+		return err
+	}
+	return clt.conn.Write(message)
+}
+
 // Session returns an exact copy of the session object or nil if there's no
 // session currently assigned to this client
 func (clt *client) Session() *webwire.Session {
@@ -280,6 +365,49 @@ func (clt *client) CloseSession() error {
 	return nil
 }
 
+// Pause requests the server to stop sending non-critical signals
+// until Resume is called. The pause is reapplied automatically
+// when the client reconnects
+func (clt *client) Pause() error {
+	return clt.setSignalsPaused(true)
+}
+
+// Resume requests the server to resume sending signals
+// previously paused by Pause
+func (clt *client) Resume() error {
+	return clt.setSignalsPaused(false)
+}
+
+// setSignalsPaused pauses or resumes signals synchronizing
+// the state to the server if connected
+func (clt *client) setSignalsPaused(paused bool) error {
+	clt.signalFlowLock.Lock()
+	defer clt.signalFlowLock.Unlock()
+
+	clt.signalsPaused = paused
+
+	// The state is synchronized on connection establishment
+	// if the client is currently not connected
+	if atomic.LoadInt32(&clt.status) != Connected {
+		return nil
+	}
+
+	msgType := msg.MsgResumeSignals
+	if paused {
+		msgType = msg.MsgPauseSignals
+	}
+	return clt.conn.Write([]byte{msgType})
+}
+
+// RequestVersion returns the payload schema version of the request
+// of the given name agreed on with the server during the last connection
+// establishment. Returns 0 if no version was agreed on
+func (clt *client) RequestVersion(name string) int {
+	clt.agreedVersionsLock.RLock()
+	defer clt.agreedVersionsLock.RUnlock()
+	return clt.agreedVersions[name]
+}
+
 // Close gracefully closes the connection and disables the client.
 // A disabled client won't autoconnect until enabled again.
 func (clt *client) Close() {
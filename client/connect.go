@@ -2,7 +2,11 @@ package client
 
 import (
 	"context"
+	"net/http"
 	"sync/atomic"
+
+	webwire "github.com/qbeon/webwire-go"
+	msg "github.com/qbeon/webwire-go/message"
 )
 
 // connect will try to establish a connection to the configured webwire server
@@ -10,7 +14,7 @@ import (
 // If the session restoration fails connect won't fail,
 // instead it will reset the current session and return normally.
 // Before establishing the connection - connect verifies
-// protocol compatibility and returns an error if
+// protocol compatibility and returns a ProtocolVersionMismatchErr if
 // the protocol implemented by the server doesn't match
 // the required protocol version of this client instance.
 func (clt *client) connect() error {
@@ -24,7 +28,7 @@ func (clt *client) connect() error {
 		return err
 	}
 
-	if err := clt.conn.Dial(clt.serverAddr); err != nil {
+	if err := clt.dial(); err != nil {
 		return err
 	}
 
@@ -48,6 +52,17 @@ func (clt *client) connect() error {
 
 				atomic.StoreInt32(&clt.status, Disconnected)
 
+				// Fail all pending requests, their replies are lost
+				// along with the connection. Replayable requests
+				// remain pending if the connection is to be reestablished
+				if clt.replayIdempotent && atomic.LoadInt32(
+					&clt.autoconnect,
+				) == autoconnectEnabled {
+					clt.requestManager.FailLost(webwire.ConnectionLostErr{})
+				} else {
+					clt.requestManager.FailAll(webwire.ConnectionLostErr{})
+				}
+
 				// Call hook
 				clt.impl.OnDisconnected()
 
@@ -80,6 +95,18 @@ func (clt *client) connect() error {
 
 	atomic.StoreInt32(&clt.status, Connected)
 
+	// Replay pending idempotent requests once the session is restored
+	defer clt.replayRequests()
+
+	// Reapply paused signals on the new connection
+	clt.signalFlowLock.Lock()
+	if clt.signalsPaused {
+		if err := clt.conn.Write([]byte{msg.MsgPauseSignals}); err != nil {
+			clt.warningLog.Printf("Couldn't pause signals: %s", err)
+		}
+	}
+	clt.signalFlowLock.Unlock()
+
 	// Read the current sessions key if there is any
 	clt.sessionLock.RLock()
 	if clt.session == nil {
@@ -113,3 +140,39 @@ func (clt *client) connect() error {
 	clt.sessionLock.Unlock()
 	return nil
 }
+
+// dial connects the socket to the server sending the idempotency scope
+// and the agreed request payload schema versions along with
+// the upgrade request if the socket supports custom headers
+func (clt *client) dial() error {
+	clt.agreedVersionsLock.RLock()
+	agreedVersions := clt.agreedVersions
+	clt.agreedVersionsLock.RUnlock()
+
+	headerDialer, supportsHeaders := clt.conn.(webwire.HeaderDialer)
+	if !supportsHeaders {
+		return clt.conn.Dial(clt.serverAddr)
+	}
+
+	header := http.Header{}
+	header.Set(webwire.IdempotencyScopeHeader, clt.idempotencyScope)
+	if len(agreedVersions) > 0 {
+		header.Set(
+			webwire.RequestVersionsHeader,
+			webwire.EncodeRequestVersions(agreedVersions),
+		)
+	}
+	return headerDialer.DialHeader(clt.serverAddr, header)
+}
+
+// replayRequests sends the pending replayable requests again
+// after the connection was reestablished,
+// see Options.ReplayIdempotentRequests
+func (clt *client) replayRequests() {
+	for _, message := range clt.requestManager.ReplayMessages() {
+		if err := clt.conn.Write(message); err != nil {
+			clt.warningLog.Printf("Couldn't replay request: %s", err)
+			return
+		}
+	}
+}
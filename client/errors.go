@@ -0,0 +1,38 @@
+package client
+
+import (
+	"fmt"
+
+	webwire "github.com/qbeon/webwire-go"
+)
+
+// SessionsDisabledErr is returned by RestoreSession if the server
+// has sessions disabled. It's an alias of webwire.SessionsDisabledErr,
+// thus errors.As works with either of both
+type SessionsDisabledErr = webwire.SessionsDisabledErr
+
+// SessNotFoundErr is returned by RestoreSession if the server
+// didn't find the session to be restored.
+// It's an alias of webwire.SessNotFoundErr
+type SessNotFoundErr = webwire.SessNotFoundErr
+
+// MaxSessConnsReachedErr is returned by RestoreSession if the session
+// to be restored already reached the maximum number of concurrent
+// connections. It's an alias of webwire.MaxSessConnsReachedErr
+type MaxSessConnsReachedErr = webwire.MaxSessConnsReachedErr
+
+// MessageTooBigErr is returned by Request and Signal if the message
+// exceeds the maximum message size accepted by the server
+// and Options.EnforceMaxMessageSize is enabled
+type MessageTooBigErr struct {
+	Size    int
+	MaxSize int64
+}
+
+func (err MessageTooBigErr) Error() string {
+	return fmt.Sprintf(
+		"Message too big (%d bytes, at most %d accepted by the server)",
+		err.Size,
+		err.MaxSize,
+	)
+}
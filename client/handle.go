@@ -3,6 +3,7 @@ package client
 import (
 	"encoding/json"
 	"fmt"
+	"time"
 
 	webwire "github.com/qbeon/webwire-go"
 	msg "github.com/qbeon/webwire-go/message"
@@ -32,6 +33,25 @@ func (clt *client) handleSessionCreated(msgPayload pld.Payload) {
 	clt.impl.OnSessionCreated(clt.session)
 }
 
+func (clt *client) handleSessionInfoUpdated(msgPayload pld.Payload) {
+	var info map[string]interface{}
+	if err := json.Unmarshal(msgPayload.Data, &info); err != nil {
+		clt.errorLog.Printf("Failed unmarshalling session info: %s", err)
+		return
+	}
+
+	var parsedSessInfo webwire.SessionInfo
+	if clt.sessionInfoParser != nil {
+		parsedSessInfo = clt.sessionInfoParser(info)
+	}
+
+	clt.sessionLock.Lock()
+	if clt.session != nil {
+		clt.session.Info = parsedSessInfo
+	}
+	clt.sessionLock.Unlock()
+}
+
 func (clt *client) handleSessionClosed() {
 	// Destroy local session
 	clt.sessionLock.Lock()
@@ -43,13 +63,20 @@ func (clt *client) handleSessionClosed() {
 
 func (clt *client) handleFailure(
 	reqIdent [8]byte,
-	errCode,
-	errMessage string,
+	errCode string,
+	errMessage pld.Payload,
 ) {
+	// Decode the error message according to its encoding
+	decoded, err := errMessage.Utf8()
+	if err != nil {
+		clt.requestManager.Fail(reqIdent, webwire.NewProtocolErr(err))
+		return
+	}
+
 	// Fail request
 	clt.requestManager.Fail(reqIdent, webwire.ReqErr{
 		Code:    errCode,
-		Message: errMessage,
+		Message: decoded,
 	})
 }
 
@@ -78,11 +105,80 @@ func (clt *client) handleReply(reqIdent [8]byte, payload pld.Payload) {
 	clt.requestManager.Fulfill(reqIdent, payload)
 }
 
+func (clt *client) handleFeatureDisabled(reqIdent [8]byte) {
+	clt.requestManager.Fail(reqIdent, webwire.FeatureDisabledErr{})
+}
+
+func (clt *client) handleTooManyRequests(
+	reqIdent [8]byte,
+	retryAfter time.Duration,
+) {
+	clt.requestManager.Fail(
+		reqIdent,
+		webwire.NewTooManyRequestsErr(retryAfter),
+	)
+}
+
+func (clt *client) handleUnauthorized(reqIdent [8]byte) {
+	clt.requestManager.Fail(reqIdent, webwire.UnauthorizedErr{})
+}
+
+func (clt *client) handleMethodNotFound(reqIdent [8]byte) {
+	clt.requestManager.Fail(reqIdent, webwire.MethodNotFoundErr{})
+}
+
+func (clt *client) handleServiceUnavailable(
+	reqIdent [8]byte,
+	retryAfter time.Duration,
+) {
+	clt.requestManager.Fail(
+		reqIdent,
+		webwire.NewServiceUnavailableErr(retryAfter),
+	)
+}
+
+func (clt *client) handleRequestTimeout(reqIdent [8]byte) {
+	clt.requestManager.Fail(reqIdent, webwire.RequestTimeoutErr{})
+}
+
+func (clt *client) handleSessionOperationThrottled(reqIdent [8]byte) {
+	clt.requestManager.Fail(
+		reqIdent,
+		webwire.SessionOperationThrottledErr{},
+	)
+}
+
+func (clt *client) handleReplyProtocolError(reqIdent [8]byte) {
+	clt.requestManager.Fail(reqIdent, webwire.NewProtocolErr(
+		fmt.Errorf("The server rejected the request due to a protocol error"),
+	))
+}
+
+func (clt *client) handleReplyChunk(reqIdent [8]byte, chunk []byte) {
+	clt.requestManager.AppendChunk(reqIdent, chunk, clt.maxReplySize)
+}
+
+// handleReliableSignal invokes the OnSignal hook
+// and acknowledges the reception of the signal after the hook returned
+func (clt *client) handleReliableSignal(message *msg.Message) {
+	clt.impl.OnSignal(webwire.NewMessageWrapper(message))
+	if err := clt.conn.Write(
+		msg.NewSignalAckMessage(message.Identifier),
+	); err != nil {
+		clt.errorLog.Printf("Couldn't acknowledge reliable signal: %s", err)
+	}
+}
+
 func (clt *client) handleMessage(message []byte) error {
 	if len(message) < 1 {
 		return nil
 	}
 
+	// Drop signals not subscribed to before parsing them
+	if clt.filterSignal(message) {
+		return nil
+	}
+
 	var parsedMsg msg.Message
 	typeDetermined, err := parsedMsg.Parse(message)
 	if !typeDetermined {
@@ -98,6 +194,10 @@ func (clt *client) handleMessage(message []byte) error {
 		clt.handleReply(parsedMsg.Identifier, parsedMsg.Payload)
 	case msg.MsgReplyUtf16:
 		clt.handleReply(parsedMsg.Identifier, parsedMsg.Payload)
+	case msg.MsgReplyTyped:
+		clt.handleReply(parsedMsg.Identifier, parsedMsg.Payload)
+	case msg.MsgReplyChunk:
+		clt.handleReplyChunk(parsedMsg.Identifier, parsedMsg.Payload.Data)
 	case msg.MsgReplyShutdown:
 		clt.handleReplyShutdown(parsedMsg.Identifier)
 	case msg.MsgSessionNotFound:
@@ -106,14 +206,35 @@ func (clt *client) handleMessage(message []byte) error {
 		clt.handleMaxSessConnsReached(parsedMsg.Identifier)
 	case msg.MsgSessionsDisabled:
 		clt.handleSessionsDisabled(parsedMsg.Identifier)
+	case msg.MsgFeatureDisabled:
+		clt.handleFeatureDisabled(parsedMsg.Identifier)
+	case msg.MsgTooManyRequests:
+		clt.handleTooManyRequests(parsedMsg.Identifier, parsedMsg.RetryAfter)
+	case msg.MsgUnauthorized:
+		clt.handleUnauthorized(parsedMsg.Identifier)
+	case msg.MsgMethodNotFound:
+		clt.handleMethodNotFound(parsedMsg.Identifier)
+	case msg.MsgServiceUnavailable:
+		clt.handleServiceUnavailable(
+			parsedMsg.Identifier,
+			parsedMsg.RetryAfter,
+		)
+	case msg.MsgRequestTimeout:
+		clt.handleRequestTimeout(parsedMsg.Identifier)
+	case msg.MsgSessionOperationThrottled:
+		clt.handleSessionOperationThrottled(parsedMsg.Identifier)
+	case msg.MsgReplyProtocolError:
+		clt.handleReplyProtocolError(parsedMsg.Identifier)
 	case msg.MsgErrorReply:
+		fallthrough
+	case msg.MsgErrorReplyUtf16:
 		// The message name contains the error code in case of
-		// error reply messages, while the UTF8 encoded error message is
+		// error reply messages, while the encoded error message is
 		// contained in the message payload
 		clt.handleFailure(
 			parsedMsg.Identifier,
 			parsedMsg.Name,
-			string(parsedMsg.Payload.Data),
+			parsedMsg.Payload,
 		)
 	case msg.MsgInternalError:
 		clt.handleInternalError(parsedMsg.Identifier)
@@ -123,10 +244,23 @@ func (clt *client) handleMessage(message []byte) error {
 	case msg.MsgSignalUtf8:
 		fallthrough
 	case msg.MsgSignalUtf16:
+		fallthrough
+	case msg.MsgSignalTyped:
 		clt.impl.OnSignal(webwire.NewMessageWrapper(&parsedMsg))
 
+	case msg.MsgReliableSignalBinary:
+		fallthrough
+	case msg.MsgReliableSignalUtf8:
+		fallthrough
+	case msg.MsgReliableSignalUtf16:
+		clt.handleReliableSignal(&parsedMsg)
+
 	case msg.MsgSessionCreated:
+		fallthrough
+	case msg.MsgSessionCreatedUtf8:
 		clt.handleSessionCreated(parsedMsg.Payload)
+	case msg.MsgSessionInfoUpdated:
+		clt.handleSessionInfoUpdated(parsedMsg.Payload)
 	case msg.MsgSessionClosed:
 		clt.handleSessionClosed()
 	default:
@@ -27,7 +27,10 @@ type Client interface {
 	// It blocks until either a response is received
 	// or the request fails or times out.
 	// Request will respect cancelable and timed contexts,
-	// nil contexts are also supported
+	// nil contexts are also supported.
+	// The encoding of the returned reply payload reflects the encoding
+	// the server replied with (binary, UTF8 or UTF16),
+	// the header padding of UTF16 encoded replies is stripped
 	Request(
 		ctx context.Context,
 		name string,
@@ -37,6 +40,38 @@ type Client interface {
 	// Signal sends a signal containing the given payload to the server
 	Signal(name string, payload webwire.Payload) error
 
+	// Pause requests the server to stop sending signals
+	// except the ones the server considers critical until Resume is called.
+	// The pause is automatically reapplied after reconnecting
+	Pause() error
+
+	// Resume requests the server to resume sending signals
+	// paused by Pause
+	Resume() error
+
+	// SubscribeSignal registers interest in signals of the given name.
+	// As soon as at least one signal name is subscribed to, signals
+	// of other names are dropped before being parsed
+	// and the OnSignal hook isn't invoked for them.
+	// All signals are passed to the OnSignal hook if there are
+	// no subscriptions
+	SubscribeSignal(name string)
+
+	// UnsubscribeSignal removes the interest in signals of the given name
+	// registered by SubscribeSignal
+	UnsubscribeSignal(name string)
+
+	// RequestVersion returns the payload schema version of the request
+	// of the given name agreed on with the server during the last
+	// connection establishment. Returns 0 if no version was agreed on
+	RequestVersion(name string) int
+
+	// ServerMaxMessageSize returns the maximum size in bytes of messages
+	// accepted by the server as announced in the endpoint metadata
+	// during the last connection establishment.
+	// Returns 0 if the message size is unlimited or unknown
+	ServerMaxMessageSize() int64
+
 	// Session returns an exact copy of the session object,
 	// otherwise returns nil if there's currently no session
 	Session() *webwire.Session
@@ -77,6 +112,8 @@ type Implementation interface {
 	OnSessionCreated(*webwire.Session)
 
 	// OnSessionClosed is invoked when the client's session was closed
-	// either by the server or the client itself
+	// either by the server or the client itself.
+	// The local session is already cleared when the hook is invoked,
+	// so Client.Session returns nil
 	OnSessionClosed()
 }
@@ -0,0 +1,25 @@
+package client
+
+import "sync/atomic"
+
+// ServerMaxMessageSize implements the Client interface
+func (clt *client) ServerMaxMessageSize() int64 {
+	return atomic.LoadInt64(&clt.serverMaxMessageSize)
+}
+
+// checkMessageSize returns a MessageTooBigErr if the given message exceeds
+// the maximum message size accepted by the server
+// and Options.EnforceMaxMessageSize is enabled
+func (clt *client) checkMessageSize(message []byte) error {
+	if !clt.enforceMaxMsgSize {
+		return nil
+	}
+	maxSize := clt.ServerMaxMessageSize()
+	if maxSize > 0 && int64(len(message)) > maxSize {
+		return MessageTooBigErr{
+			Size:    len(message),
+			MaxSize: maxSize,
+		}
+	}
+	return nil
+}
@@ -3,7 +3,9 @@ package client
 import (
 	"context"
 	"fmt"
+	"math/rand"
 	"sync"
+	"time"
 
 	webwire "github.com/qbeon/webwire-go"
 	reqman "github.com/qbeon/webwire-go/requestManager"
@@ -39,7 +41,12 @@ func NewClient(
 		status:            Disconnected,
 		defaultReqTimeout: opts.DefaultRequestTimeout,
 		reconnInterval:    opts.ReconnectionInterval,
+		reconnMaxInterval: opts.ReconnectionMaxInterval,
+		reconnJitter:      opts.ReconnectionJitter,
+		reconnRand:        rand.New(rand.NewSource(time.Now().UnixNano())),
+		onReconnSchedule:  opts.OnReconnectScheduled,
 		autoconnect:       autoconnect,
+		nameValidator:     opts.NameValidator,
 		sessionLock:       sync.RWMutex{},
 		session:           nil,
 		apiLock:           sync.RWMutex{},
@@ -50,6 +57,12 @@ func NewClient(
 		conn:              webwire.NewSocket(),
 		readerClosing:     make(chan bool, 1),
 		requestManager:    reqman.NewRequestManager(),
+		reqQueue:          newRequestQueue(opts.ReconnectQueueCapacity),
+		requestVersions:   opts.RequestVersions,
+		enforceMaxMsgSize: opts.EnforceMaxMessageSize == webwire.Enabled,
+		replayIdempotent:  opts.ReplayIdempotentRequests == webwire.Enabled,
+		idempotencyScope:  webwire.NewIdempotencyScope(),
+		maxReplySize:      opts.MaxReplySize,
 		warningLog:        opts.WarnLog,
 		errorLog:          opts.ErrorLog,
 	}
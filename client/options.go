@@ -6,6 +6,7 @@ import (
 	"time"
 
 	webwire "github.com/qbeon/webwire-go"
+	msg "github.com/qbeon/webwire-go/message"
 )
 
 // Options represents the options used during the creation a new client instance
@@ -30,9 +31,77 @@ type Options struct {
 
 	// ReconnectionInterval defines the interval at which autoconnect
 	// should retry connection establishment.
+	// The interval is doubled after each failed attempt
+	// up to ReconnectionMaxInterval.
 	// If undefined then the default value of 2 seconds is applied
 	ReconnectionInterval time.Duration
 
+	// ReconnectionMaxInterval defines the maximum interval the exponentially
+	// growing reconnection interval is capped at.
+	// If undefined then the default value of 30 seconds is applied.
+	// It's raised to ReconnectionInterval if it's below it
+	ReconnectionMaxInterval time.Duration
+
+	// ReconnectionJitter defines the factor (0 to 1) of the reconnection
+	// interval randomly subtracted from it before each attempt to spread
+	// the reconnection attempts of many clients losing their connection
+	// simultaneously, for example when the server restarts.
+	// The interval isn't randomized if it's 0 which is the default
+	ReconnectionJitter float64
+
+	// OnReconnectScheduled is invoked with the number of the next
+	// reconnection attempt and the delay until it, for example to display
+	// a countdown, it's optional. The first attempt is made immediately
+	// without being scheduled, the first scheduled attempt is therefore
+	// the attempt number 2
+	OnReconnectScheduled func(attempt uint, delay time.Duration)
+
+	// ReconnectQueueCapacity defines the maximum number of requests
+	// queued while the client is reconnecting. Queued requests are sent
+	// in order as soon as the connection is reestablished.
+	// If undefined then the default capacity of 1024 requests is applied
+	ReconnectQueueCapacity uint
+
+	// NameValidator defines the validator verifying the names
+	// of outgoing requests and signals.
+	// If undefined then msg.ValidateNameASCII is applied allowing
+	// printable 7-bit ASCII characters only
+	NameValidator msg.NameValidator
+
+	// RequestVersions defines the payload schema versions supported
+	// by the client per request name. The highest version supported
+	// by both the client and the server is agreed on for each request
+	// during the connection establishment, see client.RequestVersion
+	RequestVersions map[string][]int
+
+	// EnforceMaxMessageSize defines whether requests and signals exceeding
+	// the maximum message size accepted by the server
+	// (see client.ServerMaxMessageSize) are rejected with
+	// a MessageTooBigErr before they're sent.
+	// It's disabled by default, in which case the server closes
+	// the connection when receiving oversized messages
+	EnforceMaxMessageSize webwire.OptionValue
+
+	// ReplayIdempotentRequests defines whether pending requests carrying
+	// an idempotency key (see webwire.IdempotentRequestName) are sent again
+	// when the connection is lost before their replies are received
+	// and autoconnect reestablishes it. They're replayed in order
+	// right after the reconnection and keep awaiting their replies
+	// within their original timeout.
+	// Any other pending requests are considered lost and fail
+	// with a webwire.ConnectionLostErr because the server may or may not
+	// have processed them.
+	// It's disabled by default, in which case all pending requests fail
+	// with a webwire.ConnectionLostErr when the connection is lost
+	ReplayIdempotentRequests webwire.OptionValue
+
+	// MaxReplySize defines the maximum size in bytes of streamed replies
+	// reassembled by the client. Requests whose streamed reply exceeds it
+	// fail with a webwire.ReplyTooLargeErr and the remaining chunks
+	// are discarded. If undefined then the default maximum
+	// of 64 MiB is applied
+	MaxReplySize int
+
 	// WarnLog defines the warn logging output target
 	WarnLog *log.Logger
 
@@ -58,6 +127,39 @@ func (opts *Options) SetDefaults() {
 		opts.ReconnectionInterval = 2 * time.Second
 	}
 
+	if opts.ReconnectionMaxInterval < 1 {
+		opts.ReconnectionMaxInterval = 30 * time.Second
+	}
+	if opts.ReconnectionMaxInterval < opts.ReconnectionInterval {
+		opts.ReconnectionMaxInterval = opts.ReconnectionInterval
+	}
+
+	if opts.ReconnectionJitter < 0 {
+		opts.ReconnectionJitter = 0
+	} else if opts.ReconnectionJitter > 1 {
+		opts.ReconnectionJitter = 1
+	}
+
+	if opts.EnforceMaxMessageSize == webwire.OptionUnset {
+		opts.EnforceMaxMessageSize = webwire.Disabled
+	}
+
+	if opts.ReplayIdempotentRequests == webwire.OptionUnset {
+		opts.ReplayIdempotentRequests = webwire.Disabled
+	}
+
+	if opts.ReconnectQueueCapacity < 1 {
+		opts.ReconnectQueueCapacity = 1024
+	}
+
+	if opts.NameValidator == nil {
+		opts.NameValidator = msg.ValidateNameASCII
+	}
+
+	if opts.MaxReplySize < 1 {
+		opts.MaxReplySize = 64 * 1024 * 1024
+	}
+
 	// Create default loggers to std-out/err when no loggers are specified
 	if opts.WarnLog == nil {
 		opts.WarnLog = log.New(
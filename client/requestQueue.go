@@ -0,0 +1,127 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	webwire "github.com/qbeon/webwire-go"
+)
+
+// queuedRequest represents a request awaiting the reestablishment
+// of the connection in the request queue
+type queuedRequest struct {
+	// proceed receives either nil when the request may be sent
+	// or the error the reconnection failed with
+	proceed chan error
+
+	// sent is closed when the request was either sent or abandoned
+	sent     chan struct{}
+	sentOnce sync.Once
+}
+
+// done marks the queued request as either sent or abandoned
+// allowing the next queued request to proceed
+func (req *queuedRequest) done() {
+	req.sentOnce.Do(func() {
+		close(req.sent)
+	})
+}
+
+// requestQueue represents a bounded FIFO queue of requests
+// issued while the client is reconnecting
+type requestQueue struct {
+	lock      sync.Mutex
+	flushLock sync.Mutex
+	capacity  uint
+	queue     []*queuedRequest
+}
+
+// newRequestQueue constructs a new request queue instance
+// of the given capacity
+func newRequestQueue(capacity uint) *requestQueue {
+	return &requestQueue{
+		lock:      sync.Mutex{},
+		flushLock: sync.Mutex{},
+		capacity:  capacity,
+		queue:     make([]*queuedRequest, 0),
+	}
+}
+
+// enqueue appends a new request to the queue.
+// Returns a ReqQueueFullErr error if the queue is full
+func (rq *requestQueue) enqueue() (*queuedRequest, error) {
+	rq.lock.Lock()
+	defer rq.lock.Unlock()
+	if uint(len(rq.queue)) >= rq.capacity {
+		return nil, webwire.ReqQueueFullErr{}
+	}
+	req := &queuedRequest{
+		proceed: make(chan error, 1),
+		sent:    make(chan struct{}),
+	}
+	rq.queue = append(rq.queue, req)
+	return req, nil
+}
+
+// remove removes the given request from the queue if it's still queued
+func (rq *requestQueue) remove(req *queuedRequest) {
+	rq.lock.Lock()
+	defer rq.lock.Unlock()
+	for i, queued := range rq.queue {
+		if queued == req {
+			rq.queue = append(rq.queue[:i], rq.queue[i+1:]...)
+			return
+		}
+	}
+}
+
+// pop removes and returns the first request of the queue,
+// returns nil if the queue is empty
+func (rq *requestQueue) pop() *queuedRequest {
+	rq.lock.Lock()
+	defer rq.lock.Unlock()
+	if len(rq.queue) < 1 {
+		return nil
+	}
+	req := rq.queue[0]
+	rq.queue = rq.queue[1:]
+	return req
+}
+
+// flush releases all queued requests in order.
+// If err is nil then each request is awaited to be sent before the next one
+// is released to preserve the order, otherwise all requests are failed
+func (rq *requestQueue) flush(err error) {
+	rq.flushLock.Lock()
+	defer rq.flushLock.Unlock()
+	for {
+		req := rq.pop()
+		if req == nil {
+			return
+		}
+		req.proceed <- err
+		if err == nil {
+			<-req.sent
+		}
+	}
+}
+
+// await blocks the calling goroutine until either the queued request
+// may proceed, the timeout is triggered or the context is canceled
+func (req *queuedRequest) await(
+	ctx context.Context,
+	timeout time.Duration,
+) error {
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return webwire.TranslateContextError(ctx.Err())
+	case err := <-req.proceed:
+		return err
+	case <-timer.C:
+		return webwire.NewTimeoutErr(fmt.Errorf("timed out"))
+	}
+}
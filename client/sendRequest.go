@@ -3,6 +3,7 @@ package client
 import (
 	"context"
 	"fmt"
+	"strings"
 	"time"
 
 	webwire "github.com/qbeon/webwire-go"
@@ -15,6 +16,7 @@ func (clt *client) sendRequest(
 	name string,
 	payload webwire.Payload,
 	timeout time.Duration,
+	sent func(),
 ) (webwire.Payload, error) {
 	// Require either a name or a payload or both
 	if len(name) < 1 && (payload == nil || len(payload.Data()) < 1) {
@@ -25,6 +27,10 @@ func (clt *client) sendRequest(
 		)
 	}
 
+	if err := clt.nameValidator(name); err != nil {
+		return nil, webwire.NewProtocolErr(err)
+	}
+
 	// Return an error if the request was already prematurely canceled
 	// or already exceeded the user-defined deadline for its completion
 	select {
@@ -35,24 +41,61 @@ func (clt *client) sendRequest(
 
 	payloadEncoding := webwire.EncodingBinary
 	var payloadData []byte
+	var contentType string
 	if payload != nil {
 		payloadEncoding = payload.Encoding()
 		payloadData = payload.Data()
+
+		var err error
+		contentType, err = webwire.PayloadContentType(payload)
+		if err != nil {
+			return nil, webwire.NewProtocolErr(err)
+		}
 	}
 
 	// Compose a message and register it
 	request := clt.requestManager.Create(timeout)
 	reqIdentifier := request.Identifier()
-	msg := msg.NewRequestMessage(
-		reqIdentifier,
-		name,
-		payloadEncoding,
-		payloadData,
-	)
+	var message []byte
+	if contentType != "" {
+		message = msg.NewTypedRequestMessage(
+			reqIdentifier,
+			name,
+			contentType,
+			payloadData,
+			clt.nameValidator,
+		)
+	} else {
+		message = msg.NewRequestMessage(
+			reqIdentifier,
+			name,
+			payloadEncoding,
+			payloadData,
+			clt.nameValidator,
+		)
+	}
+
+	// Reject the request before sending it if it's too big
+	if err := clt.checkMessageSize(message); err != nil {
+		clt.requestManager.Fail(reqIdentifier, err)
+		return nil, err
+	}
+
+	// Mark the request replayable before sending it to not consider
+	// it lost if the connection is lost right after sending it
+	if clt.replayIdempotent &&
+		strings.Contains(name, webwire.IdempotencyKeySeparator) {
+		request.MarkReplayable(message)
+	}
 
 	// Send request
-	if err := clt.conn.Write(msg); err != nil {
-		return nil, webwire.NewReqTransErr(err)
+	if err := clt.conn.Write(message); err != nil {
+		err = webwire.NewReqTransErr(err)
+		clt.requestManager.Fail(reqIdentifier, err)
+		return nil, err
+	}
+	if sent != nil {
+		sent()
 	}
 
 	// Block until request either times out or a response is received
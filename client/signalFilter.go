@@ -0,0 +1,88 @@
+package client
+
+import (
+	msg "github.com/qbeon/webwire-go/message"
+)
+
+// SubscribeSignal implements the Client interface
+func (clt *client) SubscribeSignal(name string) {
+	clt.signalFilterLock.Lock()
+	if clt.signalFilter == nil {
+		clt.signalFilter = make(map[string]struct{})
+	}
+	clt.signalFilter[name] = struct{}{}
+	clt.signalFilterLock.Unlock()
+}
+
+// UnsubscribeSignal implements the Client interface
+func (clt *client) UnsubscribeSignal(name string) {
+	clt.signalFilterLock.Lock()
+	delete(clt.signalFilter, name)
+	clt.signalFilterLock.Unlock()
+}
+
+// signalSubscribed returns true if signals of the given name are to be
+// passed to the OnSignal hook, which is the case for all signals
+// as long as no signal names are subscribed to
+func (clt *client) signalSubscribed(name []byte) bool {
+	clt.signalFilterLock.RLock()
+	defer clt.signalFilterLock.RUnlock()
+	if len(clt.signalFilter) < 1 {
+		return true
+	}
+	_, subscribed := clt.signalFilter[string(name)]
+	return subscribed
+}
+
+// filterSignal returns true if the given raw message is a signal
+// of a name not subscribed to, which is then dropped without being parsed.
+// Dropped reliable signals are still acknowledged
+// to prevent the server from sending them again
+func (clt *client) filterSignal(message []byte) bool {
+	// Determine the offset of the name length flag
+	nameLenOffset := 1
+	reliable := false
+	switch message[0] {
+	case msg.MsgSignalBinary:
+	case msg.MsgSignalUtf8:
+	case msg.MsgSignalUtf16:
+	case msg.MsgSignalTyped:
+	case msg.MsgReliableSignalBinary:
+		fallthrough
+	case msg.MsgReliableSignalUtf8:
+		fallthrough
+	case msg.MsgReliableSignalUtf16:
+		// Reliable signals carry an identifier before the name
+		nameLenOffset = 9
+		reliable = true
+	default:
+		return false
+	}
+
+	// Leave malformed messages to the parser
+	if len(message) < nameLenOffset+1 {
+		return false
+	}
+	nameEnd := nameLenOffset + 1 + int(message[nameLenOffset])
+	if len(message) < nameEnd {
+		return false
+	}
+
+	if clt.signalSubscribed(message[nameLenOffset+1 : nameEnd]) {
+		return false
+	}
+
+	if reliable {
+		var identifier [8]byte
+		copy(identifier[:], message[1:9])
+		if err := clt.conn.Write(
+			msg.NewSignalAckMessage(identifier),
+		); err != nil {
+			clt.errorLog.Printf(
+				"Couldn't acknowledge reliable signal: %s",
+				err,
+			)
+		}
+	}
+	return true
+}
@@ -8,6 +8,41 @@ import (
 	webwire "github.com/qbeon/webwire-go"
 )
 
+// queueRequest queues a request issued while the client isn't connected
+// and blocks the calling goroutine until the connection is reestablished.
+// The returned queued request must be marked done once it's sent
+// to let the next queued request proceed.
+// Returns a DisconnectedErr if autoconnect is disabled
+func (clt *client) queueRequest(
+	ctx context.Context,
+	timeout time.Duration,
+) (*queuedRequest, error) {
+	if atomic.LoadInt32(&clt.autoconnect) != autoconnectEnabled {
+		return nil, webwire.DisconnectedErr{}
+	}
+
+	queued, err := clt.reqQueue.enqueue()
+	if err != nil {
+		return nil, err
+	}
+
+	// Start the reconnector goroutine if not already started
+	clt.backgroundReconnect()
+
+	// Flush the queue if the connection was reestablished
+	// before the request was queued
+	if atomic.LoadInt32(&clt.status) == Connected {
+		go clt.reqQueue.flush(nil)
+	}
+
+	if err := queued.await(ctx, timeout); err != nil {
+		clt.reqQueue.remove(queued)
+		queued.done()
+		return nil, err
+	}
+	return queued, nil
+}
+
 // tryAutoconnect tries to connect to the server.
 // If autoconnect is enabled it will spawn a new autoconnector goroutine which
 // will periodically poll the server and check whether it's available again.
@@ -0,0 +1,47 @@
+//go:build go1.18
+// +build go1.18
+
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	webwire "github.com/qbeon/webwire-go"
+)
+
+// Request JSON-encodes the given request, sends it to the server under the
+// given name and JSON-decodes the reply into a Resp. It's the client-side
+// counterpart of webwire.Handle. An empty reply is returned as the zero
+// value of Resp
+func Request[Req, Resp any](
+	ctx context.Context,
+	clt Client,
+	name string,
+	request Req,
+) (Resp, error) {
+	var response Resp
+
+	payload, err := webwire.Reply(request)
+	if err != nil {
+		return response, err
+	}
+
+	reply, err := clt.Request(ctx, name, payload)
+	if err != nil {
+		return response, err
+	}
+	if reply == nil || len(reply.Data()) < 1 {
+		return response, nil
+	}
+
+	text, err := reply.Utf8()
+	if err != nil {
+		return response, fmt.Errorf("Couldn't decode reply payload: %s", err)
+	}
+	if err := json.Unmarshal([]byte(text), &response); err != nil {
+		return response, fmt.Errorf("Couldn't decode reply payload: %s", err)
+	}
+	return response, nil
+}
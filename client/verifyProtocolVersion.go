@@ -5,13 +5,16 @@ import (
 	"fmt"
 	"io/ioutil"
 	"net/http"
+	"sync/atomic"
 	"time"
 
 	"github.com/qbeon/webwire-go"
 )
 
 // verifyProtocolVersion requests the endpoint metadata
-// to verify the server is running a supported protocol version
+// to verify the server is running a supported protocol version,
+// agrees on the request payload schema versions
+// and adopts the maximum message size accepted by the server
 func (clt *client) verifyProtocolVersion() error {
 	// Initialize HTTP client
 	var httpClient = &http.Client{
@@ -50,7 +53,9 @@ func (clt *client) verifyProtocolVersion() error {
 
 	// Unmarshal response
 	var metadata struct {
-		ProtocolVersion string `json:"protocol-version"`
+		ProtocolVersion string           `json:"protocol-version"`
+		RequestVersions map[string][]int `json:"request-versions"`
+		MaxMessageSize  int64            `json:"max-message-size"`
 	}
 	if err := json.Unmarshal(encodedData, &metadata); err != nil {
 		return webwire.NewProtocolErr(fmt.Errorf(
@@ -62,11 +67,22 @@ func (clt *client) verifyProtocolVersion() error {
 
 	// Verify metadata
 	if metadata.ProtocolVersion != supportedProtocolVersion {
-		return webwire.NewConnIncompErr(
+		return webwire.NewProtocolVersionMismatchErr(
 			metadata.ProtocolVersion,
 			supportedProtocolVersion,
 		)
 	}
 
+	// Agree on the request payload schema versions
+	agreedVersions := webwire.NegotiateRequestVersions(
+		metadata.RequestVersions,
+		clt.requestVersions,
+	)
+	clt.agreedVersionsLock.Lock()
+	clt.agreedVersions = agreedVersions
+	clt.agreedVersionsLock.Unlock()
+
+	atomic.StoreInt64(&clt.serverMaxMessageSize, metadata.MaxMessageSize)
+
 	return nil
 }
@@ -0,0 +1,16 @@
+package webwire
+
+import "time"
+
+// realClock implements the Clock interface using the system clock
+type realClock struct{}
+
+// NewRealClock creates a new clock reading the system time
+func NewRealClock() Clock {
+	return realClock{}
+}
+
+// Now implements the Clock interface
+func (realClock) Now() time.Time {
+	return time.Now()
+}
@@ -0,0 +1,68 @@
+package webwire
+
+// CloseLogLevel defines how the closure of a client connection
+// with a certain WebSocket close code is logged,
+// see ServerOptions.ClassifyCloseCode
+type CloseLogLevel int
+
+const (
+	// CloseLogSuppress doesn't log the closure,
+	// it's meant for expected close codes
+	CloseLogSuppress CloseLogLevel = iota
+
+	// CloseLogWarn logs the closure as a warning
+	CloseLogWarn
+
+	// CloseLogError logs the closure as an error
+	CloseLogError
+)
+
+// String stringifies the close log level
+func (level CloseLogLevel) String() string {
+	switch level {
+	case CloseLogSuppress:
+		return "suppress"
+	case CloseLogWarn:
+		return "warn"
+	case CloseLogError:
+		return "error"
+	}
+	return ""
+}
+
+// DefaultClassifyCloseCode is the default ServerOptions.ClassifyCloseCode.
+// It suppresses the logging of normal closures (1000), clients going away
+// (1001) and connections lost without a close frame (1006)
+// and logs any other close code as a warning
+func DefaultClassifyCloseCode(code int) CloseLogLevel {
+	switch code {
+	case 1000, 1001, 1006:
+		return CloseLogSuppress
+	}
+	return CloseLogWarn
+}
+
+// logClosure logs the closure of a client connection
+// according to ServerOptions.ClassifyCloseCode.
+// Sockets not providing the close code (see SockReadCloseCoder)
+// are logged as a warning if they report an abnormal closure
+func (srv *server) logClosure(err SockReadErr) {
+	coder, isCoder := err.(SockReadCloseCoder)
+	if !isCoder {
+		if err.IsAbnormalCloseErr() {
+			srv.warnLog.Printf("Abnormal closure error: %s", err)
+		}
+		return
+	}
+
+	code := coder.CloseCode()
+	if code == 0 {
+		return
+	}
+	switch srv.options.ClassifyCloseCode(code) {
+	case CloseLogWarn:
+		srv.warnLog.Printf("Abnormal closure error (%d): %s", code, err)
+	case CloseLogError:
+		srv.errorLog.Printf("Abnormal closure error (%d): %s", code, err)
+	}
+}
@@ -0,0 +1,94 @@
+package webwire
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+)
+
+// compressedSessionInfo implements the SessionInfo interface
+// holding the session info as gzip compressed JSON,
+// see ServerOptions.CompressSessionInfo.
+// The info is decompressed and parsed on each access
+type compressedSessionInfo struct {
+	compressed []byte
+	parser     SessionInfoParser
+}
+
+// newCompressedSessionInfo compresses the given session info
+func newCompressedSessionInfo(
+	info SessionInfo,
+	parser SessionInfoParser,
+) (*compressedSessionInfo, error) {
+	encoded, err := json.Marshal(SessionInfoToVarMap(info))
+	if err != nil {
+		return nil, fmt.Errorf("Couldn't marshal session info: %s", err)
+	}
+
+	compressed := &bytes.Buffer{}
+	writer := gzip.NewWriter(compressed)
+	if _, err := writer.Write(encoded); err != nil {
+		return nil, fmt.Errorf("Couldn't compress session info: %s", err)
+	}
+	if err := writer.Close(); err != nil {
+		return nil, fmt.Errorf("Couldn't compress session info: %s", err)
+	}
+
+	return &compressedSessionInfo{
+		compressed: compressed.Bytes(),
+		parser:     parser,
+	}, nil
+}
+
+// decompress decompresses and parses the session info
+func (info *compressedSessionInfo) decompress() SessionInfo {
+	reader, err := gzip.NewReader(bytes.NewReader(info.compressed))
+	if err != nil {
+		panic(fmt.Errorf("Couldn't decompress session info: %s", err))
+	}
+	encoded, err := ioutil.ReadAll(reader)
+	if err != nil {
+		panic(fmt.Errorf("Couldn't decompress session info: %s", err))
+	}
+	var varMap map[string]interface{}
+	if err := json.Unmarshal(encoded, &varMap); err != nil {
+		panic(fmt.Errorf("Couldn't unmarshal session info: %s", err))
+	}
+	return info.parser(varMap)
+}
+
+// Fields implements the SessionInfo interface
+func (info *compressedSessionInfo) Fields() []string {
+	return info.decompress().Fields()
+}
+
+// Value implements the SessionInfo interface
+func (info *compressedSessionInfo) Value(fieldName string) interface{} {
+	return info.decompress().Value(fieldName)
+}
+
+// Copy implements the SessionInfo interface.
+// The compressed data is never mutated and is therefore shared
+func (info *compressedSessionInfo) Copy() SessionInfo {
+	return &compressedSessionInfo{
+		compressed: info.compressed,
+		parser:     info.parser,
+	}
+}
+
+// storedSessionInfo returns the given session info as it's to be held
+// by connections which is compressed if ServerOptions.CompressSessionInfo
+// is enabled
+func (srv *server) storedSessionInfo(info SessionInfo) SessionInfo {
+	if !srv.options.CompressSessionInfo || info == nil {
+		return info
+	}
+	compressed, err := newCompressedSessionInfo(info, srv.sessionInfoParser)
+	if err != nil {
+		srv.errorLog.Printf("Holding uncompressed session info: %s", err)
+		return info
+	}
+	return compressed
+}
@@ -0,0 +1,65 @@
+package webwire
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// largeSessionInfo returns a generic session info object
+// containing a large and well compressible field
+func largeSessionInfo() SessionInfo {
+	return &GenericSessionInfo{
+		data: map[string]interface{}{
+			"name":        "samplename",
+			"permissions": strings.Repeat("read,write,", 1024),
+		},
+	}
+}
+
+// TestCompressedSessionInfo tests the compressed session info
+func TestCompressedSessionInfo(t *testing.T) {
+	original := largeSessionInfo()
+	compressed, err := newCompressedSessionInfo(
+		original,
+		GenericSessionInfoParser,
+	)
+	require.NoError(t, err)
+
+	// Expect the compressed representation to be smaller
+	require.True(t, len(compressed.compressed) < 1024)
+
+	require.ElementsMatch(t, original.Fields(), compressed.Fields())
+	require.Equal(t, original.Value("name"), compressed.Value("name"))
+	require.Equal(
+		t,
+		original.Value("permissions"),
+		compressed.Value("permissions"),
+	)
+	require.Equal(t, "samplename", compressed.Copy().Value("name"))
+}
+
+// BenchmarkCompressedSessionInfoValue benchmarks the access
+// of a field of a compressed session info object
+func BenchmarkCompressedSessionInfoValue(b *testing.B) {
+	compressed, err := newCompressedSessionInfo(
+		largeSessionInfo(),
+		GenericSessionInfoParser,
+	)
+	require.NoError(b, err)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		compressed.Value("name")
+	}
+}
+
+// BenchmarkSessionInfoValue benchmarks the access
+// of a field of an uncompressed session info object
+func BenchmarkSessionInfoValue(b *testing.B) {
+	info := largeSessionInfo()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		info.Value("name")
+	}
+}
@@ -1,10 +1,14 @@
 package webwire
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"net"
+	"net/http"
+	"net/url"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	msg "github.com/qbeon/webwire-go/message"
@@ -18,18 +22,90 @@ type ClientInfo struct {
 	RemoteAddr     net.Addr
 }
 
+// ConnectionInfo represents a consistent snapshot of the state
+// of a client connection
+type ConnectionInfo struct {
+	// RemoteAddr is the remote address of the client
+	RemoteAddr net.Addr
+
+	// UserAgent is the user agent string of the client
+	UserAgent string
+
+	// ConnectionTime is the time the connection was established at
+	ConnectionTime time.Time
+
+	// LastActivity is the time the last message was received
+	LastActivity time.Time
+
+	// SessionKey is the key of the currently assigned session,
+	// empty if no session is assigned
+	SessionKey string
+
+	// SessionCreation is the creation time of the currently assigned session,
+	// zero if no session is assigned
+	SessionCreation time.Time
+
+	// InFlightRequests is the number of requests
+	// currently processed by the OnRequest hook
+	InFlightRequests uint
+}
+
 // connection represents a connected client connected to the server
 type connection struct {
+	// lastActivity represents the time of the last inbound message
+	// in unix nanoseconds, it's accessed atomically and must therefore
+	// remain the first field to guarantee its 64-bit alignment
+	lastActivity int64
+
+	// pendingOutboundBytes represents the number of outbound bytes
+	// pending to be written to the socket, it's accessed atomically
+	// and must therefore directly follow lastActivity
+	pendingOutboundBytes int64
+
+	// slowConsumer is true if the connection was closed due to
+	// ServerOptions.MaxPendingOutboundBytes being exceeded
+	// while it had the most outbound bytes pending, it's protected
+	// by stateLock
+	slowConsumer bool
+
 	// options represents the options defined during the connection upgrade
 	options ConnectionOptions
 
-	// stateLock protects both isActive and tasks from concurrent access
+	// stateLock protects isActive, failed, signalsPaused, subscriptions
+	// and tasks from concurrent access
 	stateLock sync.RWMutex
 	isActive  bool
 
+	// failed is true if the connection was closed due to a panic
+	// while serving it
+	failed bool
+
+	// signalsPaused is true while the client requested
+	// non-critical signals to be suppressed
+	signalsPaused bool
+
+	// subscriptions represents the set of topics the connection
+	// is subscribed to, see Server.Publish
+	subscriptions map[string]struct{}
+
 	// tasks represents the number of currently performed tasks
 	tasks int32
 
+	// inFlightRequests represents the number of requests
+	// currently processed by the OnRequest hook
+	inFlightRequests uint
+
+	// signalAcksLock protects lastSignalIdent and signalAcks
+	// from concurrent access
+	signalAcksLock sync.Mutex
+
+	// lastSignalIdent represents the last reliable signal identifier
+	lastSignalIdent uint64
+
+	// signalAcks maps the identifiers of the reliable signals awaiting
+	// acknowledgement to the channels closed on acknowledgement
+	signalAcks map[[8]byte]chan struct{}
+
 	// handlerSlots keeps track of available handler slots
 	handlerSlots *semaphore.Weighted
 
@@ -42,11 +118,58 @@ type connection struct {
 	// sessionLock protects the session field from concurrent access
 	sessionLock sync.RWMutex
 
+	// sessionPatchLock serializes session info patches
+	sessionPatchLock sync.Mutex
+
 	// session references the currently assigned session, can be null
 	session *Session
 
+	// sessionOpsLock protects sessionOpsWindow and sessionOps
+	// from concurrent access
+	sessionOpsLock sync.Mutex
+
+	// sessionOpsWindow represents the start of the current
+	// session operation rate limiting window
+	sessionOpsWindow time.Time
+
+	// sessionOps represents the number of session operations
+	// performed within the current rate limiting window
+	sessionOps uint
+
 	// info represents overall connection information
 	info ClientInfo
+
+	// header contains a copy of the upgrade request headers
+	// listed in ServerOptions.ForwardedHeaders
+	header http.Header
+
+	// connectParams contains a copy of the upgrade request URL query
+	// parameters listed in ServerOptions.ConnectParams
+	connectParams url.Values
+
+	// requestVersions contains the request payload schema versions
+	// agreed on during the connection establishment
+	requestVersions map[string]int
+
+	// idempotencyScope scopes the idempotency keys of the requests
+	// sent over the connection while it has no session, see
+	// IdempotencyScopeHeader. It's empty if no idempotency store
+	// is configured
+	idempotencyScope string
+
+	// tags contains the tags attached by the BeforeUpgrade hook,
+	// it's never modified after the connection is created
+	tags map[string]struct{}
+
+	// ctx is cancelled when the connection is closed
+	ctx       context.Context
+	cancelCtx context.CancelFunc
+
+	// awaitCtx is cancelled when either the connection is closed
+	// or the server is shut down, it aborts the read loop
+	// awaiting the capacity to handle the next message
+	awaitCtx    context.Context
+	cancelAwait context.CancelFunc
 }
 
 // newConnection creates and returns a new client connection instance
@@ -66,11 +189,26 @@ func newConnection(
 	}
 
 	concurrencyLimit := int64(0)
+	var tags map[string]struct{}
 	if options != nil {
 		concurrencyLimit = int64(options.ConcurrencyLimit())
+		if tagged, isTagged := options.(TaggedConnectionOptions); isTagged {
+			tags = makeTagSet(tagged.Tags())
+		}
+	}
+
+	ctx, cancelCtx := context.WithCancel(context.Background())
+	awaitCtx, cancelAwait := context.WithCancel(ctx)
+
+	var creationTime time.Time
+	if srv != nil {
+		creationTime = srv.options.Clock.Now()
+	} else {
+		creationTime = time.Now()
 	}
 
 	return &connection{
+		lastActivity: creationTime.UnixNano(),
 		options:      options,
 		stateLock:    sync.RWMutex{},
 		isActive:     isActive,
@@ -81,10 +219,15 @@ func newConnection(
 		sessionLock:  sync.RWMutex{},
 		session:      nil,
 		info: ClientInfo{
-			time.Now(),
+			creationTime,
 			userAgent,
 			remoteAddr,
 		},
+		tags:        tags,
+		ctx:         ctx,
+		cancelCtx:   cancelCtx,
+		awaitCtx:    awaitCtx,
+		cancelAwait: cancelAwait,
 	}
 }
 
@@ -97,6 +240,48 @@ func (con *connection) IsActive() bool {
 
 }
 
+// fail closes the connection due to a panic while serving it
+func (con *connection) fail() {
+	con.stateLock.Lock()
+	con.failed = true
+	con.stateLock.Unlock()
+	con.Close()
+}
+
+// hasFailed returns true if the connection was closed
+// due to a panic while serving it
+func (con *connection) hasFailed() bool {
+	con.stateLock.RLock()
+	failed := con.failed
+	con.stateLock.RUnlock()
+	return failed
+}
+
+// SignalsPaused implements the Connection interface
+func (con *connection) SignalsPaused() bool {
+	con.stateLock.RLock()
+	paused := con.signalsPaused
+	con.stateLock.RUnlock()
+	return paused
+}
+
+// setSignalsPaused pauses or resumes non-critical signals
+func (con *connection) setSignalsPaused(paused bool) {
+	con.stateLock.Lock()
+	con.signalsPaused = paused
+	con.stateLock.Unlock()
+}
+
+// suppressSignal returns true if the signal of the given name
+// mustn't be sent due to signals being paused
+func (con *connection) suppressSignal(name string) bool {
+	if !con.SignalsPaused() {
+		return false
+	}
+	critical := con.srv.options.CriticalSignal
+	return critical == nil || !critical(name)
+}
+
 // registerTask increments the number of currently executed tasks
 func (con *connection) registerTask() {
 	con.stateLock.Lock()
@@ -122,6 +307,26 @@ func (con *connection) deregisterTask() {
 	}
 }
 
+// acquireRequestSlot increments the number of in-flight requests
+// and returns true, or returns false if the maximum number
+// of in-flight requests was already reached. 0 means unlimited
+func (con *connection) acquireRequestSlot(max uint) bool {
+	con.stateLock.Lock()
+	defer con.stateLock.Unlock()
+	if max > 0 && con.inFlightRequests >= max {
+		return false
+	}
+	con.inFlightRequests++
+	return true
+}
+
+// releaseRequestSlot decrements the number of in-flight requests
+func (con *connection) releaseRequestSlot() {
+	con.stateLock.Lock()
+	con.inFlightRequests--
+	con.stateLock.Unlock()
+}
+
 // setSession sets a new session for this client
 func (con *connection) setSession(newSess *Session) {
 	con.sessionLock.Lock()
@@ -139,6 +344,8 @@ func (con *connection) unlink() {
 	con.session = nil
 	con.sessionLock.Unlock()
 
+	con.clearSubscriptions()
+
 	// Close connection
 	con.sock.Close()
 }
@@ -148,17 +355,147 @@ func (con *connection) Info() ClientInfo {
 	return con.info
 }
 
+// Snapshot implements the Connection interface
+func (con *connection) Snapshot() ConnectionInfo {
+	info := ConnectionInfo{
+		RemoteAddr:     con.info.RemoteAddr,
+		UserAgent:      con.info.UserAgent,
+		ConnectionTime: con.info.ConnectionTime,
+		LastActivity:   con.LastActivity(),
+	}
+
+	con.stateLock.RLock()
+	info.InFlightRequests = con.inFlightRequests
+	con.stateLock.RUnlock()
+
+	con.sessionLock.RLock()
+	if con.session != nil {
+		info.SessionKey = con.session.Key
+		info.SessionCreation = con.session.Creation
+	}
+	con.sessionLock.RUnlock()
+
+	return info
+}
+
+// LastActivity implements the Connection interface
+func (con *connection) LastActivity() time.Time {
+	return time.Unix(0, atomic.LoadInt64(&con.lastActivity))
+}
+
+// touch updates the time of the last activity to the current time
+func (con *connection) touch() {
+	atomic.StoreInt64(&con.lastActivity, con.now().UnixNano())
+}
+
+// now returns the current time according to the clock of the server
+func (con *connection) now() time.Time {
+	if con.srv == nil {
+		return time.Now()
+	}
+	return con.srv.options.Clock.Now()
+}
+
+// UnderlyingConn implements the Connection interface
+func (con *connection) UnderlyingConn() interface{} {
+	provider, isProvider := con.sock.(UnderlyingConnProvider)
+	if !isProvider {
+		return nil
+	}
+	return provider.UnderlyingConn()
+}
+
+// OutboundQueueLen implements the Connection interface
+func (con *connection) OutboundQueueLen() int {
+	provider, isProvider := con.sock.(OutboundQueueLenProvider)
+	if !isProvider {
+		return 0
+	}
+	return provider.OutboundQueueLen()
+}
+
+// Header implements the Connection interface
+func (con *connection) Header(name string) string {
+	return con.header.Get(name)
+}
+
+// ConnectParam implements the Connection interface
+func (con *connection) ConnectParam(key string) string {
+	return con.connectParams.Get(key)
+}
+
 // Signal implements the Connection interface
 func (con *connection) Signal(name string, payload Payload) error {
-	return con.sock.Write(msg.NewSignalMessage(
+	if err := con.srv.options.NameValidator(name); err != nil {
+		return err
+	}
+
+	// Drop non-critical signals while signals are paused
+	if con.suppressSignal(name) {
+		return nil
+	}
+
+	contentType, err := PayloadContentType(payload)
+	if err != nil {
+		return err
+	}
+
+	// Transform the signal payload
+	encoding := con.srv.resolveEncoding(payload.Encoding())
+	data, err := con.srv.interceptOutbound(encoding, payload.Data())
+	if err != nil {
+		return err
+	}
+
+	if contentType != "" {
+		return con.write(msg.NewTypedSignalMessage(
+			name,
+			contentType,
+			data,
+			con.srv.options.NameValidator,
+		))
+	}
+
+	return con.write(msg.NewSignalMessage(
 		name,
-		payload.Encoding(),
-		payload.Data(),
+		encoding,
+		data,
+		con.srv.options.NameValidator,
 	))
 }
 
+// SendRaw implements the Connection interface
+func (con *connection) SendRaw(prebuilt []byte) error {
+	if !isPreparedSignal(prebuilt) {
+		return fmt.Errorf("Raw message doesn't represent a prepared signal")
+	}
+	if con.srv.interceptsPayloads() {
+		return fmt.Errorf(
+			"Can't send prepared signals when a payload interceptor is set",
+		)
+	}
+	if con.SignalsPaused() && con.suppressSignal(preparedSignalName(prebuilt)) {
+		return nil
+	}
+	return con.write(prebuilt)
+}
+
+// write writes the given message to the socket of the connection
+// or returns a DisconnectedErr if the connection was already closed
+func (con *connection) write(message []byte) error {
+	if con.sock == nil || !con.IsActive() {
+		return DisconnectedErr{
+			Cause: fmt.Errorf("Can't write to a closed connection"),
+		}
+	}
+	return con.sockWrite(message)
+}
+
 // CreateSession implements the Connection interface
-func (con *connection) CreateSession(attachment SessionInfo) error {
+func (con *connection) CreateSession(
+	ctx context.Context,
+	attachment SessionInfo,
+) error {
 	if !con.srv.sessionsEnabled {
 		return SessionsDisabledErr{}
 	}
@@ -171,6 +508,40 @@ func (con *connection) CreateSession(attachment SessionInfo) error {
 		}
 	}
 
+	if !con.allowSessionOperation() {
+		return SessionOperationThrottledErr{}
+	}
+
+	if con.srv.maxActiveSessionsReached() {
+		return MaxSessionsReachedErr{}
+	}
+
+	if con.srv.isShuttingDown() {
+		return ReqSrvShutdownErr{}
+	}
+
+	// Abort the creation when either the context is cancelled
+	// or the connection is closed
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	con.srv.spawn(func() {
+		select {
+		case <-con.ctx.Done():
+			cancel()
+		case <-ctx.Done():
+		}
+	})
+
+	if err := ctx.Err(); err != nil {
+		return SessionCreationCancelledErr{Cause: err}
+	}
+
+	// Generate the session key and make sure it can be sent to the client
+	key := con.srv.sessionKeyGen.Generate()
+	if err := verifyGeneratedSessionKey(key); err != nil {
+		return err
+	}
+
 	con.sessionLock.Lock()
 
 	// Abort if there's already another active session
@@ -183,31 +554,130 @@ func (con *connection) CreateSession(attachment SessionInfo) error {
 	}
 
 	// Create a new session
-	newSession := NewSession(attachment, con.srv.sessionKeyGen.Generate)
+	newSession := newSession(
+		con.srv.storedSessionInfo(attachment),
+		func() string { return key },
+		con.now(),
+	)
+
+	// Register the session
+	con.session = &newSession
+	if err := con.srv.sessionRegistry.register(con); err != nil {
+		con.session = nil
+		con.sessionLock.Unlock()
+		return err
+	}
 
 	// Try to notify about session creation
 	if err := con.notifySessionCreated(&newSession); err != nil {
+		con.srv.sessionRegistry.deregister(con)
+		con.session = nil
 		con.sessionLock.Unlock()
 		return fmt.Errorf(
 			"Couldn't notify client about the session creation: %s",
 			err,
 		)
 	}
-
-	// Register the session
-	con.session = &newSession
-
-	con.srv.sessionRegistry.register(con)
 	con.sessionLock.Unlock()
 
 	// Call session creation hook
-	if err := con.srv.sessionManager.OnSessionCreated(con); err != nil {
-		con.srv.errorLog.Printf("OnSessionCreated hook failed: %s", err)
+	err := con.srv.sessionManager.OnSessionCreated(ctx, con)
+	if err == nil {
+		con.srv.emitEvent(ServerEvent{
+			Type:       EventSessionCreated,
+			Connection: con,
+			SessionKey: newSession.Key,
+		})
+		return nil
+	}
+
+	// Roll back the session creation if the hook failed
+	con.abortSessionCreation(&newSession)
+	if ctxErr := ctx.Err(); ctxErr != nil {
+		return SessionCreationCancelledErr{Cause: ctxErr}
+	}
+	con.srv.errorLog.Printf("OnSessionCreated hook failed: %s", err)
+	return SessionCreationFailedErr{Cause: err}
+}
+
+// CreateEphemeralSession implements the Connection interface
+func (con *connection) CreateEphemeralSession(attachment SessionInfo) error {
+	if !con.srv.sessionsEnabled {
+		return SessionsDisabledErr{}
 	}
 
+	if !con.IsActive() {
+		return DisconnectedErr{
+			Cause: fmt.Errorf(
+				"Can't create session on disconnected connection",
+			),
+		}
+	}
+
+	if !con.allowSessionOperation() {
+		return SessionOperationThrottledErr{}
+	}
+
+	if con.srv.maxActiveSessionsReached() {
+		return MaxSessionsReachedErr{}
+	}
+
+	if con.srv.isShuttingDown() {
+		return ReqSrvShutdownErr{}
+	}
+
+	con.sessionLock.Lock()
+	defer con.sessionLock.Unlock()
+
+	// Abort if there's already another active session
+	if con.session != nil {
+		return fmt.Errorf(
+			"Another session (%s) on this client is already active",
+			con.session.Key,
+		)
+	}
+
+	newSession := newSession(
+		con.srv.storedSessionInfo(attachment),
+		generateSessionKey,
+		con.now(),
+	)
+	newSession.Ephemeral = true
+
+	con.session = &newSession
+	if err := con.srv.sessionRegistry.register(con); err != nil {
+		con.session = nil
+		return err
+	}
+
+	con.srv.emitEvent(ServerEvent{
+		Type:       EventSessionCreated,
+		Connection: con,
+		SessionKey: newSession.Key,
+	})
 	return nil
 }
 
+// abortSessionCreation removes the given session from the connection
+// unless it was already replaced and notifies the client
+// about the session destruction
+func (con *connection) abortSessionCreation(newSession *Session) {
+	con.sessionLock.Lock()
+	if con.session != newSession {
+		con.sessionLock.Unlock()
+		return
+	}
+	con.srv.sessionRegistry.deregister(con)
+	con.session = nil
+	con.sessionLock.Unlock()
+
+	if con.sock.IsConnected() {
+		if err := con.notifySessionClosed(); err != nil {
+			con.srv.warnLog.Printf("Aborting session creation: %s", err)
+		}
+	}
+}
+
 func (con *connection) notifySessionCreated(newSession *Session) error {
 	// Serialize session info
 	var sessionInfo map[string]interface{}
@@ -230,17 +700,20 @@ func (con *connection) notifySessionCreated(newSession *Session) error {
 
 	// Notify client about the session creation
 	message := make([]byte, 1+len(encoded))
-	message[0] = msg.MsgSessionCreated
+	message[0] = msg.MsgSessionCreatedUtf8
+	if con.srv.sessionEncoding() == EncodingBinary {
+		message[0] = msg.MsgSessionCreated
+	}
 
 	for i := 0; i < len(encoded); i++ {
 		message[1+i] = encoded[i]
 	}
-	return con.sock.Write(message)
+	return con.sockWrite(message)
 }
 
 func (con *connection) notifySessionClosed() error {
 	// Notify client about the session destruction
-	if err := con.sock.Write([]byte{msg.MsgSessionClosed}); err != nil {
+	if err := con.sockWrite([]byte{msg.MsgSessionClosed}); err != nil {
 		return fmt.Errorf(
 			"Couldn't notify client about the session destruction: %s",
 			err,
@@ -263,9 +736,22 @@ func (con *connection) CloseSession() error {
 
 	// Deregister session from active sessions registry
 	con.srv.sessionRegistry.deregister(con)
+	ephemeral := con.session.Ephemeral
+	sessionKey := con.session.Key
 	con.session = nil
 	con.sessionLock.Unlock()
 
+	con.srv.emitEvent(ServerEvent{
+		Type:       EventSessionClosed,
+		Connection: con,
+		SessionKey: sessionKey,
+	})
+
+	// Ephemeral sessions aren't synchronized to the client
+	if ephemeral {
+		return nil
+	}
+
 	return con.notifySessionClosed()
 }
 
@@ -331,6 +817,7 @@ func (con *connection) Close() {
 		return
 	}
 	con.isActive = false
+	con.cancelCtx()
 	if con.tasks < 1 {
 		unlink = true
 	}
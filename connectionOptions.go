@@ -11,6 +11,7 @@ const (
 type connectionOptions struct {
 	accept           bool
 	concurrencyLimit uint
+	tags             []string
 }
 
 // Accept implements the ConnectionOptions interface
@@ -23,11 +24,18 @@ func (conopts *connectionOptions) ConcurrencyLimit() uint {
 	return conopts.concurrencyLimit
 }
 
+// Tags implements the TaggedConnectionOptions interface
+func (conopts *connectionOptions) Tags() []string {
+	return conopts.tags
+}
+
 // AcceptConnection accepts an incoming connection using the given configuration
-func AcceptConnection(concurrencyLimit uint) ConnectionOptions {
+// attaching the given optional tags to it, see Connection.Tags
+func AcceptConnection(concurrencyLimit uint, tags ...string) ConnectionOptions {
 	return &connectionOptions{
 		accept:           true,
 		concurrencyLimit: concurrencyLimit,
+		tags:             tags,
 	}
 }
 
@@ -1,11 +1,13 @@
 package webwire
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"io/ioutil"
 	"os"
 	"path/filepath"
+	"strings"
 	"time"
 )
 
@@ -90,34 +92,71 @@ func NewDefaultSessionManager(sessFilesPath string) *DefaultSessionManager {
 	}
 }
 
-// filePath generates an absolute session file path given the session key
-func (mng *DefaultSessionManager) filePath(sessionKey string) string {
-	return filepath.Join(mng.path, sessionKey+".wwrsess")
+// filePath generates an absolute session file path given the session key.
+// Returns an error if the session key can't be used as a file name
+// because it's a relative path element or contains NUL bytes
+// or path separators, which restored session keys may contain
+func (mng *DefaultSessionManager) filePath(sessionKey string) (string, error) {
+	if sessionKey == "." || sessionKey == ".." ||
+		strings.ContainsAny(sessionKey, "\x00/\\") {
+		return "", fmt.Errorf(
+			"Session key %q can't be used as a session file name",
+			sessionKey,
+		)
+	}
+	return filepath.Join(mng.path, sessionKey+".wwrsess"), nil
 }
 
 // OnSessionCreated implements the session manager interface.
 // It writes the created session into a file using the session key as file name
-func (mng *DefaultSessionManager) OnSessionCreated(conn Connection) error {
+func (mng *DefaultSessionManager) OnSessionCreated(
+	ctx context.Context,
+	conn Connection,
+) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
 	sess := conn.Session()
 	sessFile := sessionFile{
 		Creation:   sess.Creation,
 		LastLookup: sess.LastLookup,
 		Info:       SessionInfoToVarMap(sess.Info),
 	}
-	return sessFile.Save(mng.filePath(conn.SessionKey()))
+	path, err := mng.filePath(conn.SessionKey())
+	if err != nil {
+		return err
+	}
+	return sessFile.Save(path)
+}
+
+// OnSessionInfoUpdated implements the SessionInfoUpdater interface.
+// It overwrites the session file with the patched session
+func (mng *DefaultSessionManager) OnSessionInfoUpdated(
+	ctx context.Context,
+	conn Connection,
+) error {
+	return mng.OnSessionCreated(ctx, conn)
 }
 
 // OnSessionLookup implements the session manager interface.
 // It searches the session file directory for the session file and loads it.
 // It also updates the file by updating the last lookup session field.
-func (mng *DefaultSessionManager) OnSessionLookup(key string) (
-	SessionLookupResult,
-	error,
-) {
-	path := mng.filePath(key)
+func (mng *DefaultSessionManager) OnSessionLookup(
+	ctx context.Context,
+	key string,
+) (SessionLookupResult, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	// Sessions with keys that can't be file names can't exist
+	path, err := mng.filePath(key)
+	if err != nil {
+		return nil, nil
+	}
 
 	// Lookup session file
-	_, err := os.Stat(path)
+	_, err = os.Stat(path)
 	if os.IsNotExist(err) {
 		return nil, nil
 	} else if err != nil {
@@ -142,7 +181,7 @@ func (mng *DefaultSessionManager) OnSessionLookup(key string) (
 		LastLookup: time.Now().UTC(),
 		Info:       file.Info,
 	}
-	if err := newSessionFile.Save(mng.filePath(key)); err != nil {
+	if err := newSessionFile.Save(path); err != nil {
 		return nil, fmt.Errorf(
 			"Couldn't update last lookup field, failed writing file: %s",
 			err,
@@ -159,7 +198,11 @@ func (mng *DefaultSessionManager) OnSessionLookup(key string) (
 // OnSessionClosed implements the session manager interface.
 // It closes the session by deleting the according session file
 func (mng *DefaultSessionManager) OnSessionClosed(sessionKey string) error {
-	if err := os.Remove(mng.filePath(sessionKey)); err != nil {
+	path, err := mng.filePath(sessionKey)
+	if err != nil {
+		return err
+	}
+	if err := os.Remove(path); err != nil {
 		return fmt.Errorf(
 			"Unexpected error during session destruction: %s",
 			err,
@@ -0,0 +1,33 @@
+package webwire
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// TestDefaultSessionManagerUnsafeKeys tests whether the default session
+// manager refuses session keys that can't be used as file names
+func TestDefaultSessionManagerUnsafeKeys(t *testing.T) {
+	dir, err := ioutil.TempDir("", "wwrsess")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	mng := NewDefaultSessionManager(dir)
+	for _, key := range []string{
+		".",
+		"..",
+		"../key",
+		"dir/key",
+		"dir\\key",
+		"key\x00",
+	} {
+		result, err := mng.OnSessionLookup(context.Background(), key)
+		require.NoError(t, err)
+		require.Nil(t, result)
+		require.Error(t, mng.OnSessionClosed(key))
+	}
+}
@@ -0,0 +1,80 @@
+package webwire
+
+// DisconnectReason represents the reason a client connection was closed for
+type DisconnectReason int
+
+const (
+	// DisconnectReadError represents a connection closed
+	// due to an unexpected error while reading from the socket
+	DisconnectReadError DisconnectReason = iota
+
+	// DisconnectNormalClose represents a connection closed normally
+	// by the client
+	DisconnectNormalClose
+
+	// DisconnectAbnormalClose represents a connection closed abnormally,
+	// for example when the network connection to the client is lost
+	DisconnectAbnormalClose
+
+	// DisconnectServerInitiated represents a connection closed by the server
+	DisconnectServerInitiated
+
+	// DisconnectIdleTimeout represents a connection closed due to
+	// the client not responding within the read or heartbeat timeout
+	DisconnectIdleTimeout
+
+	// DisconnectProtocolError represents a connection closed due to
+	// the client sending a malformed WebSocket frame
+	DisconnectProtocolError
+
+	// DisconnectMessageTooBig represents a connection closed due to
+	// the client sending a message exceeding ServerOptions.MaxMessageSize
+	DisconnectMessageTooBig
+
+	// DisconnectInternalError represents a connection closed due to
+	// a panic while serving the client, for example in a hook
+	DisconnectInternalError
+
+	// DisconnectSlowConsumer represents a connection closed due to
+	// ServerOptions.MaxPendingOutboundBytes being exceeded
+	// while the client had the most outbound bytes pending
+	DisconnectSlowConsumer
+)
+
+// String stringifies the disconnect reason
+func (reason DisconnectReason) String() string {
+	switch reason {
+	case DisconnectReadError:
+		return "read error"
+	case DisconnectNormalClose:
+		return "normal close"
+	case DisconnectAbnormalClose:
+		return "abnormal close"
+	case DisconnectServerInitiated:
+		return "server initiated"
+	case DisconnectIdleTimeout:
+		return "idle timeout"
+	case DisconnectProtocolError:
+		return "protocol error"
+	case DisconnectMessageTooBig:
+		return "message too big"
+	case DisconnectInternalError:
+		return "internal error"
+	case DisconnectSlowConsumer:
+		return "slow consumer"
+	}
+	return ""
+}
+
+// CloseCode returns the WebSocket close code (RFC 6455, section 7.4.1)
+// the client is told the connection was closed with,
+// or 0 if no close frame is to be sent
+func (reason DisconnectReason) CloseCode() int {
+	switch reason {
+	case DisconnectProtocolError:
+		return 1002
+	case DisconnectMessageTooBig:
+		return 1009
+	}
+	return 0
+}
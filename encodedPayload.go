@@ -1,6 +1,11 @@
 package webwire
 
-import pld "github.com/qbeon/webwire-go/payload"
+import (
+	"fmt"
+
+	msg "github.com/qbeon/webwire-go/message"
+	pld "github.com/qbeon/webwire-go/payload"
+)
 
 // PayloadEncoding represents the type of encoding of the message payload
 type PayloadEncoding = pld.Encoding
@@ -14,8 +19,23 @@ const (
 
 	// EncodingUtf16 represents UTF16 encoding
 	EncodingUtf16 = pld.Utf16
+
+	// EncodingDefault represents an unspecified encoding resolved to
+	// ServerOptions.DefaultEncoding when sent by the server
+	EncodingDefault = pld.Default
 )
 
+// SupportedEncodings returns the payload encodings supported
+// by this build of the library. EncodingDefault isn't included
+// since it's resolved to one of the supported encodings when sent
+func SupportedEncodings() []PayloadEncoding {
+	return []PayloadEncoding{
+		EncodingBinary,
+		EncodingUtf8,
+		EncodingUtf16,
+	}
+}
+
 // EncodedPayload represents an encoded message payload
 // and implements the WebWire payload interface
 type EncodedPayload struct {
@@ -37,6 +57,26 @@ func (pld *EncodedPayload) Utf8() (string, error) {
 	return pld.Payload.Utf8()
 }
 
+// ContentType implements the ContentTyped interface
+func (pld *EncodedPayload) ContentType() string {
+	return pld.Payload.ContentType
+}
+
+// Len returns the length of the payload data in bytes
+func (pld *EncodedPayload) Len() int {
+	return pld.Payload.Len()
+}
+
+// IsEmpty returns true if the payload carries no data
+func (pld *EncodedPayload) IsEmpty() bool {
+	return pld.Payload.IsEmpty()
+}
+
+// Hash returns a stable hash of the payload, see pld.Payload.Hash
+func (pld *EncodedPayload) Hash() uint64 {
+	return pld.Payload.Hash()
+}
+
 // NewPayload creates a new WebWire message payload
 func NewPayload(encoding PayloadEncoding, data []byte) Payload {
 	return &EncodedPayload{
@@ -46,3 +86,44 @@ func NewPayload(encoding PayloadEncoding, data []byte) Payload {
 		},
 	}
 }
+
+// NewTypedPayload creates a new binary WebWire message payload
+// of the given application-level content type, such as
+// "application/protobuf" or "image/png", for the receiver to dispatch it
+// to the right decoder. The content type must consist of 1 to 255
+// printable ASCII characters
+func NewTypedPayload(contentType string, data []byte) Payload {
+	return &EncodedPayload{
+		Payload: pld.Payload{
+			Encoding:    EncodingBinary,
+			Data:        data,
+			ContentType: contentType,
+		},
+	}
+}
+
+// PayloadContentType returns the content type of the given payload
+// if it implements the ContentTyped interface, otherwise returns
+// an empty string. Returns an error if the content type is invalid
+// or if the payload of a content type is UTF8 or UTF16 encoded
+func PayloadContentType(payload Payload) (string, error) {
+	typed, isTyped := payload.(ContentTyped)
+	if !isTyped {
+		return "", nil
+	}
+	contentType := typed.ContentType()
+	if contentType == "" {
+		return "", nil
+	}
+	if err := msg.ValidateContentType(contentType); err != nil {
+		return "", err
+	}
+	if encoding := payload.Encoding(); encoding == EncodingUtf8 ||
+		encoding == EncodingUtf16 {
+		return "", fmt.Errorf(
+			"Payloads of a content type must be binary encoded, got %s",
+			encoding,
+		)
+	}
+	return contentType, nil
+}
@@ -2,11 +2,14 @@ package webwire
 
 import (
 	"fmt"
+	"time"
 )
 
 // ConnIncompErr represents a connection error type indicating that the server
 // requires an incompatible version of the protocol
 // and can't therefore be connected to.
+//
+// Deprecated: the client returns a ProtocolVersionMismatchErr instead
 type ConnIncompErr struct {
 	requiredVersion  string
 	supportedVersion string
@@ -29,6 +32,47 @@ func NewConnIncompErr(requiredVersion, supportedVersion string) ConnIncompErr {
 	}
 }
 
+// ProtocolVersionMismatchErr represents a connection error type indicating
+// that the protocol version implemented by the server isn't supported
+// by the client and the server can't therefore be connected to
+type ProtocolVersionMismatchErr struct {
+	// ServerVersion is the protocol version implemented by the server
+	ServerVersion string
+
+	// ClientVersion is the protocol version supported by the client
+	ClientVersion string
+}
+
+func (err ProtocolVersionMismatchErr) Error() string {
+	return fmt.Sprintf(
+		"Unsupported protocol version: %s (%s is supported by this client)",
+		err.ServerVersion,
+		err.ClientVersion,
+	)
+}
+
+// NewProtocolVersionMismatchErr constructs and returns a new protocol version
+// mismatch error based on the server and client protocol versions
+func NewProtocolVersionMismatchErr(
+	serverVersion,
+	clientVersion string,
+) ProtocolVersionMismatchErr {
+	return ProtocolVersionMismatchErr{
+		ServerVersion: serverVersion,
+		ClientVersion: clientVersion,
+	}
+}
+
+// HeadlessServerErr represents an error type indicating that
+// a headless server was attempted to be run while it's supposed to be
+// hosted by an external HTTP server through its ServeHTTP method
+type HeadlessServerErr struct{}
+
+func (err HeadlessServerErr) Error() string {
+	return "Headless servers can't be run, " +
+		"host them by an external HTTP server instead"
+}
+
 // ReqTransErr represents a connection error type
 // indicating that the dialing failed.
 type ReqTransErr struct {
@@ -56,6 +100,30 @@ func (err ReqSrvShutdownErr) Error() string {
 	return "Server is currently being shut down and won't process the request"
 }
 
+// ReqQueueFullErr represents a request error type indicating that the request
+// couldn't be queued while the client is reconnecting
+// because the request queue is full
+type ReqQueueFullErr struct{}
+
+func (err ReqQueueFullErr) Error() string {
+	return "Request queue is full"
+}
+
+// ReplyTooLargeErr represents a request error type indicating that
+// the streamed reply to the request exceeded the maximum reply size
+// accepted by the client, see client.Options.MaxReplySize
+type ReplyTooLargeErr struct {
+	// MaxSize is the maximum reply size in bytes
+	MaxSize int
+}
+
+func (err ReplyTooLargeErr) Error() string {
+	return fmt.Sprintf(
+		"Reply exceeds the maximum size of %d bytes",
+		err.MaxSize,
+	)
+}
+
 // ReqInternalErr represents a request error type
 // indicating that the request failed due to an internal server-side error
 type ReqInternalErr struct{}
@@ -96,10 +164,19 @@ func (err DeadlineExceededErr) Error() string {
 }
 
 // ReqErr represents an error returned in case of
-// a request that couldn't be processed
+// a request that couldn't be processed.
+// The Code must consist of 1 to 255 printable ASCII characters
+// while the Message is transmitted in the given Encoding
+// and may thus contain any Unicode characters (e.g. localized messages)
 type ReqErr struct {
 	Code    string
 	Message string
+
+	// Encoding defines the encoding the Message is transmitted in.
+	// The Message is transmitted UTF16 encoded if it's EncodingUtf16
+	// and UTF8 encoded otherwise. Clients decode the Message
+	// and leave the Encoding of received errors unset
+	Encoding PayloadEncoding
 }
 
 func (err ReqErr) Error() string {
@@ -114,6 +191,146 @@ func (err SessionsDisabledErr) Error() string {
 	return "Sessions are disabled for this server"
 }
 
+// FeatureDisabledErr represents an error type indicating that the server
+// has the type of the sent message disabled
+type FeatureDisabledErr struct{}
+
+func (err FeatureDisabledErr) Error() string {
+	return "The message type is disabled for this server"
+}
+
+// TooManyRequestsErr represents a request error type indicating that
+// the maximum number of in-flight requests per connection was reached.
+// It can also be returned from the OnRequest hook to reject requests
+// exceeding a rate limit, see NewTooManyRequestsErr
+type TooManyRequestsErr struct {
+	retryAfter time.Duration
+}
+
+// NewTooManyRequestsErr constructs a new TooManyRequestsErr error
+// carrying a hint about when the request may be retried
+func NewTooManyRequestsErr(retryAfter time.Duration) TooManyRequestsErr {
+	return TooManyRequestsErr{
+		retryAfter: retryAfter,
+	}
+}
+
+func (err TooManyRequestsErr) Error() string {
+	return "Reached maximum number of in-flight requests per connection"
+}
+
+// RetryAfter returns the duration after which the request may be retried,
+// it's 0 if the server didn't provide a hint
+func (err TooManyRequestsErr) RetryAfter() time.Duration {
+	return err.retryAfter
+}
+
+// UnauthorizedErr represents a request error type indicating that
+// the request requires a session but the client has none,
+// see ServerOptions.PreAuthRequest
+type UnauthorizedErr struct{}
+
+func (err UnauthorizedErr) Error() string {
+	return "Request requires a session"
+}
+
+// MethodNotFoundErr represents a request error type indicating that
+// the name of the request isn't allowed by the server,
+// see ServerOptions.AllowedNames
+type MethodNotFoundErr struct{}
+
+func (err MethodNotFoundErr) Error() string {
+	return "Request name not allowed"
+}
+
+// ServiceUnavailableErr represents a request error type indicating that
+// the request was rejected because a downstream dependency of the handler
+// is failing, see CircuitBreaker
+type ServiceUnavailableErr struct {
+	retryAfter time.Duration
+}
+
+// NewServiceUnavailableErr constructs a new ServiceUnavailableErr error
+// carrying a hint about when the request may be retried
+func NewServiceUnavailableErr(
+	retryAfter time.Duration,
+) ServiceUnavailableErr {
+	return ServiceUnavailableErr{
+		retryAfter: retryAfter,
+	}
+}
+
+func (err ServiceUnavailableErr) Error() string {
+	return "Service unavailable"
+}
+
+// RetryAfter returns the duration after which the request may be retried,
+// it's 0 if the server didn't provide a hint
+func (err ServiceUnavailableErr) RetryAfter() time.Duration {
+	return err.retryAfter
+}
+
+// RequestTimeoutErr represents a request error type indicating that
+// the request handler didn't reply within the request timeout
+// defined on the server, see Server.SetRequestTimeout
+type RequestTimeoutErr struct{}
+
+func (err RequestTimeoutErr) Error() string {
+	return "Request timed out"
+}
+
+// SessionOperationThrottledErr represents an error type indicating that
+// a session operation was rejected because the connection exceeded
+// ServerOptions.MaxSessionOperations
+type SessionOperationThrottledErr struct{}
+
+func (err SessionOperationThrottledErr) Error() string {
+	return "Too many session operations"
+}
+
+// OutboundLimitExceededErr represents an error type indicating that
+// a message wasn't sent because ServerOptions.MaxPendingOutboundBytes
+// was exceeded, see OutboundLimitRejectWrites
+type OutboundLimitExceededErr struct{}
+
+func (err OutboundLimitExceededErr) Error() string {
+	return "Too many outbound bytes pending"
+}
+
+// SessionCreationCancelledErr represents an error type indicating that
+// the session creation was aborted due to either the context being cancelled
+// or the connection being closed during the creation
+type SessionCreationCancelledErr struct {
+	Cause error
+}
+
+func (err SessionCreationCancelledErr) Error() string {
+	if err.Cause == nil {
+		return "Session creation cancelled"
+	}
+	return fmt.Sprintf("Session creation cancelled: %s", err.Cause)
+}
+
+// SessionCreationFailedErr represents an error type indicating that
+// the session creation was rolled back due to the OnSessionCreated hook
+// of the session manager failing
+type SessionCreationFailedErr struct {
+	Cause error
+}
+
+func (err SessionCreationFailedErr) Error() string {
+	return fmt.Sprintf("Session creation failed: %s", err.Cause)
+}
+
+// MaxSessionsReachedErr represents a session creation error type
+// indicating that the maximum number of active sessions was reached,
+// see ServerOptions.MaxActiveSessions
+type MaxSessionsReachedErr struct{}
+
+func (err MaxSessionsReachedErr) Error() string {
+	return "Reached maximum number of active sessions"
+}
+
 // SessNotFoundErr represents a session restoration error type
 // indicating that the server didn't find the session to be restored
 type SessNotFoundErr struct{}
@@ -152,6 +369,16 @@ func (err DisconnectedErr) Error() string {
 	return err.Cause.Error()
 }
 
+// ConnectionLostErr represents a request error type indicating that
+// the connection was lost after the request was sent but before its reply
+// was received. The request may or may not have been processed
+// by the server
+type ConnectionLostErr struct{}
+
+func (err ConnectionLostErr) Error() string {
+	return "Connection lost before the reply was received"
+}
+
 // ProtocolErr represents an error type
 // indicating an error in the protocol implementation
 type ProtocolErr struct {
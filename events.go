@@ -0,0 +1,82 @@
+package webwire
+
+import "time"
+
+// ServerEventType represents the type of a server event
+type ServerEventType int
+
+const (
+	// EventClientConnected represents the event of a client connection
+	// being established, it's emitted before the OnClientConnected hook
+	// is invoked
+	EventClientConnected ServerEventType = iota
+
+	// EventClientDisconnected represents the event of a client connection
+	// being closed, the reason is carried in ServerEvent.DisconnectReason
+	EventClientDisconnected
+
+	// EventSessionCreated represents the event of a session being created
+	// on a connection, the session key is carried in ServerEvent.SessionKey
+	EventSessionCreated
+
+	// EventSessionClosed represents the event of a session being closed
+	// on a connection either by the server or by the client,
+	// the session key is carried in ServerEvent.SessionKey
+	EventSessionClosed
+
+	// EventRequestFailed represents the event of a request being replied to
+	// with an error, the request name is carried in ServerEvent.Name
+	// and the error in ServerEvent.Err which is nil for internal errors
+	// not caused by an error returned from a hook
+	EventRequestFailed
+)
+
+// String stringifies the server event type
+func (tp ServerEventType) String() string {
+	switch tp {
+	case EventClientConnected:
+		return "ClientConnected"
+	case EventClientDisconnected:
+		return "ClientDisconnected"
+	case EventSessionCreated:
+		return "SessionCreated"
+	case EventSessionClosed:
+		return "SessionClosed"
+	case EventRequestFailed:
+		return "RequestFailed"
+	}
+	return ""
+}
+
+// ServerEvent represents a connection lifecycle event, see Server.Events
+type ServerEvent struct {
+	Type       ServerEventType
+	Time       time.Time
+	Connection Connection
+
+	// DisconnectReason is set for EventClientDisconnected events only
+	DisconnectReason DisconnectReason
+
+	// SessionKey is set for EventSessionCreated
+	// and EventSessionClosed events only
+	SessionKey string
+
+	// Name and Err are set for EventRequestFailed events only
+	Name string
+	Err  error
+}
+
+// Events implements the Server interface
+func (srv *server) Events() <-chan ServerEvent {
+	return srv.events
+}
+
+// emitEvent sends the given event to the event stream
+// dropping it if the buffer of the stream is full
+func (srv *server) emitEvent(event ServerEvent) {
+	event.Time = srv.options.Clock.Now()
+	select {
+	case srv.events <- event:
+	default:
+	}
+}
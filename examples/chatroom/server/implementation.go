@@ -71,7 +71,7 @@ func (srv *ChatRoomServer) broadcastMessage(name string, msg string) {
 // and either rejects the authentication or confirms it eventually
 // creating a session and returning the session key
 func (srv *ChatRoomServer) handleAuth(
-	_ context.Context,
+	ctx context.Context,
 	client wwr.Connection,
 	message wwr.Message,
 ) (wwr.Payload, error) {
@@ -113,7 +113,7 @@ func (srv *ChatRoomServer) handleAuth(
 	}
 
 	// Finally create a new session
-	if err := client.CreateSession(&shared.SessionInfo{
+	if err := client.CreateSession(ctx, &shared.SessionInfo{
 		Username: credentials.Name,
 	}); err != nil {
 		return nil, fmt.Errorf("Couldn't create session: %s", err)
@@ -236,8 +236,15 @@ func (srv *ChatRoomServer) OnClientConnected(newClient wwr.Connection) {
 
 // OnClientDisconnected implements the webwire.ServerImplementation interface.
 // Deregisters gone clients
-func (srv *ChatRoomServer) OnClientDisconnected(client wwr.Connection) {
-	log.Printf("Client %s disconnected", client.Info().RemoteAddr)
+func (srv *ChatRoomServer) OnClientDisconnected(
+	client wwr.Connection,
+	reason wwr.DisconnectReason,
+) {
+	log.Printf(
+		"Client %s disconnected (%s)",
+		client.Info().RemoteAddr,
+		reason,
+	)
 	srv.lock.Lock()
 	defer srv.lock.Unlock()
 	delete(srv.connected, client)
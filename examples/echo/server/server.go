@@ -38,7 +38,11 @@ func (srv *EchoServer) OnClientConnected(client wwr.Connection) {}
 
 // OnClientDisconnected implements the webwire.ServerImplementation interface
 // Does nothing, not needed in this example
-func (srv *EchoServer) OnClientDisconnected(client wwr.Connection) {}
+func (srv *EchoServer) OnClientDisconnected(
+	_ wwr.Connection,
+	_ wwr.DisconnectReason,
+) {
+}
 
 // BeforeUpgrade implements the webwire.ServerImplementation interface.
 // Must return true to ensure incoming connections are accepted
@@ -80,7 +80,10 @@ func (srv *PubSubServer) OnClientConnected(client wwr.Connection) {
 
 // OnClientDisconnected implements the webwire.ServerImplementation interface
 // Deregisters a gone client
-func (srv *PubSubServer) OnClientDisconnected(client wwr.Connection) {
+func (srv *PubSubServer) OnClientDisconnected(
+	client wwr.Connection,
+	_ wwr.DisconnectReason,
+) {
 	srv.mapLock.Lock()
 	delete(srv.connectedClients, client)
 	srv.mapLock.Unlock()
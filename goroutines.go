@@ -0,0 +1,58 @@
+package webwire
+
+import "sync/atomic"
+
+// ActiveGoroutines implements the Server interface
+func (srv *server) ActiveGoroutines() int {
+	return int(atomic.LoadInt64(&srv.activeGoroutines))
+}
+
+// spawn calls the given function in a new goroutine
+// tracked by ActiveGoroutines
+func (srv *server) spawn(fn func()) {
+	atomic.AddInt64(&srv.activeGoroutines, 1)
+	go func() {
+		defer atomic.AddInt64(&srv.activeGoroutines, -1)
+		fn()
+	}()
+}
+
+// awaitHandlerCapacity blocks the read loop of the given connection
+// while the number of goroutines handling incoming messages reached
+// ServerOptions.MaxGoroutines or while there's no free worker slot
+// if the worker pool is bounded. Returns an error if the connection
+// is closed or the server is shut down in the meantime.
+// The acquired capacity is released by spawnHandler
+func (srv *server) awaitHandlerCapacity(con *connection) error {
+	if srv.goroutineSlots != nil {
+		if err := srv.goroutineSlots.Acquire(con.awaitCtx, 1); err != nil {
+			return err
+		}
+	}
+	if srv.workerSlots != nil {
+		if err := srv.workerSlots.Acquire(con.awaitCtx, 1); err != nil {
+			if srv.goroutineSlots != nil {
+				srv.goroutineSlots.Release(1)
+			}
+			return err
+		}
+	}
+	return nil
+}
+
+// spawnHandler calls the given function handling an incoming message
+// in a new goroutine releasing the capacity acquired
+// by awaitHandlerCapacity when it returns
+func (srv *server) spawnHandler(fn func()) {
+	srv.spawn(func() {
+		defer func() {
+			if srv.workerSlots != nil {
+				srv.workerSlots.Release(1)
+			}
+			if srv.goroutineSlots != nil {
+				srv.goroutineSlots.Release(1)
+			}
+		}()
+		fn()
+	})
+}
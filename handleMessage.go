@@ -2,17 +2,43 @@ package webwire
 
 import (
 	"context"
+	"encoding/binary"
+	"fmt"
+	"time"
+	"unicode/utf16"
 
 	msg "github.com/qbeon/webwire-go/message"
 )
 
 // handleMessage handles incoming messages
 func (srv *server) handleMessage(con *connection, message []byte) {
-	// Parse message
 	var parsedMessage msg.Message
+
+	// Close the connection if handling the message panics
+	// instead of crashing the server
+	defer func() {
+		if recovered := recover(); recovered != nil {
+			srv.errorLog.Printf(
+				"Handling message %x of client %v panicked: %v",
+				parsedMessage.Identifier,
+				con.Info().RemoteAddr,
+				recovered,
+			)
+			if parsedMessage.RequiresReply() && !parsedMessage.Replied() {
+				srv.failMsg(con, &parsedMessage, fmt.Errorf(
+					"Request handler panicked: %v",
+					recovered,
+				))
+			}
+			con.fail()
+		}
+	}()
+
+	// Parse message
 	msgTypeParsed, parserErr := parsedMessage.Parse(message)
 	if !msgTypeParsed {
 		// Couldn't determine message type, drop message
+		srv.handleUnknownMessageType(con, message)
 		return
 	} else if parserErr != nil {
 		// Couldn't parse message, protocol error
@@ -24,6 +50,101 @@ func (srv *server) handleMessage(con *connection, message []byte) {
 		return
 	}
 
+	// Reject semantically invalid messages
+	if err := parsedMessage.Validate(); err != nil {
+		srv.warnLog.Println("Invalid message:", err)
+		if !parsedMessage.RequiresReply() {
+			return
+		}
+		if _, isInvalidKey := err.(msg.InvalidSessionKeyErr); isInvalidKey {
+			// No session can be identified by an invalid key
+			srv.failMsg(con, &parsedMessage, SessNotFoundErr{})
+			return
+		}
+		srv.failMsg(con, &parsedMessage, ProtocolErr{})
+		return
+	}
+
+	// Reject requests and signals of names exceeding the maximum length,
+	// idempotency keys don't count towards the length
+	if len(srv.messageName(&parsedMessage)) > srv.options.MaxNameLength {
+		srv.warnLog.Printf(
+			"Rejected message of name exceeding %d bytes: %q",
+			srv.options.MaxNameLength,
+			parsedMessage.Name,
+		)
+		if !parsedMessage.RequiresReply() {
+			return
+		}
+		srv.failMsg(con, &parsedMessage, ProtocolErr{})
+		return
+	}
+
+	// Reject requests and signals of names not allowed
+	if !srv.nameAllowed(&parsedMessage) {
+		srv.warnLog.Printf(
+			"Rejected message of unknown name: %q",
+			parsedMessage.Name,
+		)
+		if !parsedMessage.RequiresReply() {
+			return
+		}
+		srv.failMsg(con, &parsedMessage, MethodNotFoundErr{})
+		return
+	}
+
+	// Reject messages of disabled types
+	if !srv.messageTypeEnabled(parsedMessage.Type) {
+		if !parsedMessage.RequiresReply() {
+			srv.warnLog.Printf(
+				"Dropped message of disabled type: %d",
+				parsedMessage.Type,
+			)
+			return
+		}
+		srv.failMsg(con, &parsedMessage, FeatureDisabledErr{})
+		return
+	}
+
+	// Transform the payload of incoming requests and signals
+	if err := srv.interceptInbound(&parsedMessage); err != nil {
+		srv.warnLog.Println("Payload interception failed:", err)
+		if !parsedMessage.RequiresReply() {
+			return
+		}
+		srv.failMsg(con, &parsedMessage, ProtocolErr{})
+		return
+	}
+
+	// Let the inspection hook veto the message
+	if srv.options.OnMessage != nil {
+		if err := srv.options.OnMessage(
+			con,
+			NewMessageWrapper(&parsedMessage),
+		); err != nil {
+			srv.warnLog.Println("Message rejected by OnMessage hook:", err)
+			if !parsedMessage.RequiresReply() {
+				return
+			}
+			srv.failMsg(con, &parsedMessage, err)
+			return
+		}
+	}
+
+	// Handle signal flow control and acknowledgement messages
+	// without registering a handler
+	switch parsedMessage.Type {
+	case msg.MsgPauseSignals:
+		con.setSignalsPaused(true)
+		return
+	case msg.MsgResumeSignals:
+		con.setSignalsPaused(false)
+		return
+	case msg.MsgSignalAck:
+		con.acknowledgeSignal(parsedMessage.Identifier)
+		return
+	}
+
 	// Deregister the handler only if a handler was registered
 	if srv.registerHandler(con, &parsedMessage) {
 		defer srv.deregisterHandler(con)
@@ -35,6 +156,8 @@ func (srv *server) handleMessage(con *connection, message []byte) {
 	case msg.MsgSignalUtf8:
 		fallthrough
 	case msg.MsgSignalUtf16:
+		fallthrough
+	case msg.MsgSignalTyped:
 		srv.handleSignal(con, &parsedMessage)
 
 	case msg.MsgRequestBinary:
@@ -42,6 +165,8 @@ func (srv *server) handleMessage(con *connection, message []byte) {
 	case msg.MsgRequestUtf8:
 		fallthrough
 	case msg.MsgRequestUtf16:
+		fallthrough
+	case msg.MsgRequestTyped:
 		srv.handleRequest(con, &parsedMessage)
 
 	case msg.MsgRestoreSession:
@@ -51,6 +176,49 @@ func (srv *server) handleMessage(con *connection, message []byte) {
 	}
 }
 
+// nameAllowed returns false if the given message is a request or a signal
+// of a name not listed in ServerOptions.AllowedNames, otherwise returns true
+func (srv *server) nameAllowed(message *msg.Message) bool {
+	if srv.allowedNames == nil {
+		return true
+	}
+	switch message.Type {
+	case msg.MsgSignalBinary, msg.MsgSignalUtf8, msg.MsgSignalUtf16,
+		msg.MsgSignalTyped, msg.MsgRequestBinary, msg.MsgRequestUtf8,
+		msg.MsgRequestUtf16, msg.MsgRequestTyped:
+		_, allowed := srv.allowedNames[srv.messageName(message)]
+		return allowed
+	}
+	return true
+}
+
+// messageName returns the name of the given message without
+// the idempotency key if it's a request and an idempotency store
+// is configured. The names of signals are returned as is
+// since they never carry an idempotency key
+func (srv *server) messageName(message *msg.Message) string {
+	if srv.options.IdempotencyStore == nil {
+		return message.Name
+	}
+	switch message.Type {
+	case msg.MsgRequestBinary, msg.MsgRequestUtf8,
+		msg.MsgRequestUtf16, msg.MsgRequestTyped:
+		name, _ := splitIdempotencyKey(message.Name)
+		return name
+	}
+	return message.Name
+}
+
+// messageTypeEnabled returns true if messages of the given type
+// are enabled for this server, otherwise returns false
+func (srv *server) messageTypeEnabled(msgType byte) bool {
+	if srv.options.EnabledMessageTypes == nil {
+		return true
+	}
+	_, enabled := srv.options.EnabledMessageTypes[msgType]
+	return enabled
+}
+
 // registerHandler increments the number of currently executed handlers
 // for this particular client.
 // It blocks if the current number of max concurrent handlers was reached
@@ -108,6 +276,22 @@ func (srv *server) deregisterHandler(con *connection) {
 	}
 }
 
+// beginReply marks the message as replied and returns true
+// if the reply is to be sent. Returns false if the message
+// was already replied to, which is logged as a warning,
+// or if the client is disconnected, in which case the reply
+// is silently dropped
+func (srv *server) beginReply(con *connection, message *msg.Message) bool {
+	if !message.MarkReplied() {
+		srv.warnLog.Printf(
+			"Ignoring redundant reply to message %x",
+			message.Identifier,
+		)
+		return false
+	}
+	return con.sock.IsConnected()
+}
+
 // fulfillMsg fulfills the message sending the reply
 func (srv *server) fulfillMsg(
 	con *connection,
@@ -115,8 +299,12 @@ func (srv *server) fulfillMsg(
 	replyPayloadEncoding PayloadEncoding,
 	replyPayloadData []byte,
 ) {
+	if !srv.beginReply(con, message) {
+		return
+	}
+
 	// Send reply
-	if err := con.sock.Write(
+	if err := con.sockWrite(
 		msg.NewReplyMessage(
 			message.Identifier,
 			replyPayloadEncoding,
@@ -127,6 +315,30 @@ func (srv *server) fulfillMsg(
 	}
 }
 
+// fulfillMsgTyped fulfills the message sending a reply
+// of the given content type
+func (srv *server) fulfillMsgTyped(
+	con *connection,
+	message *msg.Message,
+	contentType string,
+	replyPayloadData []byte,
+) {
+	if !srv.beginReply(con, message) {
+		return
+	}
+
+	// Send reply
+	if err := con.sockWrite(
+		msg.NewTypedReplyMessage(
+			message.Identifier,
+			contentType,
+			replyPayloadData,
+		),
+	); err != nil {
+		srv.errorLog.Println("Writing failed:", err)
+	}
+}
+
 // failMsg fails the message returning an error reply
 func (srv *server) failMsg(
 	con *connection,
@@ -139,20 +351,23 @@ func (srv *server) failMsg(
 		return
 	}
 
+	if !srv.beginReply(con, message) {
+		return
+	}
+
+	srv.emitEvent(ServerEvent{
+		Type:       EventRequestFailed,
+		Connection: con,
+		Name:       message.Name,
+		Err:        reqErr,
+	})
+
 	var replyMsg []byte
 	switch err := reqErr.(type) {
 	case ReqErr:
-		replyMsg = msg.NewErrorReplyMessage(
-			message.Identifier,
-			err.Code,
-			err.Message,
-		)
+		replyMsg = newErrorReply(message.Identifier, err)
 	case *ReqErr:
-		replyMsg = msg.NewErrorReplyMessage(
-			message.Identifier,
-			err.Code,
-			err.Message,
-		)
+		replyMsg = newErrorReply(message.Identifier, *err)
 	case MaxSessConnsReachedErr:
 		replyMsg = msg.NewSpecialRequestReplyMessage(
 			msg.MsgMaxSessConnsReached,
@@ -173,6 +388,48 @@ func (srv *server) failMsg(
 			msg.MsgReplyProtocolError,
 			message.Identifier,
 		)
+	case FeatureDisabledErr:
+		replyMsg = msg.NewSpecialRequestReplyMessage(
+			msg.MsgFeatureDisabled,
+			message.Identifier,
+		)
+	case TooManyRequestsErr:
+		replyMsg = newRetryAfterReply(
+			msg.MsgTooManyRequests,
+			message.Identifier,
+			err.RetryAfter(),
+		)
+	case UnauthorizedErr:
+		replyMsg = msg.NewSpecialRequestReplyMessage(
+			msg.MsgUnauthorized,
+			message.Identifier,
+		)
+	case MethodNotFoundErr:
+		replyMsg = msg.NewSpecialRequestReplyMessage(
+			msg.MsgMethodNotFound,
+			message.Identifier,
+		)
+	case ServiceUnavailableErr:
+		replyMsg = newRetryAfterReply(
+			msg.MsgServiceUnavailable,
+			message.Identifier,
+			err.RetryAfter(),
+		)
+	case RequestTimeoutErr:
+		replyMsg = msg.NewSpecialRequestReplyMessage(
+			msg.MsgRequestTimeout,
+			message.Identifier,
+		)
+	case SessionOperationThrottledErr:
+		replyMsg = msg.NewSpecialRequestReplyMessage(
+			msg.MsgSessionOperationThrottled,
+			message.Identifier,
+		)
+	case ReqSrvShutdownErr:
+		replyMsg = msg.NewSpecialRequestReplyMessage(
+			msg.MsgReplyShutdown,
+			message.Identifier,
+		)
 	default:
 		replyMsg = msg.NewSpecialRequestReplyMessage(
 			msg.MsgInternalError,
@@ -181,17 +438,74 @@ func (srv *server) failMsg(
 	}
 
 	// Send request failure notification
-	if err := con.sock.Write(replyMsg); err != nil {
+	if err := con.sockWrite(replyMsg); err != nil {
 		srv.errorLog.Println("Writing failed:", err)
 	}
 }
 
+// newRetryAfterReply composes a special reply message of the given type
+// carrying the given retry-after hint unless it's 0
+func newRetryAfterReply(
+	msgType byte,
+	reqIdent [8]byte,
+	retryAfter time.Duration,
+) []byte {
+	if retryAfter < 1 {
+		return msg.NewSpecialRequestReplyMessage(msgType, reqIdent)
+	}
+	return msg.NewRetryAfterReplyMessage(msgType, reqIdent, retryAfter)
+}
+
+// newErrorReply composes an error reply message carrying the message
+// of the given request error in the encoding defined by the error
+func newErrorReply(reqIdent [8]byte, reqErr ReqErr) []byte {
+	if reqErr.Encoding == EncodingUtf16 {
+		return msg.NewErrorReplyMessageUtf16(
+			reqIdent,
+			reqErr.Code,
+			encodeUtf16(reqErr.Message),
+		)
+	}
+	return msg.NewErrorReplyMessage(reqIdent, reqErr.Code, reqErr.Message)
+}
+
+// encodeUtf16 encodes the given string in little endian UTF16
+func encodeUtf16(str string) []byte {
+	units := utf16.Encode([]rune(str))
+	encoded := make([]byte, 2*len(units))
+	for i, unit := range units {
+		binary.LittleEndian.PutUint16(encoded[2*i:], unit)
+	}
+	return encoded
+}
+
 // failMsgShutdown sends request failure reply due to current server shutdown
 func (srv *server) failMsgShutdown(con *connection, message *msg.Message) {
-	if err := con.sock.Write(msg.NewSpecialRequestReplyMessage(
+	if !srv.beginReply(con, message) {
+		return
+	}
+
+	if err := con.sockWrite(msg.NewSpecialRequestReplyMessage(
 		msg.MsgReplyShutdown,
 		message.Identifier,
 	)); err != nil {
 		srv.errorLog.Println("Writing failed:", err)
 	}
 }
+
+// handleUnknownMessageType handles a message of an unknown type according to
+// the configured unknown message type policy
+func (srv *server) handleUnknownMessageType(con *connection, message []byte) {
+	description := "empty message"
+	if len(message) > 0 {
+		description = fmt.Sprintf("message of unknown type %d", message[0])
+	}
+
+	switch srv.options.OnUnknownMessageType {
+	case UnknownMessageTypeWarn:
+		srv.warnLog.Printf("Dropped %s", description)
+	case UnknownMessageTypeDisconnect:
+		srv.warnLog.Printf("Closing connection due to %s", description)
+		con.Close()
+	}
+}
@@ -9,9 +9,20 @@ import (
 func (srv *server) handleMetadata(resp http.ResponseWriter) {
 	resp.Header().Set("Content-Type", "application/json")
 	resp.Header().Set("Access-Control-Allow-Origin", "*")
+	supportedEncodings := SupportedEncodings()
+	encodings := make([]string, len(supportedEncodings))
+	for i, encoding := range supportedEncodings {
+		encodings[i] = encoding.String()
+	}
 	json.NewEncoder(resp).Encode(struct {
-		ProtocolVersion string `json:"protocol-version"`
+		ProtocolVersion string           `json:"protocol-version"`
+		Encodings       []string         `json:"encodings"`
+		RequestVersions map[string][]int `json:"request-versions,omitempty"`
+		MaxMessageSize  int64            `json:"max-message-size,omitempty"`
 	}{
 		protocolVersion,
+		encodings,
+		srv.options.RequestVersions,
+		srv.options.MaxMessageSize,
 	})
 }
@@ -9,36 +9,202 @@ import (
 // handleRequest handles incoming requests
 // and returns an error if the ongoing connection cannot be proceeded
 func (srv *server) handleRequest(conn *connection, message *msg.Message) {
-	replyPayload, returnedErr := srv.impl.OnRequest(
-		context.Background(),
+	// Replay the stored reply if the request carries an idempotency key
+	// that was already handled
+	var idempotencyKey string
+	if srv.options.IdempotencyStore != nil {
+		message.Name, idempotencyKey = splitIdempotencyKey(message.Name)
+	}
+
+	// Reject requests not allowed before the session establishment
+	if srv.options.PreAuthRequest != nil &&
+		!conn.HasSession() &&
+		!srv.options.PreAuthRequest(message.Name) {
+		srv.failMsg(conn, message, UnauthorizedErr{})
+		return
+	}
+
+	var storeKey string
+	if idempotencyKey != "" {
+		storeKey = idempotencyStoreKey(conn, message.Name, idempotencyKey)
+		release := srv.idempotencyKeys.acquire(storeKey)
+		defer release()
+
+		storedReply, found, err := srv.options.IdempotencyStore.Lookup(
+			storeKey,
+		)
+		if err != nil {
+			srv.errorLog.Printf(
+				"Couldn't lookup idempotency key of request %x "+
+					"of client %v: %s",
+				message.Identifier,
+				conn.Info().RemoteAddr,
+				err,
+			)
+			srv.failMsg(conn, message, err)
+			return
+		}
+		if found {
+			srv.replyPayload(conn, message, storedReply)
+			return
+		}
+	}
+
+	// Reject the request if too many requests are already in flight
+	if !conn.acquireRequestSlot(srv.options.MaxInFlightRequestsPerConn) {
+		srv.failMsg(conn, message, TooManyRequestsErr{})
+		return
+	}
+
+	wrappedMessage := NewMessageWrapper(message)
+	srv.inFlightRequests.register(
+		conn,
+		wrappedMessage,
+		srv.options.Clock.Now(),
+	)
+	// Provide the agreed payload schema version of the request
+	ctx := context.Background()
+	if version, agreed := conn.requestVersions[message.Name]; agreed {
+		ctx = context.WithValue(ctx, requestVersionKey{}, version)
+	}
+
+	ctx, finishSpan := srv.options.Tracer.StartSpan(
+		ctx,
+		"request",
+		wrappedMessage,
+	)
+	startTime := srv.options.Clock.Now()
+	replyPayload, returnedErr := srv.callWithRequestTimeout(
+		ctx,
 		conn,
-		NewMessageWrapper(message),
+		message.Name,
+		func(ctx context.Context) (Payload, error) {
+			// Deregister the request even if the handler panics
+			defer func() {
+				srv.inFlightRequests.deregister(conn, wrappedMessage)
+				conn.releaseRequestSlot()
+			}()
+			return srv.implementation().OnRequest(
+				ctx,
+				conn,
+				wrappedMessage,
+			)
+		},
+	)
+	finishSpan(returnedErr)
+	srv.requestMetrics.observe(
+		srv.options.Clock.Now().Sub(startTime),
+		returnedErr != nil,
 	)
 	switch returnedErr.(type) {
 	case nil:
-		// Initialize payload encoding & data
-		var encoding PayloadEncoding
-		var data []byte
-		if replyPayload != nil {
-			encoding = replyPayload.Encoding()
-			data = replyPayload.Data()
+		// Stream the reply in chunks if it's a streamed payload
+		if stream, isStream := replyPayload.(*StreamPayload); isStream {
+			srv.streamReply(conn, message, stream)
+			return
 		}
 
-		srv.fulfillMsg(
-			conn,
-			message,
-			encoding,
-			data,
-		)
+		// Store the reply for requests carrying an idempotency key
+		if idempotencyKey != "" {
+			if err := srv.options.IdempotencyStore.Save(
+				storeKey,
+				copyPayload(replyPayload),
+			); err != nil {
+				srv.errorLog.Printf(
+					"Couldn't save idempotent reply to request %x "+
+						"of client %v: %s",
+					message.Identifier,
+					conn.Info().RemoteAddr,
+					err,
+				)
+			}
+		}
+
+		srv.replyPayload(conn, message, replyPayload)
 	case ReqErr:
 		srv.failMsg(conn, message, returnedErr)
+	case RequestTimeoutErr:
+		srv.warnLog.Printf(
+			"Request %q (%x) of client %v timed out",
+			message.Name,
+			message.Identifier,
+			conn.Info().RemoteAddr,
+		)
+		srv.failMsg(conn, message, returnedErr)
+	case SessionOperationThrottledErr:
+		srv.failMsg(conn, message, returnedErr)
+	case TooManyRequestsErr:
+		srv.failMsg(conn, message, returnedErr)
+	case ServiceUnavailableErr:
+		srv.failMsg(conn, message, returnedErr)
+	case ReqSrvShutdownErr:
+		srv.failMsg(conn, message, returnedErr)
 	case *ReqErr:
 		srv.failMsg(conn, message, returnedErr)
 	default:
 		srv.errorLog.Printf(
-			"Internal error during request handling: %s",
+			"Internal error during handling of request %q (%x) "+
+				"of client %v: %s",
+			message.Name,
+			message.Identifier,
+			conn.Info().RemoteAddr,
 			returnedErr,
 		)
 		srv.failMsg(conn, message, returnedErr)
 	}
 }
+
+// replyPayload fulfills the request with the given (optional) reply payload
+func (srv *server) replyPayload(
+	conn *connection,
+	message *msg.Message,
+	replyPayload Payload,
+) {
+	// Initialize payload encoding & data
+	encoding := EncodingDefault
+	var data []byte
+	var contentType string
+	if replyPayload != nil {
+		encoding = replyPayload.Encoding()
+		data = replyPayload.Data()
+
+		var err error
+		contentType, err = PayloadContentType(replyPayload)
+		if err != nil {
+			srv.errorLog.Printf(
+				"Invalid reply payload to request %x of client %v: %s",
+				message.Identifier,
+				conn.Info().RemoteAddr,
+				err,
+			)
+			srv.failMsg(conn, message, err)
+			return
+		}
+	}
+	encoding = srv.resolveEncoding(encoding)
+
+	// Transform the reply payload
+	data, err := srv.interceptOutbound(encoding, data)
+	if err != nil {
+		srv.errorLog.Printf(
+			"Couldn't intercept reply payload to request %x of client %v: %s",
+			message.Identifier,
+			conn.Info().RemoteAddr,
+			err,
+		)
+		srv.failMsg(conn, message, err)
+		return
+	}
+
+	if contentType != "" {
+		srv.fulfillMsgTyped(conn, message, contentType, data)
+		return
+	}
+
+	srv.fulfillMsg(
+		conn,
+		message,
+		encoding,
+		data,
+	)
+}
@@ -15,12 +15,20 @@ func (srv *server) handleSessionClosure(
 		return
 	}
 
-	if !conn.HasSession() {
+	// Ephemeral sessions can't be closed by the client
+	// because they're not synchronized to it
+	sess := conn.Session()
+	if sess == nil || sess.Ephemeral {
 		// Send confirmation even though no session was closed
 		srv.fulfillMsg(conn, message, 0, nil)
 		return
 	}
 
+	if !conn.allowSessionOperation() {
+		srv.failMsg(conn, message, SessionOperationThrottledErr{})
+		return
+	}
+
 	// Deregister session from active sessions registry
 	srv.sessionRegistry.deregister(conn)
 
@@ -37,6 +45,12 @@ func (srv *server) handleSessionClosure(
 	// Reset the session on the connection
 	conn.setSession(nil)
 
+	srv.emitEvent(ServerEvent{
+		Type:       EventSessionClosed,
+		Connection: conn,
+		SessionKey: sess.Key,
+	})
+
 	// Send confirmation
 	srv.fulfillMsg(conn, message, 0, nil)
 }
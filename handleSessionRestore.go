@@ -2,7 +2,6 @@ package webwire
 
 import (
 	"encoding/json"
-	"fmt"
 
 	msg "github.com/qbeon/webwire-go/message"
 )
@@ -18,17 +17,33 @@ func (srv *server) handleSessionRestore(
 		return
 	}
 
+	if !con.allowSessionOperation() {
+		srv.failMsg(con, message, SessionOperationThrottledErr{})
+		return
+	}
+
+	if srv.isShuttingDown() {
+		srv.failMsgShutdown(con, message)
+		return
+	}
+
 	key := string(message.Payload.Data)
 
+	// Restoring the session that's already active on this connection
+	// again only refreshes it, the connection remains registered
+	currentSession := con.Session()
+	alreadyRestored := currentSession != nil && currentSession.Key == key
+
 	sessConsNum := srv.sessionRegistry.sessionConnectionsNum(key)
-	if sessConsNum >= 0 && srv.sessionRegistry.maxConns > 0 &&
+	if !alreadyRestored && sessConsNum >= 0 &&
+		srv.sessionRegistry.maxConns > 0 &&
 		uint(sessConsNum+1) > srv.sessionRegistry.maxConns {
 		srv.failMsg(con, message, MaxSessConnsReachedErr{})
 		return
 	}
 
 	// Call session manager lookup hook
-	result, err := srv.sessionManager.OnSessionLookup(key)
+	result, err := srv.sessionManager.OnSessionLookup(con.ctx, key)
 
 	if err != nil {
 		// Fail message with internal error and log it in case the handler fails
@@ -71,17 +86,77 @@ func (srv *server) handleSessionRestore(
 		parsedSessInfo = srv.sessionInfoParser(sessionInfo)
 	}
 
-	con.setSession(&Session{
+	restoredSession := &Session{
 		Key:        key,
 		Creation:   sessionCreation,
 		LastLookup: sessionLastLookup,
-		Info:       parsedSessInfo,
-	})
+		Info:       srv.storedSessionInfo(parsedSessInfo),
+	}
+
+	if alreadyRestored {
+		con.setSession(restoredSession)
+		srv.fulfillMsg(
+			con,
+			message,
+			srv.sessionEncoding(),
+			encodedSession,
+		)
+		return
+	}
+
+	// Replace the currently active session by the restored one
+	con.sessionLock.Lock()
+	previousSession := con.session
+	if previousSession != nil {
+		srv.sessionRegistry.deregister(con)
+	}
+	con.session = restoredSession
 	if err := srv.sessionRegistry.register(con); err != nil {
-		panic(fmt.Errorf("The number of concurrent session connections was " +
-			"unexpectedly exceeded",
-		))
+		// Either the maximum number of active sessions was reached
+		// or the maximum number of concurrent session connections
+		// could have been reached in the meantime by another server instance
+		// sharing the session registry backend.
+		// Keep the previous session active if it can be registered again
+		con.session = previousSession
+		var reregisterErr error
+		if previousSession != nil {
+			reregisterErr = srv.sessionRegistry.register(con)
+			if reregisterErr != nil {
+				con.session = nil
+			}
+		}
+		con.sessionLock.Unlock()
+
+		if reregisterErr != nil {
+			srv.errorLog.Printf(
+				"Couldn't keep session %s after failed restoration: %s",
+				previousSession.Key,
+				reregisterErr,
+			)
+			if err := con.notifySessionClosed(); err != nil {
+				srv.errorLog.Printf("%s", err)
+			}
+		}
+		if _, isMaxSessions := err.(MaxSessionsReachedErr); isMaxSessions {
+			srv.failMsg(con, message, ReqErr{
+				Code:    "MAX_SESSIONS_REACHED",
+				Message: err.Error(),
+			})
+			return
+		}
+		srv.failMsg(con, message, MaxSessConnsReachedErr{})
+		return
 	}
+	con.sessionLock.Unlock()
 
-	srv.fulfillMsg(con, message, EncodingUtf8, encodedSession)
+	srv.fulfillMsg(con, message, srv.sessionEncoding(), encodedSession)
+}
+
+// sessionEncoding returns the encoding of session objects sent to clients,
+// see ServerOptions.BinarySessionEncoding
+func (srv *server) sessionEncoding() PayloadEncoding {
+	if srv.options.BinarySessionEncoding == Enabled {
+		return EncodingBinary
+	}
+	return EncodingUtf8
 }
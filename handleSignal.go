@@ -2,6 +2,7 @@ package webwire
 
 import (
 	"context"
+	"fmt"
 
 	msg "github.com/qbeon/webwire-go/message"
 )
@@ -9,6 +10,20 @@ import (
 // handleSignal handles incoming signals
 // and returns an error if the ongoing connection cannot be proceeded
 func (srv *server) handleSignal(con *connection, message *msg.Message) {
+	// Drop signals exceeding the maximum signal size
+	if srv.options.MaxSignalSize > 0 &&
+		message.Payload.Len() > srv.options.MaxSignalSize {
+		srv.warnLog.Printf(
+			"Dropped signal %q of client %v: "+
+				"payload size (%d) exceeds the maximum signal size (%d)",
+			message.Name,
+			con.Info().RemoteAddr,
+			message.Payload.Len(),
+			srv.options.MaxSignalSize,
+		)
+		return
+	}
+
 	srv.opsLock.Lock()
 	// Ignore incoming signals during shutdown
 	if srv.shutdown {
@@ -18,18 +33,40 @@ func (srv *server) handleSignal(con *connection, message *msg.Message) {
 	srv.currentOps++
 	srv.opsLock.Unlock()
 
-	srv.impl.OnSignal(
+	wrappedMessage := NewMessageWrapper(message)
+	ctx, finishSpan := srv.options.Tracer.StartSpan(
 		context.Background(),
-		con,
-		NewMessageWrapper(message),
+		"signal",
+		wrappedMessage,
 	)
 
-	// Mark signal as done and shutdown the server
-	// if scheduled and no ops are left
-	srv.opsLock.Lock()
-	srv.currentOps--
-	if srv.shutdown && srv.currentOps < 1 {
-		close(srv.shutdownRdy)
-	}
-	srv.opsLock.Unlock()
+	defer func() {
+		// Recover from panics in the signal handler
+		// to not crash the goroutine serving the connection
+		if recovered := recover(); recovered != nil {
+			finishSpan(fmt.Errorf("Signal handler panicked: %v", recovered))
+			srv.errorLog.Printf(
+				"Handling signal %q of client %v panicked: %v",
+				message.Name,
+				con.Info().RemoteAddr,
+				recovered,
+			)
+			if srv.options.OnSignalError != nil {
+				srv.options.OnSignalError(con, wrappedMessage, recovered)
+			}
+		} else {
+			finishSpan(nil)
+		}
+
+		// Mark signal as done and shutdown the server
+		// if scheduled and no ops are left
+		srv.opsLock.Lock()
+		srv.currentOps--
+		if srv.shutdown && srv.currentOps < 1 {
+			close(srv.shutdownRdy)
+		}
+		srv.opsLock.Unlock()
+	}()
+
+	srv.implementation().OnSignal(ctx, con, wrappedMessage)
 }
@@ -0,0 +1,218 @@
+package webwire
+
+import (
+	"encoding/base64"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	pld "github.com/qbeon/webwire-go/payload"
+)
+
+// IdempotencyKeySeparator separates the request name from the
+// application-level idempotency key in the name of an idempotent request
+const IdempotencyKeySeparator = "#"
+
+// IdempotencyScopeHeader is the name of the upgrade request header
+// carrying the idempotency scope of the client. Clients send the same
+// randomly generated scope with each connection for the replies
+// to requests carrying an idempotency key to be replayed after a reconnect
+// even if the client has no session
+const IdempotencyScopeHeader = "Webwire-Idempotency-Scope"
+
+// maxIdempotencyScopeLen defines the maximum length of the idempotency scope
+// sent by the client in the IdempotencyScopeHeader header
+const maxIdempotencyScopeLen = 64
+
+// IdempotentRequestName returns the name of an idempotent request carrying
+// the given application-level idempotency key (such as a UUID generated
+// on form submission). The key is stripped from the request name before
+// the request is passed to the OnRequest hook of servers with an
+// idempotency store configured.
+// The resulting name must not exceed 255 bytes
+func IdempotentRequestName(name, key string) string {
+	return name + IdempotencyKeySeparator + key
+}
+
+// splitIdempotencyKey splits the given request name into the actual name
+// and the idempotency key. The returned key is empty if the name
+// doesn't carry one
+func splitIdempotencyKey(name string) (string, string) {
+	separator := strings.LastIndex(name, IdempotencyKeySeparator)
+	if separator < 0 {
+		return name, ""
+	}
+	return name[:separator], name[separator+len(IdempotencyKeySeparator):]
+}
+
+// NewIdempotencyScope returns a securely generated random string
+// scoping the idempotency keys of the requests sent by a client
+// without a session, see IdempotencyScopeHeader
+func NewIdempotencyScope() string {
+	bytes, err := generateRandomBytes(16)
+	if err != nil {
+		panic(fmt.Errorf("Could not generate an idempotency scope"))
+	}
+	return base64.URLEncoding.EncodeToString(bytes)
+}
+
+// connectionIdempotencyScope returns the idempotency scope sent
+// by the client in the IdempotencyScopeHeader header. A new random scope
+// is returned if the client didn't send any or sent an invalid one,
+// in which case the scope is limited to the connection
+func connectionIdempotencyScope(header string) string {
+	if len(header) < 1 || len(header) > maxIdempotencyScopeLen {
+		return NewIdempotencyScope()
+	}
+	for i := 0; i < len(header); i++ {
+		if header[i] < 33 || header[i] > 126 {
+			return NewIdempotencyScope()
+		}
+	}
+	return header
+}
+
+// idempotencyStoreKey returns the key the reply to a request
+// carrying the given idempotency key is stored by.
+// The key is scoped to the session of the given connection, or to
+// the idempotency scope of the client if it has no session,
+// and to the request name to prevent clients from replaying
+// the replies to requests of others
+func idempotencyStoreKey(conn *connection, name, key string) string {
+	scope := "s" + conn.SessionKey()
+	if !conn.HasSession() {
+		scope = "c" + conn.idempotencyScope
+	}
+
+	// Prefix the scope and the name by their length since both
+	// may contain any characters
+	return fmt.Sprintf("%d:%s%d:%s%s", len(scope), scope, len(name), name, key)
+}
+
+// copyPayload returns a deep copy of the given payload
+// to prevent stored replies from being modified by the handler
+func copyPayload(payload Payload) Payload {
+	if payload == nil {
+		return nil
+	}
+	data := make([]byte, len(payload.Data()))
+	copy(data, payload.Data())
+	var contentType string
+	if typed, isTyped := payload.(ContentTyped); isTyped {
+		contentType = typed.ContentType()
+	}
+	return &EncodedPayload{
+		Payload: pld.Payload{
+			Encoding:    payload.Encoding(),
+			Data:        data,
+			ContentType: contentType,
+		},
+	}
+}
+
+// idempotencyKeyLocks serializes the handling of requests
+// carrying the same idempotency key
+type idempotencyKeyLocks struct {
+	lock *sync.Mutex
+	keys map[string]chan struct{}
+}
+
+// newIdempotencyKeyLocks creates a new idempotency key lock set
+func newIdempotencyKeyLocks() *idempotencyKeyLocks {
+	return &idempotencyKeyLocks{
+		lock: &sync.Mutex{},
+		keys: make(map[string]chan struct{}),
+	}
+}
+
+// acquire blocks until no other request carrying the given key is handled
+// and returns the function releasing the key
+func (locks *idempotencyKeyLocks) acquire(key string) func() {
+	for {
+		locks.lock.Lock()
+		busy, isBusy := locks.keys[key]
+		if !isBusy {
+			released := make(chan struct{})
+			locks.keys[key] = released
+			locks.lock.Unlock()
+			return func() {
+				locks.lock.Lock()
+				delete(locks.keys, key)
+				locks.lock.Unlock()
+				close(released)
+			}
+		}
+		locks.lock.Unlock()
+		<-busy
+	}
+}
+
+// memoryIdempotencyEntry represents a reply stored in memory
+type memoryIdempotencyEntry struct {
+	reply   Payload
+	expires time.Time
+}
+
+// memoryIdempotencyStore is an in-memory implementation
+// of the IdempotencyStore interface
+type memoryIdempotencyStore struct {
+	lock      *sync.Mutex
+	retention time.Duration
+	entries   map[string]memoryIdempotencyEntry
+
+	// nextPrune defines when expired entries are pruned next,
+	// they're pruned at most once per retention period
+	nextPrune time.Time
+}
+
+// NewMemoryIdempotencyStore creates a new in-memory idempotency store
+// retaining replies for the given duration. Stored replies don't survive
+// server restarts and aren't shared among multiple server instances,
+// use a persistent shared store implementation instead if necessary
+func NewMemoryIdempotencyStore(retention time.Duration) IdempotencyStore {
+	return &memoryIdempotencyStore{
+		lock:      &sync.Mutex{},
+		retention: retention,
+		entries:   make(map[string]memoryIdempotencyEntry),
+	}
+}
+
+// Lookup implements the IdempotencyStore interface
+func (store *memoryIdempotencyStore) Lookup(key string) (Payload, bool, error) {
+	store.lock.Lock()
+	defer store.lock.Unlock()
+	entry, exists := store.entries[key]
+	if !exists {
+		return nil, false, nil
+	}
+	if time.Now().After(entry.expires) {
+		delete(store.entries, key)
+		return nil, false, nil
+	}
+	return entry.reply, true, nil
+}
+
+// Save implements the IdempotencyStore interface
+func (store *memoryIdempotencyStore) Save(key string, reply Payload) error {
+	now := time.Now()
+	store.lock.Lock()
+	defer store.lock.Unlock()
+
+	// Prune expired entries once per retention period
+	// rather than on each save
+	if now.After(store.nextPrune) {
+		for storedKey, entry := range store.entries {
+			if now.After(entry.expires) {
+				delete(store.entries, storedKey)
+			}
+		}
+		store.nextPrune = now.Add(store.retention)
+	}
+
+	store.entries[key] = memoryIdempotencyEntry{
+		reply:   reply,
+		expires: now.Add(store.retention),
+	}
+	return nil
+}
@@ -0,0 +1,80 @@
+package webwire
+
+import (
+	"sync"
+	"time"
+)
+
+// RequestInfo represents information about a request
+// currently processed by the OnRequest hook
+type RequestInfo struct {
+	// Name is the name of the request
+	Name string
+
+	// Identifier is the identifier of the request which is only unique
+	// within the scope of the connection the request was received from
+	Identifier [8]byte
+
+	// Connection references the client connection
+	// the request was received from
+	Connection Connection
+
+	// StartTime is the time the request handler was invoked at
+	StartTime time.Time
+}
+
+// inFlightRequestKey identifies an in-flight request
+// by its origin connection and its connection-scoped identifier
+type inFlightRequestKey struct {
+	conn       *connection
+	identifier [8]byte
+}
+
+// inFlightRequests keeps track of the requests
+// currently processed by the OnRequest hook
+type inFlightRequests struct {
+	lock     sync.Mutex
+	requests map[inFlightRequestKey]RequestInfo
+}
+
+// newInFlightRequests creates a new empty in-flight request tracker
+func newInFlightRequests() *inFlightRequests {
+	return &inFlightRequests{
+		requests: make(map[inFlightRequestKey]RequestInfo),
+	}
+}
+
+// register starts tracking the given request
+func (reqs *inFlightRequests) register(
+	conn *connection,
+	message Message,
+	startTime time.Time,
+) {
+	identifier := message.Identifier()
+	reqs.lock.Lock()
+	reqs.requests[inFlightRequestKey{conn, identifier}] = RequestInfo{
+		Name:       message.Name(),
+		Identifier: identifier,
+		Connection: conn,
+		StartTime:  startTime,
+	}
+	reqs.lock.Unlock()
+}
+
+// deregister stops tracking the given request
+func (reqs *inFlightRequests) deregister(conn *connection, message Message) {
+	reqs.lock.Lock()
+	delete(reqs.requests, inFlightRequestKey{conn, message.Identifier()})
+	reqs.lock.Unlock()
+}
+
+// list returns a snapshot of all currently tracked requests
+func (reqs *inFlightRequests) list() []RequestInfo {
+	reqs.lock.Lock()
+	list := make([]RequestInfo, 0, len(reqs.requests))
+	for _, info := range reqs.requests {
+		list = append(list, info)
+	}
+	reqs.lock.Unlock()
+	return list
+}
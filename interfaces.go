@@ -12,14 +12,25 @@ type Server interface {
 	// ServeHTTP implements the HTTP handler interface
 	ServeHTTP(resp http.ResponseWriter, req *http.Request)
 
-	// Run will launch the webwire server blocking the calling goroutine
-	// until the server is either gracefully shut down
-	// or crashes returning an error
+	// Run will launch the webwire server serving incoming connections on the
+	// listener bound to ServerOptions.Address during the server creation,
+	// blocking the calling goroutine until the server is either
+	// gracefully shut down, in which case nil is returned,
+	// or the listener fails returning the listener error.
+	// Run is only supported by servers created by NewServer,
+	// headless servers created by NewHeadlessServer are hosted
+	// by an external HTTP server through ServeHTTP instead
+	// and return a HeadlessServerErr
 	Run() error
 
-	// Addr returns the address the webwire server is listening on
+	// Addr returns the address the webwire server is listening on.
+	// Returns nil for headless servers
 	Addr() net.Addr
 
+	// ProtocolVersion returns the version of the webwire protocol
+	// implemented by the server, see ProtocolVersion
+	ProtocolVersion() string
+
 	// Shutdown appoints a server shutdown and blocks the calling goroutine
 	// until the server is gracefully stopped awaiting all currently processed
 	// signal and request handlers to return.
@@ -29,6 +40,10 @@ type Server interface {
 	// are just ignored
 	Shutdown() error
 
+	// PendingOps returns the number of currently processed operations
+	// such as signal and request handlers
+	PendingOps() uint32
+
 	// ActiveSessionsNum returns the number of currently active sessions
 	ActiveSessionsNum() int
 
@@ -38,6 +53,110 @@ type Server interface {
 	// SessionConnections implements the SessionRegistry interface
 	SessionConnections(sessionKey string) []Connection
 
+	// SignalSession sends a signal to all connections of the session
+	// identified by the given key to this server and returns the number
+	// of signaled connections. The connections are looked up in the
+	// session registry index rather than by scanning all connections.
+	// Failures to signal individual connections are logged.
+	// Returns an error if the name isn't a valid signal name
+	SignalSession(sessionKey, name string, payload Payload) (int, error)
+
+	// IsOnline returns true if the session identified by the given key
+	// has at least one connection to this server, otherwise returns false
+	IsOnline(sessionKey string) bool
+
+	// ActiveSessionConnections returns the number of connections
+	// of the session identified by the given key to this server.
+	// In contrast to SessionConnectionsNum it doesn't count connections
+	// to other servers sharing the session registry backend
+	// and returns 0 if the session has no connections
+	ActiveSessionConnections(sessionKey string) int
+
+	// SessionRegistryStats returns a read-only snapshot of the
+	// session registry of this server including the number of active
+	// sessions and connections and the high-water mark of the number
+	// of concurrent connections of a single session.
+	// Connections to other servers sharing the session registry backend
+	// aren't taken into account
+	SessionRegistryStats() RegistryStats
+
+	// PendingOutboundBytes returns the total number of bytes of outbound
+	// messages currently pending to be written to all connections,
+	// see ServerOptions.MaxPendingOutboundBytes
+	PendingOutboundBytes() int64
+
+	// ActiveGoroutines returns the number of goroutines currently started
+	// by the server such as the ones handling incoming messages,
+	// enforcing request timeouts, streaming replies and sending heartbeats.
+	// The goroutines serving the connections, which are started
+	// by the HTTP server, aren't taken into account.
+	// It's useful for sizing instances and detecting handlers
+	// that never return, see ServerOptions.MaxGoroutines
+	ActiveGoroutines() int
+
+	// Metrics returns a snapshot of the live counters of the server
+	// including the number and latencies of handled requests
+	// and the number of active connections and sessions,
+	// see the wwrprom package for exposing them to Prometheus
+	Metrics() Metrics
+
+	// CloseIdleConnections closes all connections that haven't received
+	// any message for at least the given duration and returns
+	// the number of closed connections. The OnClientDisconnected hook
+	// is invoked for each of them with a DisconnectServerInitiated reason
+	CloseIdleConnections(idleFor time.Duration) int
+
+	// InFlightRequests returns information about each request
+	// currently processed by the OnRequest hook in no particular order.
+	// It's intended for debugging, for example to find out
+	// which requests are hanging during a shutdown
+	InFlightRequests() []RequestInfo
+
+	// RetainSignal stores the given signal as the latest retained signal
+	// of the given name replacing any previously retained one.
+	// Retained signals are sent to each newly connected client right
+	// after the OnClientConnected hook returns, in no particular order.
+	// RetainSignal doesn't send the signal to currently connected clients.
+	// Returns an error if the name is invalid
+	RetainSignal(name string, payload Payload) error
+
+	// Publish sends a signal named after the given topic to all currently
+	// connected clients subscribed to it (see Connection.Subscribe)
+	// and returns the number of signaled clients. Failures to signal
+	// individual clients are logged.
+	// Returns an error if the topic isn't a valid signal name
+	Publish(topic string, payload Payload) (int, error)
+
+	// BroadcastToTag sends a named signal to all currently connected
+	// clients tagged with the given tag (see Connection.Tags)
+	// and returns the number of signaled clients. Failures to signal
+	// individual clients are logged.
+	// Returns an error if the name isn't a valid signal name
+	BroadcastToTag(tag, name string, payload Payload) (int, error)
+
+	// ClearRetainedSignal removes the retained signal of the given name,
+	// does nothing if there's no retained signal of the given name
+	ClearRetainedSignal(name string)
+
+	// SetRequestTimeout defines the maximum duration of the OnRequest hook
+	// for requests of the given name overriding ServerOptions.RequestTimeout.
+	// Requests exceeding it fail with a RequestTimeoutErr
+	// and the context of the hook is canceled.
+	// A duration of 0 removes the timeout of the given name
+	// falling back to ServerOptions.RequestTimeout
+	SetRequestTimeout(name string, timeout time.Duration)
+
+	// CloseSessionsWhere closes all sessions with connections
+	// to this server whose session info matches the given predicate
+	// notifying each affected client and returns the number
+	// of closed sessions. Sessions failed to be closed on any
+	// of their connections aren't counted, the failures are logged
+	// to the error log. The session info passed to the predicate
+	// may be nil if the session has no info attached.
+	// Sessions with connections to other servers sharing the session
+	// registry backend aren't taken into account
+	CloseSessionsWhere(predicate func(SessionInfo) bool) int
+
 	// CloseSession closes the session identified by the given key and returns
 	// the affected connections, a list of errors for each session session
 	// closure attempt and a general error which is not nil if at least
@@ -48,6 +167,26 @@ type Server interface {
 		closeErrors []error,
 		err error,
 	)
+
+	// SetImplementation atomically replaces the server implementation
+	// without affecting established connections. Each hook invocation
+	// started after SetImplementation returned is dispatched
+	// to the new implementation while hooks already invoked
+	// keep running on the previous one.
+	// Hooks are not dispatched atomically per connection, thus a client
+	// connected during the swap may have its OnClientConnected hook invoked
+	// on the previous implementation and OnClientDisconnected on the new one.
+	// Panics if the given implementation is nil
+	SetImplementation(implementation ServerImplementation)
+
+	// Events returns the stream of connection lifecycle events
+	// for logging and metrics sinks preferring a single consumer over
+	// individual hooks. Events are emitted without blocking the server
+	// and dropped while the buffer of the stream is full
+	// (see ServerOptions.EventBufferSize), thus the stream
+	// must be consumed continuously to not miss any events.
+	// The stream is never closed
+	Events() <-chan ServerEvent
 }
 
 // ConnectionOptions represents the connection upgrade options
@@ -64,6 +203,17 @@ type ConnectionOptions interface {
 	ConcurrencyLimit() uint
 }
 
+// TaggedConnectionOptions can optionally be implemented by the
+// ConnectionOptions returned by the BeforeUpgrade hook
+// to classify the connection, see AcceptConnection
+type TaggedConnectionOptions interface {
+	ConnectionOptions
+
+	// Tags returns the tags to be attached to the connection,
+	// see Connection.Tags
+	Tags() []string
+}
+
 // ServerImplementation defines the interface
 // of a webwire server implementation
 type ServerImplementation interface {
@@ -76,7 +226,9 @@ type ServerImplementation interface {
 	// intercept, configure or prevent incoming connections.
 	// BeforeUpgrade must return either the result of the `AcceptConnection`
 	// or the result of the `RefuseConnection` functions.
-	// Returning nil will refuse the incoming connection without an explanation
+	// Returning nil will refuse the incoming connection without an explanation.
+	// The context of the request is cancelled
+	// when ServerOptions.BeforeUpgradeTimeout is exceeded
 	BeforeUpgrade(
 		resp http.ResponseWriter,
 		req *http.Request,
@@ -93,11 +245,11 @@ type ServerImplementation interface {
 	OnClientConnected(client Connection)
 
 	// OnClientDisconnected is invoked when a client closes the connection
-	// to the server.
+	// to the server. The reason describes why the connection was closed.
 	//
 	// This hook will be invoked by the goroutine serving
 	// the calling client before it's suspended
-	OnClientDisconnected(client Connection)
+	OnClientDisconnected(client Connection, reason DisconnectReason)
 
 	// OnSignal is invoked when the webwire server receives
 	// a signal from a client.
@@ -119,6 +271,12 @@ type ServerImplementation interface {
 	// for security reasons as this might accidentally leak
 	// sensitive information to the client.
 	//
+	// Returning a nil payload and a nil error replies with an empty payload
+	// encoded in ServerOptions.DefaultEncoding.
+	//
+	// Nameless requests (see Message.Name) are meant to be handled
+	// by a default handler.
+	//
 	// This hook will be invoked by the goroutine serving the calling client
 	// and will block any other interactions with this client while executing
 	OnRequest(
@@ -138,15 +296,124 @@ type Connection interface {
 	// client agent string, the remote address and the time of creation
 	Info() ClientInfo
 
-	// Signal sends a named signal containing the given payload to the client
+	// Snapshot returns a snapshot of the connection's state including
+	// its information, the last activity, the currently assigned session
+	// and the number of requests currently being processed
+	Snapshot() ConnectionInfo
+
+	// LastActivity returns the time the last message was received
+	// from the client or the time of creation
+	// if no message was received yet
+	LastActivity() time.Time
+
+	// UnderlyingConn returns the connection of the underlying websocket
+	// implementation, which is a *websocket.Conn of the gorilla/websocket
+	// library when using the default connection upgrader, or nil if the
+	// socket doesn't implement the UnderlyingConnProvider interface.
+	// It's intended for advanced use cases only such as setting socket
+	// options. Reading from or writing to it bypasses the webwire protocol
+	// and will break the connection, use it at your own risk!
+	UnderlyingConn() interface{}
+
+	// OutboundQueueLen returns the number of messages (signals, replies etc.)
+	// currently waiting to be written to the client including the one
+	// that's being written. A growing queue indicates a slow client
+	// that signals should be throttled for. Always returns 0 if the socket
+	// doesn't implement the OutboundQueueLenProvider interface.
+	// It's safe for concurrent use
+	OutboundQueueLen() int
+
+	// Header returns the first value of the given header
+	// of the HTTP request the connection was upgraded from.
+	// Only headers listed in ServerOptions.ForwardedHeaders are retained,
+	// an empty string is returned for any other header
+	Header(name string) string
+
+	// ConnectParam returns the first value of the given query parameter
+	// of the URL of the HTTP request the connection was upgraded from.
+	// Only parameters listed in ServerOptions.ConnectParams are retained,
+	// an empty string is returned for any other parameter
+	ConnectParam(key string) string
+
+	// Signal sends a named signal containing the given payload to the client.
+	// Non-critical signals are silently dropped while the client
+	// has signals paused (see SignalsPaused)
 	Signal(name string, payload Payload) error
 
+	// SendRaw sends a signal message prepared by PrepareSignal to the client
+	// as is, without re-encoding it. The prepared message is only read
+	// and can therefore be safely sent to multiple clients concurrently.
+	// Returns an error if the message doesn't represent a signal
+	// or if a ServerOptions.PayloadInterceptor is set, since prepared
+	// signals can't be transformed without re-encoding them
+	SendRaw(prebuilt []byte) error
+
+	// ReliableSignal sends a named signal containing the given payload
+	// to the client and blocks until the client acknowledges its reception
+	// after the client-side OnSignal hook returned. Unacknowledged signals
+	// are sent again after ServerOptions.SignalAckTimeout up to
+	// ServerOptions.ReliableSignalAttempts times, thus a client may receive
+	// a reliable signal more than once.
+	// Reliable signals are never suppressed by paused signals.
+	// Payloads of a content type (see ContentTyped) aren't supported.
+	// Returns a TimeoutErr if the signal wasn't acknowledged
+	// or a DisconnectedErr if the connection was closed in the meantime
+	ReliableSignal(name string, payload Payload) error
+
+	// Subscribe subscribes the connection to the given topic
+	// for it to receive all signals published to the topic
+	// (see Server.Publish). Subscriptions are cleared on disconnection
+	Subscribe(topic string)
+
+	// Unsubscribe removes the subscription to the given topic,
+	// does nothing if the connection isn't subscribed to it
+	Unsubscribe(topic string)
+
+	// IsSubscribed returns true if the connection
+	// is subscribed to the given topic
+	IsSubscribed(topic string) bool
+
+	// Tags returns a copy of the tags attached to the connection
+	// by the BeforeUpgrade hook (see AcceptConnection) in lexical order.
+	// The tags remain unchanged during the lifetime of the connection
+	Tags() []string
+
+	// HasTag returns true if the given tag is attached to the connection
+	HasTag(tag string) bool
+
+	// SignalsPaused returns true if the client requested signals
+	// to be paused. While paused, signals sent through Signal and SendRaw
+	// are silently dropped unless ServerOptions.CriticalSignal
+	// considers them critical
+	SignalsPaused() bool
+
 	// CreateSession creates a new session for this connection and
 	// automatically synchronizes the new session to the remote client.
 	// The synchronization happens asynchronously using a signal
 	// and doesn't block the calling goroutine.
-	// Returns an error if there's already another session active
-	CreateSession(attachment SessionInfo) error
+	// Returns an error if there's already another session active,
+	// a MaxSessionsReachedErr if ServerOptions.MaxActiveSessions
+	// is reached, a SessionOperationThrottledErr
+	// if ServerOptions.MaxSessionOperations is exceeded
+	// or a ReqSrvShutdownErr if the server is being shut down.
+	// The creation is aborted returning a SessionCreationCancelledErr
+	// if either the given context is cancelled or the connection is closed
+	// before the session manager finished persisting the session,
+	// or a SessionCreationFailedErr if the session manager failed to
+	// persist the session
+	CreateSession(ctx context.Context, attachment SessionInfo) error
+
+	// CreateEphemeralSession creates a new server-side only session
+	// for this connection. Ephemeral sessions are registered in the session
+	// registry and provide the same getters as regular sessions
+	// but are neither synchronized to the client nor passed to the
+	// session manager and are therefore never persisted nor restored.
+	// Their randomly generated keys aren't exposed to the client.
+	// Ephemeral sessions are dropped when the connection is closed.
+	// Returns an error if there's already another session active,
+	// a MaxSessionsReachedErr if ServerOptions.MaxActiveSessions
+	// is reached or a ReqSrvShutdownErr if the server is being shut down
+	CreateEphemeralSession(attachment SessionInfo) error
 
 	// CloseSession disables the currently active session for this connection
 	// and synchronize the closure to the remote client.
@@ -154,6 +421,19 @@ type Connection interface {
 	// Does nothing if there's no active session
 	CloseSession() error
 
+	// PatchSessionInfo sets the given field of the info of the currently
+	// active session to the given value keeping all other fields.
+	// The patched info is passed through a JSON round-trip and parsed
+	// by the SessionInfoParser, persisted if the session manager implements
+	// the SessionInfoUpdater interface and synchronized to the client.
+	// Concurrent patches on the same connection are serialized.
+	// Other connections of the same session aren't affected.
+	// Returns an error if there's no active session
+	// or the session info couldn't be persisted
+	// and a SessionOperationThrottledErr if ServerOptions.MaxSessionOperations
+	// is exceeded
+	PatchSessionInfo(field string, value interface{}) error
+
 	// HasSession returns true if this connection currently has
 	// a session assigned, otherwise returns false
 	HasSession() bool
@@ -184,6 +464,43 @@ type Connection interface {
 	Close()
 }
 
+// PayloadInterceptor defines the interface of a symmetric transformation
+// applied to the payload data of messages, such as an app-layer encryption
+// or compression. Only non-empty payloads are transformed.
+// Implementations must be safe for concurrent use
+type PayloadInterceptor interface {
+	// Outbound is applied to the payload data of replies and signals
+	// before they're written to the wire. The UTF16 alignment is verified
+	// on the transformed data, which must therefore remain of even length
+	// for UTF16 encoded payloads.
+	// Each chunk of a streamed reply is transformed separately
+	// while signals prepared by PrepareSignal can't be sent
+	// when an interceptor is set
+	Outbound(data []byte) ([]byte, error)
+
+	// Inbound is applied to the payload data of incoming requests and
+	// signals right after they're parsed and before they're handled.
+	// Requests the inbound transformation fails for are rejected
+	// with a ProtocolErr while such signals are dropped
+	Inbound(data []byte) ([]byte, error)
+}
+
+// Tracer defines the interface of a tracer starting a span
+// around each invocation of the OnRequest and OnSignal hooks
+type Tracer interface {
+	// StartSpan is invoked right before a request or signal is handled.
+	// name is either "request" or "signal" while the handled message
+	// provides the message name and identifier for the span attributes.
+	// The returned context is passed to the handler so that downstream spans
+	// nest correctly, the returned function is invoked when the handler
+	// returns, receiving the error returned by the request handler if any
+	StartSpan(
+		ctx context.Context,
+		name string,
+		message Message,
+	) (context.Context, func(err error))
+}
+
 // SessionLookupResult represents the result of a session lookup
 type SessionLookupResult interface {
 	// Creation returns the retrieved creation time
@@ -197,19 +514,54 @@ type SessionLookupResult interface {
 	Info() map[string]interface{}
 }
 
-// SessionManager defines the interface of a webwire server's session manager
+// Clock defines the interface of the source of the current time
+// used for timestamps by the server, see ServerOptions.Clock
+type Clock interface {
+	// Now returns the current time
+	Now() time.Time
+}
+
+// IdempotencyStore defines the interface of a store of request replies
+// keyed by application-level idempotency keys. The keys passed
+// to the store are opaque, they're derived from the idempotency keys
+// of the requests scoped to the session or idempotency scope of the client
+// and to the request name.
+// Requests carrying an idempotency key already stored are replied to
+// with the stored reply instead of being passed to the OnRequest hook
+type IdempotencyStore interface {
+	// Lookup returns the reply stored for the given idempotency key.
+	// found must be false if no reply is stored for the given key
+	Lookup(key string) (reply Payload, found bool, err error)
+
+	// Save stores the reply of the request carrying the given
+	// idempotency key. Only successful replies are saved, failed requests
+	// can be retried using the same idempotency key
+	Save(key string, reply Payload) error
+}
+
+// SessionManager defines the interface of a webwire server's session manager.
+// A session manager can be shared by multiple server instances running
+// in the same process to make sessions created on one of them restorable
+// on the others, in which case its hooks are invoked concurrently
+// by all of them. Implementations must be safe for concurrent use
 type SessionManager interface {
 	// OnSessionCreated is invoked after the synchronization of the new session
 	// to the remote client.
 	// The actual created session can be retrieved from the provided connection.
-	// If OnSessionCreated returns an error then this error is logged
-	// but the session will not be destroyed and will remain active!
-	// The only consequence of OnSessionCreation failing is that the server
-	// won't be able to restore the session after the client is disconnected.
+	// It's intended for persisting the session and for side effects
+	// such as emitting audit events or warming caches.
+	// If OnSessionCreated returns an error then the session creation is
+	// rolled back: the session is removed from the connection, the client
+	// is notified about its closure and client.CreateSession returns
+	// a SessionCreationFailedErr. OnSessionClosed isn't invoked
+	// for rolled back sessions.
 	//
 	// This hook will be invoked by the goroutine calling the
-	// client.CreateSession connection method
-	OnSessionCreated(client Connection) error
+	// client.CreateSession connection method.
+	// The given context is cancelled when either the context passed to
+	// client.CreateSession is cancelled or the connection is closed,
+	// if the hook then returns an error the session creation is aborted
+	OnSessionCreated(ctx context.Context, client Connection) error
 
 	// OnSessionLookup is invoked when the server is looking for a specific
 	// session given its key.
@@ -222,11 +574,15 @@ type SessionManager interface {
 	// it'll be logged and the session restoration will fail.
 	//
 	// This hook will be invoked by the goroutine serving the associated client
-	// and will block any other interactions with this client while executing
+	// and will block any other interactions with this client while executing.
+	// The given context is cancelled when the connection is closed
 	//
 	// WARNING: if this hooks doesn't update the LastLookup field of the found
 	// session object then the session garbage collection won't work properly
-	OnSessionLookup(key string) (result SessionLookupResult, err error)
+	OnSessionLookup(
+		ctx context.Context,
+		key string,
+	) (result SessionLookupResult, err error)
 
 	// OnSessionClosed is invoked when the session associated with the given key
 	// is closed (thus destroyed) either by the server or the client.
@@ -241,6 +597,46 @@ type SessionManager interface {
 	OnSessionClosed(sessionKey string) error
 }
 
+// SessionInfoUpdater is an optional interface a SessionManager implements
+// to persist the session info patched by Connection.PatchSessionInfo
+type SessionInfoUpdater interface {
+	// OnSessionInfoUpdated is invoked after the info of the session
+	// of the given connection was patched but before the client is notified.
+	// The patched session can be retrieved from the provided connection.
+	// If an error is returned then the patch is rolled back.
+	// The given context is cancelled when the connection is closed
+	OnSessionInfoUpdated(ctx context.Context, client Connection) error
+}
+
+// SessionRegistryBackend defines the interface of the backend keeping track
+// of the number of concurrent connections of each active session.
+// A backend shared by multiple server instances enforces the maximum number
+// of concurrent session connections across all of them.
+// Implementations must be safe for concurrent use
+type SessionRegistryBackend interface {
+	// Register is invoked when a connection is assigned the session
+	// identified by the given key and must increment its number
+	// of connections. If the session already reached maxConns connections
+	// then an error must be returned without incrementing the number,
+	// unless maxConns is zero which stands for unlimited
+	Register(sessionKey string, maxConns uint) error
+
+	// Deregister is invoked when a connection of the session identified
+	// by the given key is closed or loses its session and must decrement its
+	// number of connections removing the session when none are left.
+	// It must return the number of connections left or -1 if the session
+	// isn't registered
+	Deregister(sessionKey string) int
+
+	// SessionConnectionsNum must return the number of concurrent connections
+	// of the session identified by the given key
+	// or -1 if the session isn't registered
+	SessionConnectionsNum(sessionKey string) int
+
+	// ActiveSessionsNum must return the number of currently active sessions
+	ActiveSessionsNum() int
+}
+
 // SessionKeyGenerator defines the interface of a webwire server's
 // session key generator. This interface must not be implemented (!) unless
 // the default generator doesn't meet the exact needs of the library user,
@@ -249,7 +645,13 @@ type SessionKeyGenerator interface {
 	// Generate is invoked when the webwire server creates a new session
 	// and requires a new session key to be generated.
 	// This hook must not be used except the user knows exactly what he/she does
-	// as it would compromise security if implemented improperly
+	// as it would compromise security if implemented improperly.
+	// Keys are transmitted as opaque binary when restoring sessions
+	// but must be valid UTF-8 because the session key is JSON encoded
+	// when sent to the client, Connection.CreateSession fails otherwise.
+	// Keys containing NUL bytes or path separators can't be used
+	// with the DefaultSessionManager which stores sessions in files
+	// named after their keys
 	Generate() string
 }
 
@@ -297,17 +699,42 @@ type Payload interface {
 	Utf8() (string, error)
 }
 
+// ContentTyped defines the optional interface of payloads specifying
+// the application-level content type of their data (see NewTypedPayload).
+// Payloads of a content type must be binary encoded
+type ContentTyped interface {
+	// ContentType returns the application-level content type of
+	// the payload data, or an empty string if it doesn't specify any
+	ContentType() string
+}
+
 // Message represents a WebWire protocol message
 type Message interface {
 	// MessageType returns the type of the message
 	MessageType() byte
 
-	// Identifier returns the message identifier
+	// Identifier returns the message identifier.
+	// Identifiers are only unique within the scope of a single connection,
+	// different connections (even of the same client reconnecting
+	// or of the same session) may use the same identifiers.
+	// State keyed by message identifiers must therefore always be scoped
+	// by the connection the message was received from
 	Identifier() [8]byte
 
-	// Name returns the name of the message
+	// Name returns the name of the message.
+	// Requests and signals may be nameless, in which case they must carry
+	// a payload and an empty name is returned. Nameless messages address
+	// the default handler and are thus only accepted
+	// if ServerOptions.AllowedNames either is nil or lists the empty name
 	Name() string
 
 	// Payload returns the message payload
 	Payload() Payload
+
+	// Replied returns true if a reply to the message was already sent
+	// or attempted, for example because the request timed out,
+	// which allows handlers doing work asynchronously to skip work
+	// whose result would be dropped anyway. A client disconnecting
+	// doesn't mark its pending messages as replied
+	Replied() bool
 }
@@ -37,8 +37,14 @@ func (wrp *MessageWrapper) Name() string {
 func (wrp *MessageWrapper) Payload() Payload {
 	return &EncodedPayload{
 		Payload: pld.Payload{
-			Encoding: wrp.actual.Payload.Encoding,
-			Data:     wrp.actual.Payload.Data,
+			Encoding:    wrp.actual.Payload.Encoding,
+			Data:        wrp.actual.Payload.Data,
+			ContentType: wrp.actual.Payload.ContentType,
 		},
 	}
 }
+
+// Replied implements the Message interface
+func (wrp *MessageWrapper) Replied() bool {
+	return wrp.actual.Replied()
+}
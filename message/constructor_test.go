@@ -2,6 +2,7 @@ package message
 
 import (
 	"testing"
+	"time"
 
 	pld "github.com/qbeon/webwire-go/payload"
 	"github.com/stretchr/testify/require"
@@ -258,6 +259,45 @@ func TestMsgNewReplyMsgUtf16(t *testing.T) {
 	require.Equal(t, expected, actual)
 }
 
+// TestMsgNewReplyChunkMsg tests NewReplyChunkMessage
+func TestMsgNewReplyChunkMsg(t *testing.T) {
+	id := genRndMsgIdentifier()
+	chunk := []byte("random chunk data")
+
+	// Compose encoded message
+	// Add type flag
+	expected := []byte{MsgReplyChunk}
+	// Add identifier
+	expected = append(expected, id[:]...)
+	// Add payload chunk
+	expected = append(expected, chunk...)
+
+	actual := NewReplyChunkMessage(id, chunk)
+
+	require.Equal(t, expected, actual)
+}
+
+// TestMsgNewRetryAfterReplyMsg tests NewRetryAfterReplyMessage
+func TestMsgNewRetryAfterReplyMsg(t *testing.T) {
+	id := genRndMsgIdentifier()
+
+	// Compose encoded message
+	// Add type flag
+	expected := []byte{MsgTooManyRequests}
+	// Add identifier
+	expected = append(expected, id[:]...)
+	// Add retry-after hint (1500 milliseconds)
+	expected = append(expected, 0, 0, 0, 0, 0, 0, 0x05, 0xDC)
+
+	actual := NewRetryAfterReplyMessage(
+		MsgTooManyRequests,
+		id,
+		1500*time.Millisecond,
+	)
+
+	require.Equal(t, expected, actual)
+}
+
 // TestMsgNewSigMsgBinary tests NewSignalMessage
 // using the default binary encoding
 func TestMsgNewSigMsgBinary(t *testing.T) {
@@ -372,3 +412,126 @@ func TestMsgNewSigMsgUtf16OddNameLen(t *testing.T) {
 
 	require.Equal(t, expected, actual)
 }
+
+// TestMsgNewReliableSigMsgUtf8 tests NewReliableSignalMessage
+// using UTF8 encoding
+func TestMsgNewReliableSigMsgUtf8(t *testing.T) {
+	id := genRndMsgIdentifier()
+	name := genRndName(1, 255)
+	payload := pld.Payload{
+		Encoding: pld.Utf8,
+		Data:     []byte("random payload data"),
+	}
+
+	// Compose encoded message
+	// Add type flag
+	expected := []byte{MsgReliableSignalUtf8}
+	// Add identifier
+	expected = append(expected, id[:]...)
+	// Add name length flag
+	expected = append(expected, byte(len(name)))
+	// Add name
+	expected = append(expected, []byte(name)...)
+	// Add payload
+	expected = append(expected, payload.Data...)
+
+	actual := NewReliableSignalMessage(
+		id,
+		string(name),
+		payload.Encoding,
+		payload.Data,
+	)
+
+	require.Equal(t, expected, actual)
+}
+
+// TestMsgNewSignalAckMsg tests NewSignalAckMessage
+func TestMsgNewSignalAckMsg(t *testing.T) {
+	id := genRndMsgIdentifier()
+
+	// Compose encoded message
+	// Add type flag
+	expected := []byte{MsgSignalAck}
+	// Add identifier
+	expected = append(expected, id[:]...)
+
+	require.Equal(t, expected, NewSignalAckMessage(id))
+}
+
+// TestMsgNewTypedSigMsg tests NewTypedSignalMessage
+func TestMsgNewTypedSigMsg(t *testing.T) {
+	name := genRndName(1, 255)
+	data := []byte("random payload data")
+
+	// Compose encoded message
+	// Add type flag
+	expected := []byte{MsgSignalTyped}
+	// Add name length flag
+	expected = append(expected, byte(len(name)))
+	// Add name
+	expected = append(expected, []byte(name)...)
+	// Add content type length flag
+	expected = append(expected, byte(len("image/png")))
+	// Add content type
+	expected = append(expected, []byte("image/png")...)
+	// Add payload
+	expected = append(expected, data...)
+
+	actual := NewTypedSignalMessage(string(name), "image/png", data)
+
+	require.Equal(t, expected, actual)
+}
+
+// TestMsgNewTypedReqMsg tests NewTypedRequestMessage
+func TestMsgNewTypedReqMsg(t *testing.T) {
+	id := genRndMsgIdentifier()
+	name := genRndName(1, 255)
+	data := []byte("random payload data")
+
+	// Compose encoded message
+	// Add type flag
+	expected := []byte{MsgRequestTyped}
+	// Add identifier
+	expected = append(expected, id[:]...)
+	// Add name length flag
+	expected = append(expected, byte(len(name)))
+	// Add name
+	expected = append(expected, []byte(name)...)
+	// Add content type length flag
+	expected = append(expected, byte(len("application/protobuf")))
+	// Add content type
+	expected = append(expected, []byte("application/protobuf")...)
+	// Add payload
+	expected = append(expected, data...)
+
+	actual := NewTypedRequestMessage(
+		id,
+		string(name),
+		"application/protobuf",
+		data,
+	)
+
+	require.Equal(t, expected, actual)
+}
+
+// TestMsgNewTypedReplyMsg tests NewTypedReplyMessage
+func TestMsgNewTypedReplyMsg(t *testing.T) {
+	id := genRndMsgIdentifier()
+	data := []byte("random payload data")
+
+	// Compose encoded message
+	// Add type flag
+	expected := []byte{MsgReplyTyped}
+	// Add identifier
+	expected = append(expected, id[:]...)
+	// Add content type length flag
+	expected = append(expected, byte(len("image/png")))
+	// Add content type
+	expected = append(expected, []byte("image/png")...)
+	// Add payload
+	expected = append(expected, data...)
+
+	actual := NewTypedReplyMessage(id, "image/png", data)
+
+	require.Equal(t, expected, actual)
+}
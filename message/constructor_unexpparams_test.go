@@ -93,6 +93,19 @@ func TestMsgNewReqMsgInvalidCharsetAboveAscii126(t *testing.T) {
 	)
 }
 
+// TestMsgNewSigMsgNoNameNoPayload tests calling
+// the signal message constructor without both the name and the payload
+func TestMsgNewSigMsgNoNameNoPayload(t *testing.T) {
+	require.Panics(t,
+		func() {
+			NewSignalMessage("", pld.Binary, nil)
+		},
+		"Expected a panic after calling the "+
+			" signal message constructor without both the name "+
+			"and the payload",
+	)
+}
+
 // TestMsgNewSigMsgNameTooLong tests NewSignalMessage with a too long name
 func TestMsgNewSigMsgNameTooLong(t *testing.T) {
 	tooLongNamelength := 256
@@ -147,6 +160,8 @@ func TestMsgNewSpecialRequestReplyMessageInvalidType(t *testing.T) {
 	allTypes := []byte{
 		MsgErrorReply,
 		MsgSessionCreated,
+		MsgSessionCreatedUtf8,
+		MsgSessionInfoUpdated,
 		MsgSessionClosed,
 		MsgCloseSession,
 		MsgRestoreSession,
@@ -156,6 +171,7 @@ func TestMsgNewSpecialRequestReplyMessageInvalidType(t *testing.T) {
 		MsgRequestBinary,
 		MsgRequestUtf8,
 		MsgRequestUtf16,
+		MsgReplyChunk,
 		MsgReplyBinary,
 		MsgReplyUtf8,
 		MsgReplyUtf16,
@@ -0,0 +1,91 @@
+//go:build go1.18
+// +build go1.18
+
+package message
+
+import (
+	"testing"
+
+	pld "github.com/qbeon/webwire-go/payload"
+	"github.com/stretchr/testify/require"
+)
+
+// FuzzParse tests whether Parse never panics on arbitrary input
+// and whether re-encoding successfully parsed requests, signals and replies
+// results in the same message when parsed again
+func FuzzParse(f *testing.F) {
+	id := [8]byte{1, 2, 3, 4, 5, 6, 7, 8}
+
+	// Seed the corpus with valid messages of all kinds
+	f.Add(NewRequestMessage(id, "req", pld.Binary, []byte("payload")))
+	f.Add(NewRequestMessage(id, "req", pld.Utf8, []byte("payload")))
+	f.Add(NewRequestMessage(id, "r", pld.Utf16, []byte("pl")))
+	f.Add(NewNamelessRequestMessage(MsgRequestBinary, id, []byte("payload")))
+	f.Add(NewSignalMessage("sig", pld.Binary, []byte("payload")))
+	f.Add(NewSignalMessage("s", pld.Utf16, []byte("pl")))
+	f.Add(NewReliableSignalMessage(id, "sig", pld.Utf8, []byte("payload")))
+	f.Add(NewSignalAckMessage(id))
+	f.Add(NewReplyMessage(id, pld.Binary, []byte("payload")))
+	f.Add(NewReplyMessage(id, pld.Utf16, []byte("pl")))
+	f.Add(NewReplyChunkMessage(id, []byte("chunk")))
+	f.Add(NewErrorReplyMessage(id, "CODE", "message"))
+	f.Add(NewSpecialRequestReplyMessage(MsgReplyShutdown, id))
+	f.Add(NewRetryAfterReplyMessage(MsgTooManyRequests, id, 1000))
+	f.Add(NewEmptyRequestMessage(MsgCloseSession, id))
+	f.Add(NewTypedRequestMessage(id, "req", "application/json", []byte("{}")))
+	f.Add(NewTypedReplyMessage(id, "application/json", []byte("{}")))
+	f.Add(NewTypedSignalMessage("sig", "text/plain", []byte("payload")))
+	f.Add([]byte{MsgSessionClosed})
+	f.Add([]byte{MsgRestoreSession, 1, 2, 3, 4, 5, 6, 7, 8, 'k'})
+
+	f.Fuzz(func(t *testing.T, encoded []byte) {
+		var parsed Message
+		typeDetermined, err := parsed.Parse(encoded)
+		if !typeDetermined || err != nil {
+			return
+		}
+
+		// Re-encode the parsed message
+		var reencoded []byte
+		data := parsed.Payload.Data
+		switch parsed.Type {
+		case MsgRequestBinary, MsgRequestUtf8, MsgRequestUtf16:
+			if parsed.Name == "" && len(data) < 1 ||
+				ValidateNameUTF8(parsed.Name) != nil {
+				return
+			}
+			reencoded = NewRequestMessage(
+				parsed.Identifier,
+				parsed.Name,
+				parsed.Payload.Encoding,
+				data,
+				ValidateNameUTF8,
+			)
+		case MsgSignalBinary, MsgSignalUtf8, MsgSignalUtf16:
+			if parsed.Name == "" && len(data) < 1 ||
+				ValidateNameUTF8(parsed.Name) != nil {
+				return
+			}
+			reencoded = NewSignalMessage(
+				parsed.Name,
+				parsed.Payload.Encoding,
+				data,
+				ValidateNameUTF8,
+			)
+		case MsgReplyBinary, MsgReplyUtf8, MsgReplyUtf16:
+			reencoded = NewReplyMessage(
+				parsed.Identifier,
+				parsed.Payload.Encoding,
+				data,
+			)
+		default:
+			return
+		}
+
+		var reparsed Message
+		typeDetermined, err = reparsed.Parse(reencoded)
+		require.True(t, typeDetermined)
+		require.NoError(t, err)
+		require.Equal(t, parsed, reparsed)
+	})
+}
@@ -0,0 +1,29 @@
+package message
+
+import (
+	"fmt"
+	"math/rand"
+	"os"
+	"strconv"
+	"testing"
+	"time"
+)
+
+// TestMain seeds the pseudo-random generator used to generate test messages.
+// The seed is printed and can be reused to reproduce a failed test run
+// by setting the WEBWIRE_TEST_SEED environment variable
+func TestMain(m *testing.M) {
+	seed := time.Now().UnixNano()
+	if env := os.Getenv("WEBWIRE_TEST_SEED"); env != "" {
+		parsed, err := strconv.ParseInt(env, 10, 64)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Invalid WEBWIRE_TEST_SEED: %s\n", err)
+			os.Exit(2)
+		}
+		seed = parsed
+	}
+	rand.Seed(seed)
+	fmt.Printf("WEBWIRE_TEST_SEED=%d\n", seed)
+
+	os.Exit(m.Run())
+}
@@ -1,6 +1,11 @@
 package message
 
-import pld "github.com/qbeon/webwire-go/payload"
+import (
+	"sync/atomic"
+	"time"
+
+	pld "github.com/qbeon/webwire-go/payload"
+)
 
 const (
 	// MsgMinLenSignal represents the minimum length
@@ -60,6 +65,14 @@ const (
 	//  4. payload (n bytes, optional or at least 2 bytes)
 	MsgMinLenReplyUtf16 = int(10)
 
+	// MsgMinLenReplyChunk represents the minimum length
+	// of reply chunk messages.
+	// Reply chunk message structure:
+	//  1. message type (1 byte)
+	//  2. message id (8 bytes)
+	//  3. payload chunk (n bytes, at least 1 byte)
+	MsgMinLenReplyChunk = int(10)
+
 	// MsgMinLenErrorReply represents the minimum length
 	// of error reply messages.
 	// Error reply message structure:
@@ -73,12 +86,33 @@ const (
 	//  5. error message (n bytes, UTF8 encoded, optional)
 	MsgMinLenErrorReply = int(11)
 
+	// MsgMinLenErrorReplyUtf16 represents the minimum length
+	// of UTF16 encoded error reply messages.
+	// UTF16 error reply message structure:
+	//  1. message type (1 byte)
+	//  2. message id (8 bytes)
+	//  3. error code length flag (1 byte, cannot be 0)
+	//  4. error code (
+	//    from 1 to 255 bytes,
+	//    length must correspond to the length flag
+	//  )
+	//  5. header padding (1 byte, present if the error code length is odd)
+	//  6. error message (n bytes, UTF16 encoded, optional)
+	MsgMinLenErrorReplyUtf16 = int(12)
+
+	// MsgMinLenRetryAfterReply represents the minimum length
+	// of a special reply message carrying a retry-after hint:
+	//  1. message type (1 byte)
+	//  2. message id (8 bytes)
+	//  3. retry-after in milliseconds (8 bytes)
+	MsgMinLenRetryAfterReply = int(17)
+
 	// MsgMinLenRestoreSession represents the minimum length
 	// of session restoration request messages.
 	// Session restoration request message structure:
 	//  1. message type (1 byte)
 	//  2. message id (8 bytes)
-	//  3. session key (n bytes, 7-bit ASCII encoded, at least 1 byte)
+	//  3. session key (n bytes, opaque binary, at least 1 byte)
 	MsgMinLenRestoreSession = int(10)
 
 	// MsgMinLenCloseSession represents the minimum length
@@ -95,15 +129,88 @@ const (
 	//  2. session key (n bytes, 7-bit ASCII encoded, at least 1 byte)
 	MsgMinLenSessionCreated = int(2)
 
+	// MsgMinLenSessionInfoUpdated represents the minimum length
+	// of session info update notification messages.
+	// Session info update notification message structure:
+	//  1. message type (1 byte)
+	//  2. session info (n bytes, UTF8 encoded JSON, at least 1 byte)
+	MsgMinLenSessionInfoUpdated = int(2)
+
 	// MsgMinLenSessionClosed represents the minimum length
 	// of session creation notification messages.
 	// Session destruction notification message structure:
 	//  1. message type (1 byte)
 	MsgMinLenSessionClosed = int(1)
+
+	// MsgMinLenPauseSignals represents the minimum length
+	// of signal pause requests.
+	// Signal pause request message structure:
+	//  1. message type (1 byte)
+	MsgMinLenPauseSignals = int(1)
+
+	// MsgMinLenResumeSignals represents the minimum length
+	// of signal resumption requests.
+	// Signal resumption request message structure:
+	//  1. message type (1 byte)
+	MsgMinLenResumeSignals = int(1)
+
+	// MsgMinLenSignalAck represents the minimum length
+	// of reliable signal acknowledgement messages.
+	// Reliable signal acknowledgement message structure:
+	//  1. message type (1 byte)
+	//  2. message id (8 bytes)
+	MsgMinLenSignalAck = int(9)
+
+	// MsgMinLenSignalTyped represents the minimum length
+	// of content-typed signal messages.
+	// Content-typed signal message structure:
+	//  1. message type (1 byte)
+	//  2. name length flag (1 byte)
+	//  3. name (n bytes, optional if name length flag is 0)
+	//  4. content type length flag (1 byte, cannot be 0)
+	//  5. content type (
+	//    from 1 to 255 bytes,
+	//    length must correspond to the length flag
+	//  )
+	//  6. payload (n bytes, optional)
+	MsgMinLenSignalTyped = int(4)
+
+	// MsgMinLenRequestTyped represents the minimum length
+	// of content-typed request messages.
+	// Content-typed request message structure:
+	//  1. message type (1 byte)
+	//  2. message id (8 bytes)
+	//  3. name length flag (1 byte)
+	//  4. name (from 0 to 255 bytes, optional if name length flag is 0)
+	//  5. content type length flag (1 byte, cannot be 0)
+	//  6. content type (
+	//    from 1 to 255 bytes,
+	//    length must correspond to the length flag
+	//  )
+	//  7. payload (n bytes, optional)
+	MsgMinLenRequestTyped = int(12)
+
+	// MsgMinLenReplyTyped represents the minimum length
+	// of content-typed reply messages.
+	// Content-typed reply message structure:
+	//  1. message type (1 byte)
+	//  2. message id (8 bytes)
+	//  3. content type length flag (1 byte, cannot be 0)
+	//  4. content type (
+	//    from 1 to 255 bytes,
+	//    length must correspond to the length flag
+	//  )
+	//  5. payload (n bytes, optional)
+	MsgMinLenReplyTyped = int(11)
 )
 
 const (
 	// SERVER
+	// The values of the special reply message types
+	// (MsgSpecialReplyMin to MsgSpecialReplyMax) are part of the protocol
+	// and are guaranteed to remain stable, clients map them to
+	// the according error types (for example MsgSessionsDisabled
+	// to webwire.SessionsDisabledErr)
 
 	// MsgErrorReply is sent by the server
 	// and represents an error-reply to a previously sent request
@@ -135,14 +242,61 @@ const (
 	// message violating the protocol
 	MsgReplyProtocolError = byte(6)
 
+	// MsgFeatureDisabled is sent by the server in response to a request
+	// of a message type that's disabled for the target server
+	MsgFeatureDisabled = byte(7)
+
+	// MsgTooManyRequests is sent by the server in response to a request
+	// exceeding the maximum number of in-flight requests per connection
+	MsgTooManyRequests = byte(8)
+
+	// MsgUnauthorized is sent by the server in response to a request
+	// requiring a session sent by a client without a session
+	MsgUnauthorized = byte(9)
+
+	// MsgMethodNotFound is sent by the server in response to a request
+	// of a name not allowed by the server
+	MsgMethodNotFound = byte(10)
+
+	// MsgServiceUnavailable is sent by the server in response to a request
+	// rejected due to a failing downstream dependency of the handler
+	MsgServiceUnavailable = byte(11)
+
+	// MsgRequestTimeout is sent by the server in response to a request
+	// the handler didn't reply to within the request timeout
+	MsgRequestTimeout = byte(12)
+
+	// MsgSessionOperationThrottled is sent by the server in response
+	// to a session restoration or closure request rejected due to
+	// the client exceeding the session operation rate limit
+	MsgSessionOperationThrottled = byte(13)
+
+	// MsgErrorReplyUtf16 is sent by the server
+	// and represents an error-reply to a previously sent request
+	// carrying a UTF16 encoded error message
+	MsgErrorReplyUtf16 = byte(20)
+
 	// MsgSessionCreated is sent by the server
 	// to notify the client about the session creation
+	// with a binary encoded session object
 	MsgSessionCreated = byte(21)
 
 	// MsgSessionClosed is sent by the server
 	// to notify the client about the session destruction
 	MsgSessionClosed = byte(22)
 
+	// MsgSessionCreatedUtf8 is sent by the server
+	// to notify the client about the session creation
+	// with a UTF8 encoded session object.
+	// Introduced in protocol version 1.5, clients of older versions
+	// only support MsgSessionCreated
+	MsgSessionCreatedUtf8 = byte(23)
+
+	// MsgSessionInfoUpdated is sent by the server
+	// to notify the client about the patched info of the session
+	// carrying the entire UTF8 encoded session info
+	MsgSessionInfoUpdated = byte(24)
+
 	// CLIENT
 
 	// MsgCloseSession is sent by the client
@@ -154,6 +308,21 @@ const (
 	// to request session restoration
 	MsgRestoreSession = byte(32)
 
+	// MsgPauseSignals is sent by the client to request the server
+	// to stop sending non-critical signals until MsgResumeSignals is sent.
+	// It doesn't require a reply
+	MsgPauseSignals = byte(33)
+
+	// MsgResumeSignals is sent by the client to request the server
+	// to resume sending signals paused by MsgPauseSignals.
+	// It doesn't require a reply
+	MsgResumeSignals = byte(34)
+
+	// MsgSignalAck is sent by the client to acknowledge the reception
+	// of a reliable signal identified by the message id.
+	// It doesn't require a reply
+	MsgSignalAck = byte(35)
+
 	// SIGNAL
 	// Signals are sent by both the client and the server
 	// and represents a one-way signal message that doesn't require a reply
@@ -167,6 +336,27 @@ const (
 	// MsgSignalUtf16 represents a signal with UTF16 encoded payload
 	MsgSignalUtf16 = byte(65)
 
+	// RELIABLE SIGNAL
+	// Reliable signals are sent by the server and must be acknowledged
+	// by the client using MsgSignalAck. They're structured like requests
+	// carrying a message id identifying the acknowledged signal
+
+	// MsgReliableSignalBinary represents a reliable signal
+	// with binary payload
+	MsgReliableSignalBinary = byte(66)
+
+	// MsgReliableSignalUtf8 represents a reliable signal
+	// with UTF8 encoded payload
+	MsgReliableSignalUtf8 = byte(67)
+
+	// MsgReliableSignalUtf16 represents a reliable signal
+	// with UTF16 encoded payload
+	MsgReliableSignalUtf16 = byte(68)
+
+	// MsgSignalTyped represents a signal with a binary payload
+	// of an application-level content type carried in the message
+	MsgSignalTyped = byte(69)
+
 	// REQUEST
 	// Requests are sent by the client
 	// and represents a roundtrip to the server requiring a reply
@@ -180,10 +370,18 @@ const (
 	// MsgRequestUtf16 represents a request with a UTF16 encoded payload
 	MsgRequestUtf16 = byte(129)
 
+	// MsgRequestTyped represents a request with a binary payload
+	// of an application-level content type carried in the message
+	MsgRequestTyped = byte(130)
+
 	// REPLY
 	// Replies are sent by the server
 	// and represent a reply to a previously sent request
 
+	// MsgReplyChunk represents a chunk of a streamed reply payload.
+	// A streamed reply is terminated by a regular reply message
+	MsgReplyChunk = byte(190)
+
 	// MsgReplyBinary represents a reply with a binary payload
 	MsgReplyBinary = byte(191)
 
@@ -192,6 +390,10 @@ const (
 
 	// MsgReplyUtf16 represents a reply with a UTF16 encoded payload
 	MsgReplyUtf16 = byte(193)
+
+	// MsgReplyTyped represents a reply with a binary payload
+	// of an application-level content type carried in the message
+	MsgReplyTyped = byte(194)
 )
 
 // Message represents a WebWire protocol message
@@ -200,6 +402,25 @@ type Message struct {
 	Identifier [8]byte
 	Name       string
 	Payload    pld.Payload
+
+	// RetryAfter is the retry-after hint of special reply messages
+	// composed by NewRetryAfterReplyMessage, it's 0 if there's none
+	RetryAfter time.Duration
+
+	// replied is set to 1 once a reply to the message was sent
+	replied int32
+}
+
+// MarkReplied marks the message as replied and returns true
+// if it wasn't already marked before, otherwise returns false
+// indicating that a reply was already sent
+func (msg *Message) MarkReplied() bool {
+	return atomic.CompareAndSwapInt32(&msg.replied, 0, 1)
+}
+
+// Replied returns true if the message was already replied to
+func (msg *Message) Replied() bool {
+	return atomic.LoadInt32(&msg.replied) == 1
 }
 
 // RequiresReply returns true if a message of this type requires a reply,
@@ -215,6 +436,8 @@ func (msg *Message) RequiresReply() bool {
 	case MsgRequestUtf8:
 		fallthrough
 	case MsgRequestUtf16:
+		fallthrough
+	case MsgRequestTyped:
 		return true
 	}
 	return false
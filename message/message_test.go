@@ -93,3 +93,33 @@ func TestRequiresReplyRequestUtf16(t *testing.T) {
 		"Expected a UTF16 request message to require a reply",
 	)
 }
+
+// TestRequiresReplyRequestTyped tests the RequiresReply method
+// with a content-typed request message
+func TestRequiresReplyRequestTyped(t *testing.T) {
+	msg := &Message{}
+	_, err := msg.Parse(NewTypedRequestMessage(
+		genRndMsgIdentifier(),
+		"samplename",
+		"application/protobuf",
+		[]byte("random"),
+	))
+	require.NoError(t, err)
+
+	require.True(t,
+		msg.RequiresReply(),
+		"Expected a content-typed request message to require a reply",
+	)
+}
+
+// TestMsgMarkReplied tests marking a message as replied
+func TestMsgMarkReplied(t *testing.T) {
+	msg := Message{Type: MsgRequestBinary}
+	require.False(t, msg.Replied())
+
+	// Expect only the first attempt to succeed
+	require.True(t, msg.MarkReplied())
+	require.True(t, msg.Replied())
+	require.False(t, msg.MarkReplied())
+	require.True(t, msg.Replied())
+}
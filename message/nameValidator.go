@@ -0,0 +1,72 @@
+package message
+
+import (
+	"fmt"
+	"unicode"
+	"unicode/utf8"
+)
+
+// NameValidator defines the type of a function verifying the validity
+// of a message name. It must return an error if the name contains
+// characters that aren't allowed
+type NameValidator func(name string) error
+
+// ValidateNameASCII implements the default NameValidator allowing names
+// consisting of printable 7-bit ASCII characters (32-126) only
+func ValidateNameASCII(name string) error {
+	for i := 0; i < len(name); i++ {
+		char := name[i]
+		if char < 32 || char > 126 {
+			return fmt.Errorf(
+				"Unsupported character in message name: %s",
+				string(char),
+			)
+		}
+	}
+	return nil
+}
+
+// ValidateNameUTF8 implements a NameValidator allowing names consisting
+// of any valid UTF-8 encoded, non-control characters
+func ValidateNameUTF8(name string) error {
+	if !utf8.ValidString(name) {
+		return fmt.Errorf("Message name is not valid UTF-8: %q", name)
+	}
+	for _, char := range name {
+		if unicode.IsControl(char) {
+			return fmt.Errorf(
+				"Unsupported character in message name: %q",
+				char,
+			)
+		}
+	}
+	return nil
+}
+
+// LimitNameLength returns a NameValidator rejecting names longer than
+// the given maximum number of bytes before verifying them
+// using the given validator, which defaults to ValidateNameASCII if nil
+func LimitNameLength(maxLength int, validator NameValidator) NameValidator {
+	if validator == nil {
+		validator = ValidateNameASCII
+	}
+	return func(name string) error {
+		if len(name) > maxLength {
+			return fmt.Errorf(
+				"Message name too long (%d bytes, at most %d allowed)",
+				len(name),
+				maxLength,
+			)
+		}
+		return validator(name)
+	}
+}
+
+// selectNameValidator returns the first of the given optional validators
+// or the default ValidateNameASCII validator if none is given
+func selectNameValidator(validators []NameValidator) NameValidator {
+	if len(validators) > 0 && validators[0] != nil {
+		return validators[0]
+	}
+	return ValidateNameASCII
+}
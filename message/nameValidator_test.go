@@ -0,0 +1,81 @@
+package message
+
+import (
+	"testing"
+
+	pld "github.com/qbeon/webwire-go/payload"
+	"github.com/stretchr/testify/require"
+)
+
+// TestMsgValidateNameASCII tests the default ASCII name validator
+func TestMsgValidateNameASCII(t *testing.T) {
+	require.NoError(t, ValidateNameASCII(""))
+	require.NoError(t, ValidateNameASCII("api.users.get"))
+	require.Error(t, ValidateNameASCII("api.\n"))
+	require.Error(t, ValidateNameASCII("api.ユーザー"))
+}
+
+// TestMsgValidateNameUTF8 tests the UTF8 name validator
+func TestMsgValidateNameUTF8(t *testing.T) {
+	require.NoError(t, ValidateNameUTF8("api.users.get"))
+	require.NoError(t, ValidateNameUTF8("api.ユーザー.取得"))
+	require.Error(t, ValidateNameUTF8("api.\n"))
+	require.Error(t, ValidateNameUTF8(string([]byte{0xff, 0xfe})))
+}
+
+// TestMsgLimitNameLength tests the name length limiting validator
+func TestMsgLimitNameLength(t *testing.T) {
+	validate := LimitNameLength(4, nil)
+	require.NoError(t, validate(""))
+	require.NoError(t, validate("name"))
+	require.Error(t, validate("names"))
+	require.Error(t, validate("na\n"))
+
+	validate = LimitNameLength(12, ValidateNameUTF8)
+	require.NoError(t, validate("ユーザー"))
+	require.Error(t, validate("ユーザーズ"))
+}
+
+// TestMsgNewReqMsgNameValidator tests NewRequestMessage
+// using a custom name validator
+func TestMsgNewReqMsgNameValidator(t *testing.T) {
+	id := genRndMsgIdentifier()
+	name := "api.ユーザー.取得"
+
+	// Expect the default validator to reject the name
+	require.Panics(t, func() {
+		NewRequestMessage(id, name, pld.Binary, []byte("payload"))
+	})
+
+	encoded := NewRequestMessage(
+		id,
+		name,
+		pld.Binary,
+		[]byte("payload"),
+		ValidateNameUTF8,
+	)
+	parsed := tryParseNoErr(t, encoded)
+	require.Equal(t, name, parsed.Name)
+	require.Equal(t, []byte("payload"), parsed.Payload.Data)
+}
+
+// TestMsgNewSigMsgNameValidator tests NewSignalMessage
+// using a custom name validator
+func TestMsgNewSigMsgNameValidator(t *testing.T) {
+	name := "api.ユーザー.更新"
+
+	// Expect the default validator to reject the name
+	require.Panics(t, func() {
+		NewSignalMessage(name, pld.Utf8, []byte("payload"))
+	})
+
+	encoded := NewSignalMessage(
+		name,
+		pld.Utf8,
+		[]byte("payload"),
+		ValidateNameUTF8,
+	)
+	parsed := tryParseNoErr(t, encoded)
+	require.Equal(t, name, parsed.Name)
+	require.Equal(t, []byte("payload"), parsed.Payload.Data)
+}
@@ -3,11 +3,51 @@ package message
 import "fmt"
 
 // NewErrorReplyMessage composes a new error reply message
-// and returns its binary representation
+// and returns its binary representation.
+// The error code must consist of printable ASCII characters only
+// while the error message is written as is and is expected
+// to be UTF8 encoded
 func NewErrorReplyMessage(
 	requestIdent [8]byte,
 	code,
 	message string,
+) (msg []byte) {
+	return newErrorReplyMessage(
+		MsgErrorReply,
+		requestIdent,
+		code,
+		[]byte(message),
+	)
+}
+
+// NewErrorReplyMessageUtf16 composes a new error reply message carrying
+// a UTF16 encoded error message and returns its binary representation.
+// The error code must consist of printable ASCII characters only
+// while the error message must be UTF16 encoded
+func NewErrorReplyMessageUtf16(
+	requestIdent [8]byte,
+	code string,
+	message []byte,
+) (msg []byte) {
+	if len(message)%2 != 0 {
+		panic(fmt.Errorf(
+			"Invalid UTF16 error reply message length: %d",
+			len(message),
+		))
+	}
+	return newErrorReplyMessage(
+		MsgErrorReplyUtf16,
+		requestIdent,
+		code,
+		message,
+	)
+}
+
+func newErrorReplyMessage(
+	msgType byte,
+	requestIdent [8]byte,
+	code string,
+	message []byte,
 ) (msg []byte) {
 	if len(code) < 1 {
 		panic(fmt.Errorf(
@@ -21,11 +61,20 @@ func NewErrorReplyMessage(
 		))
 	}
 
+	// Check if a header padding is necessary.
+	// A padding is necessary if the error message is UTF16 encoded
+	// but not properly aligned due to a header length not divisible by 2
+	headerPadding := msgType == MsgErrorReplyUtf16 && len(code)%2 != 0
+
 	// Determine total message length
-	msg = make([]byte, 10+len(code)+len(message))
+	messageSize := 10 + len(code) + len(message)
+	if headerPadding {
+		messageSize++
+	}
+	msg = make([]byte, messageSize)
 
 	// Write message type flag
-	msg[0] = MsgErrorReply
+	msg[0] = msgType
 
 	// Write request identifier
 	for i := 0; i < 8; i++ {
@@ -49,10 +98,15 @@ func NewErrorReplyMessage(
 
 	errMessageOffset := 10 + len(code)
 
-	// Write error message
-	for i := 0; i < len(message); i++ {
-		msg[errMessageOffset+i] = message[i]
+	// Write header padding byte if the error message
+	// requires proper alignment
+	if headerPadding {
+		msg[errMessageOffset] = 0
+		errMessageOffset++
 	}
 
+	// Write error message
+	copy(msg[errMessageOffset:], message)
+
 	return msg
 }
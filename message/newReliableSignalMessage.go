@@ -0,0 +1,55 @@
+package message
+
+import (
+	pld "github.com/qbeon/webwire-go/payload"
+)
+
+// NewReliableSignalMessage composes a new named reliable signal message
+// and returns its binary representation.
+// Reliable signal messages share the structure of request messages
+// and only differ in the message type.
+// The name is verified by the optional name validator
+// which defaults to ValidateNameASCII
+func NewReliableSignalMessage(
+	identifier [8]byte,
+	name string,
+	payloadEncoding pld.Encoding,
+	payloadData []byte,
+	nameValidator ...NameValidator,
+) (msg []byte) {
+	msg = NewRequestMessage(
+		identifier,
+		name,
+		payloadEncoding,
+		payloadData,
+		nameValidator...,
+	)
+
+	// Overwrite the message type flag
+	switch msg[0] {
+	case MsgRequestBinary:
+		msg[0] = MsgReliableSignalBinary
+	case MsgRequestUtf8:
+		msg[0] = MsgReliableSignalUtf8
+	case MsgRequestUtf16:
+		msg[0] = MsgReliableSignalUtf16
+	}
+
+	return msg
+}
+
+// NewSignalAckMessage composes a new reliable signal acknowledgement
+// message and returns its binary representation
+func NewSignalAckMessage(identifier [8]byte) (msg []byte) {
+	msg = make([]byte, MsgMinLenSignalAck)
+
+	// Write message type flag
+	msg[0] = MsgSignalAck
+
+	// Write signal identifier
+	for i := 0; i < 8; i++ {
+		msg[1+i] = identifier[i]
+	}
+
+	return msg
+}
@@ -0,0 +1,29 @@
+package message
+
+import "fmt"
+
+// NewReplyChunkMessage composes a new reply chunk message
+// and returns its binary representation
+func NewReplyChunkMessage(
+	requestIdentifier [8]byte,
+	chunk []byte,
+) (msg []byte) {
+	if len(chunk) < 1 {
+		panic(fmt.Errorf("Invalid reply chunk, chunks mustn't be empty"))
+	}
+
+	msg = make([]byte, 9+len(chunk))
+
+	// Write message type flag
+	msg[0] = MsgReplyChunk
+
+	// Write request identifier
+	for i := 0; i < 8; i++ {
+		msg[1+i] = requestIdentifier[i]
+	}
+
+	// Write payload chunk
+	copy(msg[9:], chunk)
+
+	return msg
+}
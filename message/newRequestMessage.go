@@ -7,12 +7,15 @@ import (
 )
 
 // NewRequestMessage composes a new named request message
-// and returns its binary representation
+// and returns its binary representation.
+// The name is verified by the optional name validator
+// which defaults to ValidateNameASCII
 func NewRequestMessage(
 	identifier [8]byte,
 	name string,
 	payloadEncoding pld.Encoding,
 	payloadData []byte,
+	nameValidator ...NameValidator,
 ) (msg []byte) {
 	// Require either a name, or a payload or both, but don't allow none
 	if len(name) < 1 && len(payloadData) < 1 {
@@ -29,6 +32,11 @@ func NewRequestMessage(
 		))
 	}
 
+	// Verify name characters
+	if err := selectNameValidator(nameValidator)(name); err != nil {
+		panic(fmt.Errorf("Invalid request message name: %s", err))
+	}
+
 	// Verify payload data validity in case of UTF16 encoding
 	if payloadEncoding == pld.Utf16 && len(payloadData)%2 != 0 {
 		panic(fmt.Errorf(
@@ -70,16 +78,7 @@ func NewRequestMessage(
 	msg[9] = byte(len(name))
 
 	// Write name
-	for i := 0; i < len(name); i++ {
-		char := name[i]
-		if char < 32 || char > 126 {
-			panic(fmt.Errorf(
-				"Unsupported character in request name: %s",
-				string(char),
-			))
-		}
-		msg[10+i] = char
-	}
+	copy(msg[10:], name)
 
 	// Write header padding byte if the payload requires proper alignment
 	payloadOffset := 10 + len(name)
@@ -0,0 +1,34 @@
+package message
+
+import (
+	"encoding/binary"
+	"time"
+)
+
+// NewRetryAfterReplyMessage composes a new special request reply message
+// carrying a hint about when the rejected request may be retried.
+// The hint is encoded in milliseconds as a big-endian unsigned 64-bit integer
+// following the request identifier, negative durations are encoded as 0.
+// Panics if the given message type isn't a special reply message type
+// (see IsSpecialReplyType)
+func NewRetryAfterReplyMessage(
+	msgType byte,
+	reqIdent [8]byte,
+	retryAfter time.Duration,
+) []byte {
+	msg := make([]byte, MsgMinLenRetryAfterReply)
+
+	// Write message type flag and request identifier
+	copy(msg, NewSpecialRequestReplyMessage(msgType, reqIdent))
+
+	// Write retry-after hint
+	if retryAfter < 0 {
+		retryAfter = 0
+	}
+	binary.BigEndian.PutUint64(
+		msg[9:],
+		uint64(retryAfter/time.Millisecond),
+	)
+
+	return msg
+}
@@ -7,12 +7,22 @@ import (
 )
 
 // NewSignalMessage composes a new named signal message
-// and returns its binary representation
+// and returns its binary representation.
+// The name is verified by the optional name validator
+// which defaults to ValidateNameASCII
 func NewSignalMessage(
 	name string,
 	payloadEncoding pld.Encoding,
 	payloadData []byte,
+	nameValidator ...NameValidator,
 ) (msg []byte) {
+	// Require either a name, or a payload or both, but don't allow none
+	if len(name) < 1 && len(payloadData) < 1 {
+		panic(fmt.Errorf(
+			"Signal message requires either a name, or a payload, or both",
+		))
+	}
+
 	if len(name) > 255 {
 		panic(fmt.Errorf(
 			"Unsupported request message name length: %d",
@@ -20,6 +30,11 @@ func NewSignalMessage(
 		))
 	}
 
+	// Verify name characters
+	if err := selectNameValidator(nameValidator)(name); err != nil {
+		panic(fmt.Errorf("Invalid signal message name: %s", err))
+	}
+
 	// Verify payload data validity in case of UTF16 encoding
 	if payloadEncoding == pld.Utf16 && len(payloadData)%2 != 0 {
 		panic(fmt.Errorf(
@@ -56,16 +71,7 @@ func NewSignalMessage(
 	msg[1] = byte(len(name))
 
 	// Write name
-	for i := 0; i < len(name); i++ {
-		char := name[i]
-		if char < 32 || char > 126 {
-			panic(fmt.Errorf(
-				"Unsupported character in request name: %s",
-				string(char),
-			))
-		}
-		msg[2+i] = char
-	}
+	copy(msg[2:], name)
 
 	// Write header padding byte if the payload requires proper alignment
 	payloadOffset := 2 + len(name)
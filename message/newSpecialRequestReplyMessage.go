@@ -2,22 +2,11 @@ package message
 
 import "fmt"
 
-// NewSpecialRequestReplyMessage composes a new special request reply message
+// NewSpecialRequestReplyMessage composes a new special request reply message.
+// Panics if the given message type isn't a special reply message type
+// (see IsSpecialReplyType)
 func NewSpecialRequestReplyMessage(msgType byte, reqIdent [8]byte) []byte {
-	switch msgType {
-	case MsgInternalError:
-		break
-	case MsgMaxSessConnsReached:
-		break
-	case MsgSessionNotFound:
-		break
-	case MsgSessionsDisabled:
-		break
-	case MsgReplyShutdown:
-		break
-	case MsgReplyProtocolError:
-		break
-	default:
+	if !IsSpecialReplyType(msgType) {
 		panic(fmt.Errorf(
 			"Message type (%d) doesn't represent a special reply message",
 			msgType,
@@ -0,0 +1,121 @@
+package message
+
+import (
+	"fmt"
+
+	pld "github.com/qbeon/webwire-go/payload"
+)
+
+// NewTypedSignalMessage composes a new named signal message carrying
+// a binary payload of the given application-level content type
+// and returns its binary representation.
+// The content type must consist of 1 to 255 printable ASCII characters.
+// The name is verified by the optional name validator
+// which defaults to ValidateNameASCII
+func NewTypedSignalMessage(
+	name,
+	contentType string,
+	payloadData []byte,
+	nameValidator ...NameValidator,
+) (msg []byte) {
+	msg = NewSignalMessage(
+		name,
+		pld.Binary,
+		contentTypeHeader(contentType, payloadData),
+		nameValidator...,
+	)
+
+	// Overwrite the message type flag
+	msg[0] = MsgSignalTyped
+
+	return msg
+}
+
+// NewTypedRequestMessage composes a new named request message carrying
+// a binary payload of the given application-level content type
+// and returns its binary representation.
+// The content type must consist of 1 to 255 printable ASCII characters.
+// The name is verified by the optional name validator
+// which defaults to ValidateNameASCII
+func NewTypedRequestMessage(
+	identifier [8]byte,
+	name,
+	contentType string,
+	payloadData []byte,
+	nameValidator ...NameValidator,
+) (msg []byte) {
+	msg = NewRequestMessage(
+		identifier,
+		name,
+		pld.Binary,
+		contentTypeHeader(contentType, payloadData),
+		nameValidator...,
+	)
+
+	// Overwrite the message type flag
+	msg[0] = MsgRequestTyped
+
+	return msg
+}
+
+// NewTypedReplyMessage composes a new reply message carrying
+// a binary payload of the given application-level content type
+// and returns its binary representation.
+// The content type must consist of 1 to 255 printable ASCII characters
+func NewTypedReplyMessage(
+	requestIdentifier [8]byte,
+	contentType string,
+	payloadData []byte,
+) (msg []byte) {
+	msg = NewReplyMessage(
+		requestIdentifier,
+		pld.Binary,
+		contentTypeHeader(contentType, payloadData),
+	)
+
+	// Overwrite the message type flag
+	msg[0] = MsgReplyTyped
+
+	return msg
+}
+
+// ValidateContentType returns an error if the given content type
+// doesn't consist of 1 to 255 printable ASCII characters
+func ValidateContentType(contentType string) error {
+	if len(contentType) < 1 {
+		return fmt.Errorf("Missing content type")
+	} else if len(contentType) > 255 {
+		return fmt.Errorf("Content type too long (%d)", len(contentType))
+	}
+	for i := 0; i < len(contentType); i++ {
+		char := contentType[i]
+		if char < 32 || char > 126 {
+			return fmt.Errorf(
+				"Unsupported character in content type: %s",
+				string(char),
+			)
+		}
+	}
+	return nil
+}
+
+// contentTypeHeader returns the payload data prefixed
+// by the content type and its length flag
+func contentTypeHeader(contentType string, payloadData []byte) []byte {
+	if err := ValidateContentType(contentType); err != nil {
+		panic(fmt.Errorf("Invalid content-typed message: %s", err))
+	}
+
+	data := make([]byte, 1+len(contentType)+len(payloadData))
+
+	// Write content type length flag
+	data[0] = byte(len(contentType))
+
+	// Write content type
+	copy(data[1:], contentType)
+
+	// Write payload
+	copy(data[1+len(contentType):], payloadData)
+
+	return data
+}
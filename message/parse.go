@@ -1,7 +1,9 @@
 package message
 
 import (
+	"encoding/binary"
 	"fmt"
+	"time"
 
 	pld "github.com/qbeon/webwire-go/payload"
 )
@@ -20,12 +22,25 @@ func (msg *Message) Parse(message []byte) (parsedMsgType bool, err error) {
 
 	// Request error reply message
 	case MsgErrorReply:
+		payloadEncoding = pld.Utf8
 		err = msg.parseErrorReply(message)
+	case MsgErrorReplyUtf16:
+		payloadEncoding = pld.Utf16
+		err = msg.parseErrorReplyUtf16(message)
 
 	// Session creation notification message
 	case MsgSessionCreated:
+		payloadEncoding = pld.Binary
+		err = msg.parseSessionCreated(message)
+	case MsgSessionCreatedUtf8:
+		payloadEncoding = pld.Utf8
 		err = msg.parseSessionCreated(message)
 
+	// Session info update notification message
+	case MsgSessionInfoUpdated:
+		payloadEncoding = pld.Utf8
+		err = msg.parseSessionInfoUpdated(message)
+
 	// Session closure notification message
 	case MsgSessionClosed:
 		err = msg.parseSessionClosed(message)
@@ -34,6 +49,16 @@ func (msg *Message) Parse(message []byte) (parsedMsgType bool, err error) {
 	case MsgCloseSession:
 		err = msg.parseCloseSession(message)
 
+	// Signal flow control messages
+	case MsgPauseSignals:
+		err = msg.parsePauseSignals(message)
+	case MsgResumeSignals:
+		err = msg.parseResumeSignals(message)
+
+	// Reliable signal acknowledgement message
+	case MsgSignalAck:
+		err = msg.parseSignalAck(message)
+
 	// Signal messages
 	case MsgSignalBinary:
 		payloadEncoding = pld.Binary
@@ -44,6 +69,20 @@ func (msg *Message) Parse(message []byte) (parsedMsgType bool, err error) {
 	case MsgSignalUtf16:
 		payloadEncoding = pld.Utf16
 		err = msg.parseSignalUtf16(message)
+	case MsgSignalTyped:
+		payloadEncoding = pld.Binary
+		err = msg.parseSignalTyped(message)
+
+	// Reliable signal messages share the structure of request messages
+	case MsgReliableSignalBinary:
+		payloadEncoding = pld.Binary
+		err = msg.parseRequest(message)
+	case MsgReliableSignalUtf8:
+		payloadEncoding = pld.Utf8
+		err = msg.parseRequest(message)
+	case MsgReliableSignalUtf16:
+		payloadEncoding = pld.Utf16
+		err = msg.parseRequestUtf16(message)
 
 	// Request messages
 	case MsgRequestBinary:
@@ -55,6 +94,9 @@ func (msg *Message) Parse(message []byte) (parsedMsgType bool, err error) {
 	case MsgRequestUtf16:
 		payloadEncoding = pld.Utf16
 		err = msg.parseRequestUtf16(message)
+	case MsgRequestTyped:
+		payloadEncoding = pld.Binary
+		err = msg.parseRequestTyped(message)
 
 	// Reply messages
 	case MsgReplyBinary:
@@ -66,27 +108,25 @@ func (msg *Message) Parse(message []byte) (parsedMsgType bool, err error) {
 	case MsgReplyUtf16:
 		payloadEncoding = pld.Utf16
 		err = msg.parseReplyUtf16(message)
+	case MsgReplyTyped:
+		payloadEncoding = pld.Binary
+		err = msg.parseReplyTyped(message)
+	case MsgReplyChunk:
+		payloadEncoding = pld.Binary
+		err = msg.parseReplyChunk(message)
 
 	// Session restoration request message
 	case MsgRestoreSession:
 		err = msg.parseRestoreSession(message)
 
-	// Special reply messages
-	case MsgReplyShutdown:
-		err = msg.parseSpecialReplyMessage(message)
-	case MsgInternalError:
-		err = msg.parseSpecialReplyMessage(message)
-	case MsgSessionNotFound:
-		err = msg.parseSpecialReplyMessage(message)
-	case MsgMaxSessConnsReached:
-		err = msg.parseSpecialReplyMessage(message)
-	case MsgSessionsDisabled:
-		err = msg.parseSpecialReplyMessage(message)
-	case MsgReplyProtocolError:
-		err = msg.parseSpecialReplyMessage(message)
-
-	// Ignore messages of invalid message type
 	default:
+		// Special reply messages
+		if IsSpecialReplyType(msgType) {
+			err = msg.parseSpecialReplyMessage(message)
+			break
+		}
+
+		// Ignore messages of invalid message type
 		return false, nil
 	}
 
@@ -341,12 +381,30 @@ func (msg *Message) parseReplyUtf16(message []byte) error {
 
 	// Read payload
 	msg.Payload = pld.Payload{
+		Encoding: pld.Utf16,
 		// Take header padding byte into account
 		Data: message[10:],
 	}
 	return nil
 }
 
+func (msg *Message) parseReplyChunk(message []byte) error {
+	if len(message) < MsgMinLenReplyChunk {
+		return fmt.Errorf("Invalid reply chunk message, too short")
+	}
+
+	// Read identifier
+	var id [8]byte
+	copy(id[:], message[1:9])
+	msg.Identifier = id
+
+	// Read payload chunk
+	msg.Payload = pld.Payload{
+		Data: message[9:],
+	}
+	return nil
+}
+
 // parseErrorReply parses the given message assuming it's an error reply message
 // parsing the error code into the name field
 // and the UTF8 encoded error message into the payload
@@ -393,6 +451,59 @@ func (msg *Message) parseErrorReply(message []byte) error {
 	return nil
 }
 
+// parseErrorReplyUtf16 parses the given message assuming it's
+// a UTF16 encoded error reply message parsing the error code
+// into the name field and the UTF16 encoded error message into the payload
+func (msg *Message) parseErrorReplyUtf16(message []byte) error {
+	if len(message) < MsgMinLenErrorReplyUtf16 {
+		return fmt.Errorf("Invalid UTF16 error reply message, too short")
+	}
+
+	if len(message)%2 != 0 {
+		return fmt.Errorf(
+			"Unaligned UTF16 encoded error reply message " +
+				"(probably missing header padding)",
+		)
+	}
+
+	// Read identifier
+	var id [8]byte
+	copy(id[:], message[1:9])
+	msg.Identifier = id
+
+	// Read error code length flag
+	errCodeLen := int(byte(message[9:10][0]))
+	if errCodeLen < 1 {
+		return fmt.Errorf(
+			"Invalid UTF16 error reply message, " +
+				"error code length flag is zero",
+		)
+	}
+
+	// Take the header padding into account if the error code length is odd
+	errMessageOffset := 10 + errCodeLen
+	if errCodeLen%2 != 0 {
+		errMessageOffset++
+	}
+
+	// Verify total message size to prevent segmentation faults
+	// caused by inconsistent flags
+	if len(message) < errMessageOffset {
+		return fmt.Errorf(
+			"Invalid UTF16 error reply message, "+
+				"too short for specified code length (%d)",
+			errCodeLen,
+		)
+	}
+
+	msg.Name = string(message[10 : 10+errCodeLen])
+	msg.Payload = pld.Payload{
+		Encoding: pld.Utf16,
+		Data:     message[errMessageOffset:],
+	}
+	return nil
+}
+
 func (msg *Message) parseRestoreSession(message []byte) error {
 	if len(message) < MsgMinLenRestoreSession {
 		return fmt.Errorf(
@@ -405,7 +516,8 @@ func (msg *Message) parseRestoreSession(message []byte) error {
 	copy(id[:], message[1:9])
 	msg.Identifier = id
 
-	// Read payload
+	// Read the session key as opaque binary spanning the rest of the frame,
+	// there's no name length flag involved
 	msg.Payload = pld.Payload{
 		Data: message[9:],
 	}
@@ -440,6 +552,19 @@ func (msg *Message) parseSessionCreated(message []byte) error {
 	return nil
 }
 
+func (msg *Message) parseSessionInfoUpdated(message []byte) error {
+	if len(message) < MsgMinLenSessionInfoUpdated {
+		return fmt.Errorf(
+			"Invalid session info update notification message, too short",
+		)
+	}
+
+	msg.Payload = pld.Payload{
+		Data: message[1:],
+	}
+	return nil
+}
+
 func (msg *Message) parseSessionClosed(message []byte) error {
 	if len(message) != MsgMinLenSessionClosed {
 		return fmt.Errorf(
@@ -449,6 +574,20 @@ func (msg *Message) parseSessionClosed(message []byte) error {
 	return nil
 }
 
+func (msg *Message) parsePauseSignals(message []byte) error {
+	if len(message) != MsgMinLenPauseSignals {
+		return fmt.Errorf("Invalid signal pause request message, too long")
+	}
+	return nil
+}
+
+func (msg *Message) parseResumeSignals(message []byte) error {
+	if len(message) != MsgMinLenResumeSignals {
+		return fmt.Errorf("Invalid signal resumption request message, too long")
+	}
+	return nil
+}
+
 func (msg *Message) parseSpecialReplyMessage(message []byte) error {
 	if len(message) < 9 {
 		return fmt.Errorf("Invalid special reply message, too short")
@@ -459,5 +598,98 @@ func (msg *Message) parseSpecialReplyMessage(message []byte) error {
 	copy(id[:], message[1:9])
 	msg.Identifier = id
 
+	// Read the optional retry-after hint
+	if len(message) >= MsgMinLenRetryAfterReply {
+		msg.RetryAfter = time.Duration(
+			binary.BigEndian.Uint64(message[9:17]),
+		) * time.Millisecond
+	}
+
+	return nil
+}
+
+func (msg *Message) parseSignalAck(message []byte) error {
+	if len(message) < MsgMinLenSignalAck {
+		return fmt.Errorf(
+			"Invalid signal acknowledgement message, too short",
+		)
+	}
+	if len(message) > MsgMinLenSignalAck {
+		return fmt.Errorf(
+			"Invalid signal acknowledgement message, too long",
+		)
+	}
+
+	// Read identifier
+	var id [8]byte
+	copy(id[:], message[1:9])
+	msg.Identifier = id
+
+	return nil
+}
+
+func (msg *Message) parseSignalTyped(message []byte) error {
+	if len(message) < MsgMinLenSignalTyped {
+		return fmt.Errorf("Invalid content-typed signal message, too short")
+	}
+	if err := msg.parseSignal(message); err != nil {
+		return err
+	}
+	return msg.parseContentType()
+}
+
+func (msg *Message) parseRequestTyped(message []byte) error {
+	if len(message) < MsgMinLenRequestTyped {
+		return fmt.Errorf("Invalid content-typed request message, too short")
+	}
+	if err := msg.parseRequest(message); err != nil {
+		return err
+	}
+	return msg.parseContentType()
+}
+
+func (msg *Message) parseReplyTyped(message []byte) error {
+	if len(message) < MsgMinLenReplyTyped {
+		return fmt.Errorf("Invalid content-typed reply message, too short")
+	}
+	if err := msg.parseReply(message); err != nil {
+		return err
+	}
+	return msg.parseContentType()
+}
+
+// parseContentType splits the content type header off the payload data
+// of content-typed messages
+func (msg *Message) parseContentType() error {
+	data := msg.Payload.Data
+	if len(data) < 2 {
+		return fmt.Errorf(
+			"Invalid content-typed message, missing content type",
+		)
+	}
+
+	// Read content type length
+	contentTypeLen := int(data[0])
+	if contentTypeLen < 1 {
+		return fmt.Errorf(
+			"Invalid content-typed message, content type length flag is 0",
+		)
+	}
+
+	// Verify total message size to prevent segmentation faults caused
+	// by inconsistent flags. This could happen if the specified content type
+	// length doesn't correspond to the actual content type length
+	if len(data) < 1+contentTypeLen {
+		return fmt.Errorf(
+			"Invalid content-typed message, "+
+				"too short for full content type (%d)",
+			contentTypeLen,
+		)
+	}
+
+	msg.Payload = pld.Payload{
+		ContentType: string(data[1 : 1+contentTypeLen]),
+		Data:        data[1+contentTypeLen:],
+	}
 	return nil
 }
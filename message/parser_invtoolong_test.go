@@ -26,3 +26,26 @@ func TestMsgParseInvalidSessionClosedTooLong(t *testing.T) {
 		lenTooLong,
 	)
 }
+
+// TestMsgParseInvalidPauseSignalsTooLong tests parsing of an invalid
+// signal pause request message which is too long to be considered valid
+func TestMsgParseInvalidPauseSignalsTooLong(t *testing.T) {
+	_, err := tryParse(t, []byte{MsgPauseSignals, 0})
+	require.Error(t, err)
+}
+
+// TestMsgParseInvalidResumeSignalsTooLong tests parsing of an invalid
+// signal resumption request message which is too long to be considered valid
+func TestMsgParseInvalidResumeSignalsTooLong(t *testing.T) {
+	_, err := tryParse(t, []byte{MsgResumeSignals, 0})
+	require.Error(t, err)
+}
+
+// TestMsgParseInvalidSignalAckTooLong tests parsing of an invalid
+// reliable signal acknowledgement which is too long to be considered valid
+func TestMsgParseInvalidSignalAckTooLong(t *testing.T) {
+	invalidMessage := make([]byte, MsgMinLenSignalAck+1)
+	invalidMessage[0] = MsgSignalAck
+	_, err := tryParse(t, invalidMessage)
+	require.Error(t, err)
+}
@@ -57,6 +57,23 @@ func TestMsgParseInvalidReplyUtf16TooShort(t *testing.T) {
 	)
 }
 
+// TestMsgParseInvalidReplyChunkTooShort tests parsing of an invalid
+// reply chunk message which is too short to be considered valid
+func TestMsgParseInvalidReplyChunkTooShort(t *testing.T) {
+	lenTooShort := MsgMinLenReplyChunk - 1
+	invalidMessage := make([]byte, lenTooShort)
+
+	invalidMessage[0] = MsgReplyChunk
+
+	_, err := tryParse(t, invalidMessage)
+	require.Error(t,
+		err,
+		"Expected error while parsing invalid reply chunk message "+
+			"(too short: %d)",
+		lenTooShort,
+	)
+}
+
 // TestMsgParseInvalidRequestTooShort tests parsing of an invalid
 // binary/UTF8 request message which is too short to be considered valid
 func TestMsgParseInvalidRequestTooShort(t *testing.T) {
@@ -194,6 +211,23 @@ func TestMsgParseInvalidErrorReplyTooShort(t *testing.T) {
 	)
 }
 
+// TestMsgParseInvalidErrorReplyUtf16TooShort tests parsing of an invalid
+// UTF16 error reply message which is too short to be considered valid
+func TestMsgParseInvalidErrorReplyUtf16TooShort(t *testing.T) {
+	lenTooShort := MsgMinLenErrorReplyUtf16 - 1
+	invalidMessage := make([]byte, lenTooShort)
+
+	invalidMessage[0] = MsgErrorReplyUtf16
+
+	_, err := tryParse(t, invalidMessage)
+	require.Error(t,
+		err,
+		"Expected error while parsing invalid UTF16 error reply message "+
+			"(too short: %d)",
+		lenTooShort,
+	)
+}
+
 // TestMsgParseInvalidSpecialReplyTooShort tests parsing of an invalid
 // special reply message which is too short to be considered valid
 func TestMsgParseInvalidSpecialReplyTooShort(t *testing.T) {
@@ -209,3 +243,22 @@ func TestMsgParseInvalidSpecialReplyTooShort(t *testing.T) {
 			"(too short: 8)",
 	)
 }
+
+// TestMsgParseInvalidReplyTypedTooShort tests parsing of an invalid
+// content-typed reply message which is too short for its content type
+func TestMsgParseInvalidReplyTypedTooShort(t *testing.T) {
+	invalidMessage := make([]byte, MsgMinLenReplyTyped)
+	invalidMessage[0] = MsgReplyTyped
+	// Specify a content type longer than the message
+	invalidMessage[9] = 3
+	_, err := tryParse(t, invalidMessage)
+	require.Error(t, err)
+}
+
+// TestMsgParseInvalidSignalTypedNoContentType tests parsing of an invalid
+// content-typed signal message with a content type length flag of 0
+func TestMsgParseInvalidSignalTypedNoContentType(t *testing.T) {
+	invalidMessage := []byte{MsgSignalTyped, 0, 0, 'a'}
+	_, err := tryParse(t, invalidMessage)
+	require.Error(t, err)
+}
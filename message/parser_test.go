@@ -74,6 +74,39 @@ func TestMsgParseRestrSessReq(t *testing.T) {
 	require.Equal(t, expected, actual)
 }
 
+// TestMsgParseRestrSessReqBinaryKey tests parsing of a session restoration
+// request carrying a session key containing null bytes, high bytes
+// and bytes equal to message type flags
+func TestMsgParseRestrSessReqBinaryKey(t *testing.T) {
+	id := genRndMsgIdentifier()
+	sessionKey := []byte{
+		0, 'k', 0, 0xff, 0x80, MsgRequestUtf16, MsgRestoreSession, 0x7f, 0,
+	}
+
+	// Compose encoded message
+	encoded := []byte{MsgRestoreSession}
+	encoded = append(encoded, id[:]...)
+	encoded = append(encoded, sessionKey...)
+
+	// Initialize expected message with the session key in the payload
+	expected := Message{
+		Type:       MsgRestoreSession,
+		Identifier: id,
+		Name:       "",
+		Payload: pld.Payload{
+			Encoding: pld.Binary,
+			Data:     sessionKey,
+		},
+	}
+
+	// Parse
+	actual := tryParseNoErr(t, encoded)
+
+	// Compare
+	require.Equal(t, expected, actual)
+	require.NoError(t, actual.Validate())
+}
+
 // TestMsgParseRequestBinary tests parsing of a named binary encoded request
 func TestMsgParseRequestBinary(t *testing.T) {
 	encoded, id, name, payload := rndRequestMsg(
@@ -207,6 +240,64 @@ func TestMsgParseReplyUtf16(t *testing.T) {
 	require.Equal(t, expected, actual)
 }
 
+// TestMsgParseRetryAfterReply tests parsing of a special reply message
+// carrying a retry-after hint
+func TestMsgParseRetryAfterReply(t *testing.T) {
+	id := genRndMsgIdentifier()
+
+	// Compose encoded message
+	// Add type flag
+	encoded := []byte{MsgServiceUnavailable}
+	// Add identifier
+	encoded = append(encoded, id[:]...)
+	// Add retry-after hint (1500 milliseconds)
+	encoded = append(encoded, 0, 0, 0, 0, 0, 0, 0x05, 0xDC)
+
+	// Initialize expected message
+	expected := Message{
+		Type:       MsgServiceUnavailable,
+		Identifier: id,
+		RetryAfter: 1500 * time.Millisecond,
+	}
+
+	// Parse
+	actual := tryParseNoErr(t, encoded)
+
+	// Compare
+	require.Equal(t, expected, actual)
+}
+
+// TestMsgParseReplyChunk tests parsing of a reply chunk message
+func TestMsgParseReplyChunk(t *testing.T) {
+	id := genRndMsgIdentifier()
+	chunk := genRndByteString(1, 1024*64, 1)
+
+	// Compose encoded message
+	// Add type flag
+	encoded := []byte{MsgReplyChunk}
+	// Add identifier
+	encoded = append(encoded, id[:]...)
+	// Add payload chunk
+	encoded = append(encoded, chunk...)
+
+	// Initialize expected message
+	expected := Message{
+		Type:       MsgReplyChunk,
+		Identifier: id,
+		Name:       "",
+		Payload: pld.Payload{
+			Encoding: pld.Binary,
+			Data:     chunk,
+		},
+	}
+
+	// Parse
+	actual := tryParseNoErr(t, encoded)
+
+	// Compare
+	require.Equal(t, expected, actual)
+}
+
 // TestMsgParseSignalBinary tests parsing of a named binary encoded signal
 func TestMsgParseSignalBinary(t *testing.T) {
 	encoded, name, payload := rndSignalMsg(
@@ -314,6 +405,63 @@ func TestMsgParseSessCreatedSig(t *testing.T) {
 	require.Equal(t, expected, actual)
 }
 
+// TestMsgParseSessCreatedSigUtf8 tests parsing of UTF8 encoded
+// session created signal
+func TestMsgParseSessCreatedSigUtf8(t *testing.T) {
+	payload := pld.Payload{
+		Encoding: pld.Utf8,
+		Data:     []byte(`{"k":"somesamplesessionkey"}`),
+	}
+
+	// Compose encoded message
+	// Add type flag
+	encoded := []byte{MsgSessionCreatedUtf8}
+	// Add session payload
+	encoded = append(encoded, payload.Data...)
+
+	// Initialize expected message
+	expected := Message{
+		Type:       MsgSessionCreatedUtf8,
+		Identifier: [8]byte{0, 0, 0, 0, 0, 0, 0, 0},
+		Name:       "",
+		Payload:    payload,
+	}
+
+	// Parse
+	actual := tryParseNoErr(t, encoded)
+
+	// Compare
+	require.Equal(t, expected, actual)
+}
+
+// TestMsgParseSessInfoUpdatedSig tests parsing of session info updated signal
+func TestMsgParseSessInfoUpdatedSig(t *testing.T) {
+	payload := pld.Payload{
+		Encoding: pld.Utf8,
+		Data:     []byte(`{"field":"value"}`),
+	}
+
+	// Compose encoded message
+	// Add type flag
+	encoded := []byte{MsgSessionInfoUpdated}
+	// Add session info payload
+	encoded = append(encoded, payload.Data...)
+
+	// Initialize expected message
+	expected := Message{
+		Type:       MsgSessionInfoUpdated,
+		Identifier: [8]byte{0, 0, 0, 0, 0, 0, 0, 0},
+		Name:       "",
+		Payload:    payload,
+	}
+
+	// Parse
+	actual := tryParseNoErr(t, encoded)
+
+	// Compare
+	require.Equal(t, expected, actual)
+}
+
 // TestMsgParseSessClosedSig tests parsing of session sloed signal
 func TestMsgParseSessClosedSig(t *testing.T) {
 	// Compose encoded message
@@ -335,6 +483,18 @@ func TestMsgParseSessClosedSig(t *testing.T) {
 	require.Equal(t, expected, actual)
 }
 
+// TestMsgParsePauseSignals tests parsing of signal pause requests
+func TestMsgParsePauseSignals(t *testing.T) {
+	actual := tryParseNoErr(t, []byte{MsgPauseSignals})
+	require.Equal(t, Message{Type: MsgPauseSignals}, actual)
+}
+
+// TestMsgParseResumeSignals tests parsing of signal resumption requests
+func TestMsgParseResumeSignals(t *testing.T) {
+	actual := tryParseNoErr(t, []byte{MsgResumeSignals})
+	require.Equal(t, Message{Type: MsgResumeSignals}, actual)
+}
+
 // TestMsgParseUnknownMessageType tests parsing of messages
 // with unknown message type
 func TestMsgParseUnknownMessageType(t *testing.T) {
@@ -345,3 +505,191 @@ func TestMsgParseUnknownMessageType(t *testing.T) {
 	typeDetermined, _ := actual.Parse(msgOfUnknownType)
 	require.False(t, typeDetermined, "Expected type not to be determined")
 }
+
+// TestMsgParseSignalAck tests parsing of reliable signal acknowledgements
+func TestMsgParseSignalAck(t *testing.T) {
+	id := genRndMsgIdentifier()
+	actual := tryParseNoErr(t, NewSignalAckMessage(id))
+	require.Equal(t, Message{Type: MsgSignalAck, Identifier: id}, actual)
+}
+
+// TestMsgParseReliableSignalUtf16 tests parsing of UTF16 encoded
+// reliable signals
+func TestMsgParseReliableSignalUtf16(t *testing.T) {
+	id := genRndMsgIdentifier()
+	payload := pld.Payload{
+		Encoding: pld.Utf16,
+		Data:     []byte{65, 0, 66, 0},
+	}
+
+	// Use an odd name length to require a header padding
+	encoded := NewReliableSignalMessage(
+		id,
+		"odd",
+		payload.Encoding,
+		payload.Data,
+	)
+
+	// Initialize expected message
+	expected := Message{
+		Type:       MsgReliableSignalUtf16,
+		Identifier: id,
+		Name:       "odd",
+		Payload:    payload,
+	}
+
+	// Parse
+	actual := tryParseNoErr(t, encoded)
+
+	// Compare
+	require.Equal(t, expected, actual)
+}
+
+// TestMsgParseSignalTyped tests parsing of content-typed signals
+func TestMsgParseSignalTyped(t *testing.T) {
+	payload := pld.Payload{
+		Encoding:    pld.Binary,
+		Data:        []byte("random payload data"),
+		ContentType: "image/png",
+	}
+	encoded := NewTypedSignalMessage(
+		"samplename",
+		payload.ContentType,
+		payload.Data,
+	)
+
+	// Initialize expected message
+	expected := Message{
+		Type:    MsgSignalTyped,
+		Name:    "samplename",
+		Payload: payload,
+	}
+
+	// Parse
+	actual := tryParseNoErr(t, encoded)
+
+	// Compare
+	require.Equal(t, expected, actual)
+}
+
+// TestMsgParseRequestTyped tests parsing of content-typed requests
+// without a payload
+func TestMsgParseRequestTyped(t *testing.T) {
+	id := genRndMsgIdentifier()
+	encoded := NewTypedRequestMessage(
+		id,
+		"samplename",
+		"application/protobuf",
+		nil,
+	)
+
+	// Initialize expected message
+	expected := Message{
+		Type:       MsgRequestTyped,
+		Identifier: id,
+		Name:       "samplename",
+		Payload: pld.Payload{
+			Encoding:    pld.Binary,
+			Data:        []byte{},
+			ContentType: "application/protobuf",
+		},
+	}
+
+	// Parse
+	actual := tryParseNoErr(t, encoded)
+
+	// Compare
+	require.Equal(t, expected, actual)
+}
+
+// TestMsgParseReplyTyped tests parsing of content-typed replies
+func TestMsgParseReplyTyped(t *testing.T) {
+	id := genRndMsgIdentifier()
+	payload := pld.Payload{
+		Encoding:    pld.Binary,
+		Data:        []byte("random payload data"),
+		ContentType: "image/png",
+	}
+	encoded := NewTypedReplyMessage(id, payload.ContentType, payload.Data)
+
+	// Initialize expected message
+	expected := Message{
+		Type:       MsgReplyTyped,
+		Identifier: id,
+		Payload:    payload,
+	}
+
+	// Parse
+	actual := tryParseNoErr(t, encoded)
+
+	// Compare
+	require.Equal(t, expected, actual)
+}
+
+// TestMsgParseErrorReplyUtf16 tests parsing of UTF16 encoded error replies
+// with error codes of both odd and even length
+func TestMsgParseErrorReplyUtf16(t *testing.T) {
+	id := genRndMsgIdentifier()
+	for _, code := range []string{"ODD", "EVEN"} {
+		payload := pld.Payload{
+			Encoding: pld.Utf16,
+			Data:     genRndByteString(2, 32, 2),
+		}
+		encoded := NewErrorReplyMessageUtf16(id, code, payload.Data)
+
+		// Initialize expected message
+		expected := Message{
+			Type:       MsgErrorReplyUtf16,
+			Identifier: id,
+			Name:       code,
+			Payload:    payload,
+		}
+
+		// Parse
+		actual := tryParseNoErr(t, encoded)
+
+		// Compare
+		require.Equal(t, expected, actual)
+	}
+}
+
+// TestMsgParseNamelessRoundTrip tests whether nameless requests and signals
+// composed by the constructors are parsed back into nameless messages
+// carrying the original payload
+func TestMsgParseNamelessRoundTrip(t *testing.T) {
+	id := genRndMsgIdentifier()
+	encodings := map[pld.Encoding][2]byte{
+		pld.Binary: {MsgRequestBinary, MsgSignalBinary},
+		pld.Utf8:   {MsgRequestUtf8, MsgSignalUtf8},
+		pld.Utf16:  {MsgRequestUtf16, MsgSignalUtf16},
+	}
+	for encoding, types := range encodings {
+		payload := pld.Payload{
+			Encoding: encoding,
+			Data:     genRndByteString(2, 32, 2),
+		}
+
+		// Request
+		request := tryParseNoErr(
+			t,
+			NewRequestMessage(id, "", encoding, payload.Data),
+		)
+		require.Equal(t, Message{
+			Type:       types[0],
+			Identifier: id,
+			Payload:    payload,
+		}, request)
+		require.NoError(t, request.Validate())
+
+		// Signal
+		signal := tryParseNoErr(
+			t,
+			NewSignalMessage("", encoding, payload.Data),
+		)
+		require.Equal(t, Message{
+			Type:    types[1],
+			Payload: payload,
+		}, signal)
+		require.NoError(t, signal.Validate())
+	}
+}
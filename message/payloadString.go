@@ -0,0 +1,38 @@
+package message
+
+import (
+	"encoding/hex"
+	"fmt"
+
+	pld "github.com/qbeon/webwire-go/payload"
+)
+
+// PayloadStringMaxBinaryPreview defines the maximum number of bytes
+// of a binary payload previewed by PayloadString
+const PayloadStringMaxBinaryPreview = 64
+
+// PayloadString returns a human readable representation of the payload
+// intended for logging. UTF8 payloads are returned as is while UTF16
+// payloads are transcoded to UTF8. Binary payloads and invalid UTF16
+// payloads are represented by a hexadecimal preview of at most
+// PayloadStringMaxBinaryPreview bytes followed by the total payload size
+func (msg *Message) PayloadString() string {
+	switch msg.Payload.Encoding {
+	case pld.Utf8:
+		return string(msg.Payload.Data)
+	case pld.Utf16:
+		if str, err := msg.Payload.Utf8(); err == nil {
+			return str
+		}
+	}
+
+	data := msg.Payload.Data
+	if len(data) <= PayloadStringMaxBinaryPreview {
+		return hex.EncodeToString(data)
+	}
+	return fmt.Sprintf(
+		"%s... (%d bytes)",
+		hex.EncodeToString(data[:PayloadStringMaxBinaryPreview]),
+		len(data),
+	)
+}
@@ -0,0 +1,49 @@
+package message
+
+import (
+	"strings"
+	"testing"
+
+	pld "github.com/qbeon/webwire-go/payload"
+	"github.com/stretchr/testify/require"
+)
+
+// TestMsgPayloadStringUtf8 tests PayloadString on UTF8 payloads
+func TestMsgPayloadStringUtf8(t *testing.T) {
+	msg := Message{Payload: pld.Payload{
+		Encoding: pld.Utf8,
+		Data:     []byte("hello ёжз"),
+	}}
+	require.Equal(t, "hello ёжз", msg.PayloadString())
+}
+
+// TestMsgPayloadStringUtf16 tests PayloadString on UTF16 payloads
+// expecting them to be transcoded to UTF8
+func TestMsgPayloadStringUtf16(t *testing.T) {
+	msg := Message{Payload: pld.Payload{
+		Encoding: pld.Utf16,
+		Data:     []byte{65, 0, 66, 0, 0x36, 0x04},
+	}}
+	require.Equal(t, "ABж", msg.PayloadString())
+
+	// Expect invalid UTF16 payloads to be represented in hex
+	msg.Payload.Data = []byte{65, 0, 66}
+	require.Equal(t, "410042", msg.PayloadString())
+}
+
+// TestMsgPayloadStringBinary tests PayloadString on binary payloads
+// expecting large payloads to be truncated
+func TestMsgPayloadStringBinary(t *testing.T) {
+	msg := Message{Payload: pld.Payload{
+		Encoding: pld.Binary,
+		Data:     []byte{0x00, 0xff, 0x10},
+	}}
+	require.Equal(t, "00ff10", msg.PayloadString())
+
+	msg.Payload.Data = make([]byte, 1024)
+	require.Equal(
+		t,
+		strings.Repeat("00", PayloadStringMaxBinaryPreview)+"... (1024 bytes)",
+		msg.PayloadString(),
+	)
+}
@@ -0,0 +1,29 @@
+package message
+
+const (
+	// MsgSpecialReplyMin represents the lowest special reply message type
+	MsgSpecialReplyMin = MsgReplyShutdown
+
+	// MsgSpecialReplyMax represents the highest special reply message type,
+	// it must be updated when a new special reply message type is added
+	MsgSpecialReplyMax = MsgSessionOperationThrottled
+)
+
+// IsSpecialReplyType returns true if the given message type represents
+// a special request reply message type (MsgSpecialReplyMin
+// to MsgSpecialReplyMax), otherwise returns false.
+// Special reply messages carry no payload and consist of
+// the message type and the identifier of the replied request
+// optionally followed by a retry-after hint (see NewRetryAfterReplyMessage)
+func IsSpecialReplyType(msgType byte) bool {
+	return msgType >= MsgSpecialReplyMin && msgType <= MsgSpecialReplyMax
+}
+
+// SpecialReplyTypes returns a list of all special reply message types
+func SpecialReplyTypes() []byte {
+	types := make([]byte, 0, MsgSpecialReplyMax-MsgSpecialReplyMin+1)
+	for tp := MsgSpecialReplyMin; tp <= MsgSpecialReplyMax; tp++ {
+		types = append(types, tp)
+	}
+	return types
+}
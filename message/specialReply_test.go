@@ -0,0 +1,47 @@
+package message
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// TestMsgIsSpecialReplyType tests IsSpecialReplyType
+func TestMsgIsSpecialReplyType(t *testing.T) {
+	specialTypes := []byte{
+		MsgReplyShutdown,
+		MsgInternalError,
+		MsgSessionNotFound,
+		MsgMaxSessConnsReached,
+		MsgSessionsDisabled,
+		MsgReplyProtocolError,
+		MsgFeatureDisabled,
+		MsgTooManyRequests,
+		MsgUnauthorized,
+		MsgMethodNotFound,
+		MsgServiceUnavailable,
+		MsgRequestTimeout,
+		MsgSessionOperationThrottled,
+	}
+	require.ElementsMatch(t, specialTypes, SpecialReplyTypes())
+
+	for _, tp := range specialTypes {
+		require.True(t, IsSpecialReplyType(tp), "type %d", tp)
+	}
+
+	for _, tp := range []byte{
+		MsgErrorReply,
+		MsgSessionCreated,
+		MsgSessionCreatedUtf8,
+		MsgSessionInfoUpdated,
+		MsgSessionClosed,
+		MsgCloseSession,
+		MsgRestoreSession,
+		MsgSignalBinary,
+		MsgRequestBinary,
+		MsgReplyChunk,
+		MsgReplyBinary,
+	} {
+		require.False(t, IsSpecialReplyType(tp), "type %d", tp)
+	}
+}
@@ -4,16 +4,11 @@ import (
 	"fmt"
 	"math/rand"
 	"testing"
-	"time"
 
 	pld "github.com/qbeon/webwire-go/payload"
 	"github.com/stretchr/testify/require"
 )
 
-func init() {
-	rand.Seed(time.Now().UnixNano())
-}
-
 func tryParse(t *testing.T, encoded []byte) (Message, error) {
 	var parsedMsg Message
 	typeDetermined, err := parsedMsg.Parse(encoded)
@@ -0,0 +1,73 @@
+package message
+
+import "fmt"
+
+// EmptyMessageErr represents a validation error type indicating that
+// a request or signal message carries neither a name nor a payload
+type EmptyMessageErr struct{}
+
+func (err EmptyMessageErr) Error() string {
+	return "Message carries neither a name nor a payload"
+}
+
+// InvalidSessionKeyErr represents a validation error type indicating that
+// the session key of a session restoration request is empty
+type InvalidSessionKeyErr struct{}
+
+func (err InvalidSessionKeyErr) Error() string {
+	return "Invalid session key"
+}
+
+// InvalidContentTypeErr represents a validation error type indicating that
+// the content type of a content-typed message is invalid,
+// see ValidateContentType
+type InvalidContentTypeErr struct {
+	Cause error
+}
+
+func (err InvalidContentTypeErr) Error() string {
+	return fmt.Sprintf("Invalid content type: %s", err.Cause)
+}
+
+// Validate verifies the semantic validity of a parsed inbound message
+// which Parse doesn't verify since it only checks the structure.
+// It returns either an EmptyMessageErr, an InvalidSessionKeyErr
+// or an InvalidContentTypeErr if the message is invalid
+func (msg *Message) Validate() error {
+	switch msg.Type {
+	case MsgRestoreSession:
+		return validateSessionKey(msg.Payload.Data)
+
+	case MsgSignalTyped:
+		fallthrough
+	case MsgRequestTyped:
+		if err := ValidateContentType(msg.Payload.ContentType); err != nil {
+			return InvalidContentTypeErr{Cause: err}
+		}
+		fallthrough
+	case MsgSignalBinary:
+		fallthrough
+	case MsgSignalUtf8:
+		fallthrough
+	case MsgSignalUtf16:
+		fallthrough
+	case MsgRequestBinary:
+		fallthrough
+	case MsgRequestUtf8:
+		fallthrough
+	case MsgRequestUtf16:
+		if len(msg.Name) < 1 && len(msg.Payload.Data) < 1 {
+			return EmptyMessageErr{}
+		}
+	}
+	return nil
+}
+
+// validateSessionKey returns an InvalidSessionKeyErr if the given session key
+// is empty. Session keys are opaque binary and may contain any bytes
+func validateSessionKey(key []byte) error {
+	if len(key) < 1 {
+		return InvalidSessionKeyErr{}
+	}
+	return nil
+}
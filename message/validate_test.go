@@ -0,0 +1,64 @@
+package message
+
+import (
+	"testing"
+
+	pld "github.com/qbeon/webwire-go/payload"
+	"github.com/stretchr/testify/require"
+)
+
+// TestMsgValidate tests the semantic validation of parsed messages
+func TestMsgValidate(t *testing.T) {
+	validate := func(msg Message) error { return msg.Validate() }
+
+	// Valid messages
+	require.NoError(t, validate(Message{
+		Type: MsgRequestBinary,
+		Name: "name",
+	}))
+	require.NoError(t, validate(Message{
+		Type:    MsgSignalUtf16,
+		Payload: pld.Payload{Data: []byte{'a', 0}},
+	}))
+	require.NoError(t, validate(Message{
+		Type:    MsgRestoreSession,
+		Payload: pld.Payload{Data: []byte("sessionkey")},
+	}))
+	require.NoError(t, validate(Message{
+		Type:    MsgRestoreSession,
+		Payload: pld.Payload{Data: []byte("session\x00key\xff\n")},
+	}))
+	require.NoError(t, validate(Message{
+		Type: MsgRequestTyped,
+		Name: "name",
+		Payload: pld.Payload{
+			ContentType: "application/json",
+		},
+	}))
+	require.NoError(t, validate(Message{Type: MsgCloseSession}))
+
+	// Requests and signals carrying neither a name nor a payload
+	require.IsType(t, EmptyMessageErr{}, validate(Message{
+		Type: MsgRequestUtf16,
+	}))
+	require.IsType(t, EmptyMessageErr{}, validate(Message{
+		Type: MsgSignalTyped,
+		Payload: pld.Payload{
+			ContentType: "text/plain",
+		},
+	}))
+
+	// Invalid session keys
+	require.IsType(t, InvalidSessionKeyErr{}, validate(Message{
+		Type: MsgRestoreSession,
+	}))
+
+	// Invalid content types
+	require.IsType(t, InvalidContentTypeErr{}, validate(Message{
+		Type: MsgRequestTyped,
+		Name: "name",
+		Payload: pld.Payload{
+			ContentType: "text/\x00",
+		},
+	}))
+}
@@ -31,3 +31,14 @@ func TestMsgWrapperGetters(t *testing.T) {
 	require.Equal(t, wwr.EncodingBinary, pld.Encoding())
 	require.Equal(t, []byte("sample-data"), pld.Data())
 }
+
+// TestMsgWrapperReplied tests whether the message wrapper
+// reflects the replied state of the wrapped message
+func TestMsgWrapperReplied(t *testing.T) {
+	message := &msg.Message{Type: msg.MsgRequestBinary}
+	wrappedMsg := wwr.NewMessageWrapper(message)
+
+	require.False(t, wrappedMsg.Replied())
+	require.True(t, message.MarkReplied())
+	require.True(t, wrappedMsg.Replied())
+}
@@ -0,0 +1,110 @@
+package webwire
+
+import (
+	"sync"
+	"time"
+)
+
+// requestLatencyBuckets defines the upper bounds of the buckets
+// of the request latency histogram
+var requestLatencyBuckets = []time.Duration{
+	5 * time.Millisecond,
+	10 * time.Millisecond,
+	25 * time.Millisecond,
+	50 * time.Millisecond,
+	100 * time.Millisecond,
+	250 * time.Millisecond,
+	500 * time.Millisecond,
+	1 * time.Second,
+	2500 * time.Millisecond,
+	5 * time.Second,
+	10 * time.Second,
+}
+
+// Metrics represents a snapshot of the live counters of a server,
+// see Server.Metrics
+type Metrics struct {
+	// RequestsTotal is the number of requests handled by the OnRequest hook
+	// since the server was started
+	RequestsTotal uint64
+
+	// RequestsFailed is the number of requests the OnRequest hook
+	// returned an error for, including timed out requests
+	RequestsFailed uint64
+
+	// RequestLatencySum is the total time spent handling requests
+	RequestLatencySum time.Duration
+
+	// RequestLatencyBounds defines the upper bounds of the buckets
+	// of the request latency histogram in ascending order
+	RequestLatencyBounds []time.Duration
+
+	// RequestLatencyBuckets holds the cumulative number of requests
+	// handled within the corresponding upper bound
+	// of RequestLatencyBounds
+	RequestLatencyBuckets []uint64
+
+	// ActiveConnections is the number of currently connected clients
+	ActiveConnections int
+
+	// ActiveSessions is the number of sessions
+	// with at least one connection to the server
+	ActiveSessions int
+}
+
+// requestMetrics keeps track of the number and latencies
+// of the requests handled by the OnRequest hook
+type requestMetrics struct {
+	lock       sync.Mutex
+	total      uint64
+	failed     uint64
+	latencySum time.Duration
+	buckets    []uint64
+}
+
+// newRequestMetrics creates a new empty request metrics tracker
+func newRequestMetrics() *requestMetrics {
+	return &requestMetrics{
+		buckets: make([]uint64, len(requestLatencyBuckets)),
+	}
+}
+
+// observe records a request handled within the given duration
+func (metrics *requestMetrics) observe(latency time.Duration, failed bool) {
+	metrics.lock.Lock()
+	defer metrics.lock.Unlock()
+
+	metrics.total++
+	if failed {
+		metrics.failed++
+	}
+	metrics.latencySum += latency
+	for i, bound := range requestLatencyBuckets {
+		if latency <= bound {
+			metrics.buckets[i]++
+		}
+	}
+}
+
+// Metrics implements the Server interface
+func (srv *server) Metrics() Metrics {
+	bucketsNum := len(requestLatencyBuckets)
+	srv.requestMetrics.lock.Lock()
+	metrics := Metrics{
+		RequestsTotal:         srv.requestMetrics.total,
+		RequestsFailed:        srv.requestMetrics.failed,
+		RequestLatencySum:     srv.requestMetrics.latencySum,
+		RequestLatencyBounds:  make([]time.Duration, bucketsNum),
+		RequestLatencyBuckets: make([]uint64, bucketsNum),
+	}
+	copy(metrics.RequestLatencyBounds, requestLatencyBuckets)
+	copy(metrics.RequestLatencyBuckets, srv.requestMetrics.buckets)
+	srv.requestMetrics.lock.Unlock()
+
+	srv.connectionsLock.Lock()
+	metrics.ActiveConnections = len(srv.connections)
+	srv.connectionsLock.Unlock()
+
+	metrics.ActiveSessions = srv.ActiveSessionsNum()
+	return metrics
+}
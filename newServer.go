@@ -5,6 +5,9 @@ import (
 	"net"
 	"net/http"
 	"sync"
+	"time"
+
+	"golang.org/x/sync/semaphore"
 )
 
 // NewServer creates a new headed WebWire server instance
@@ -64,8 +67,30 @@ func NewHeadlessServer(
 		sessionsEnabled = true
 	}
 
-	return &server{
-		impl:              implementation,
+	// Index the allowed names if an allowlist is specified
+	var allowedNames map[string]struct{}
+	if opts.AllowedNames != nil {
+		allowedNames = make(map[string]struct{}, len(opts.AllowedNames))
+		for _, name := range opts.AllowedNames {
+			allowedNames[name] = struct{}{}
+		}
+	}
+
+	// Bound the number of concurrently handled messages
+	// if a worker pool size is specified
+	var workerSlots *semaphore.Weighted
+	if opts.WorkerPoolSize > 0 {
+		workerSlots = semaphore.NewWeighted(int64(opts.WorkerPoolSize))
+	}
+
+	// Limit the number of goroutines handling incoming messages
+	// if a goroutine limit is specified
+	var goroutineSlots *semaphore.Weighted
+	if opts.MaxGoroutines > 0 {
+		goroutineSlots = semaphore.NewWeighted(int64(opts.MaxGoroutines))
+	}
+
+	srv := &server{
 		sessionManager:    opts.SessionManager,
 		sessionKeyGen:     opts.SessionKeyGenerator,
 		sessionInfoParser: opts.SessionInfoParser,
@@ -80,11 +105,33 @@ func NewHeadlessServer(
 		connections:     make([]*connection, 0),
 		connectionsLock: &sync.Mutex{},
 		sessionsEnabled: sessionsEnabled,
-		sessionRegistry: newSessionRegistry(opts.MaxSessionConnections),
+		sessionRegistry: newSessionRegistry(
+			opts.MaxSessionConnections,
+			opts.MaxActiveSessions,
+			opts.SessionRegistryBackend,
+		),
+		allowedNames:        allowedNames,
+		workerSlots:         workerSlots,
+		idempotencyKeys:     newIdempotencyKeyLocks(),
+		inFlightRequests:    newInFlightRequests(),
+		requestMetrics:      newRequestMetrics(),
+		retainedSignalsLock: &sync.RWMutex{},
+		retainedSignals:     make(map[string]Payload),
+		requestTimeoutsLock: &sync.RWMutex{},
+		requestTimeouts:     make(map[string]time.Duration),
+		events:              make(chan ServerEvent, opts.EventBufferSize),
+		goroutineSlots:      goroutineSlots,
 
 		// Internals
-		connUpgrader: newConnUpgrader(),
-		warnLog:      opts.WarnLog,
-		errorLog:     opts.ErrorLog,
-	}, nil
+		connUpgrader: newConnUpgrader(
+			opts.CompressReplies || opts.CompressionThreshold > 0,
+			opts.CompressionThreshold,
+			opts.MaxMessageSize,
+		),
+		warnLog:  opts.WarnLog,
+		errorLog: opts.ErrorLog,
+	}
+	srv.impl.Store(implementationRef{implementation})
+
+	return srv, nil
 }
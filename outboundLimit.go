@@ -0,0 +1,131 @@
+package webwire
+
+import (
+	"fmt"
+	"sort"
+	"sync/atomic"
+)
+
+// OutboundLimitPolicy defines how the server enforces
+// ServerOptions.MaxPendingOutboundBytes
+type OutboundLimitPolicy int
+
+const (
+	// OutboundLimitDisconnectSlowest closes the connections with the most
+	// outbound bytes pending until the limit is no longer exceeded.
+	// Their pending writes fail and OnClientDisconnected is invoked
+	// with the DisconnectSlowConsumer reason
+	OutboundLimitDisconnectSlowest OutboundLimitPolicy = iota
+
+	// OutboundLimitRejectWrites fails writes exceeding the limit
+	// with an OutboundLimitExceededErr without closing any connection
+	OutboundLimitRejectWrites
+)
+
+// String stringifies the outbound limit policy
+func (policy OutboundLimitPolicy) String() string {
+	switch policy {
+	case OutboundLimitDisconnectSlowest:
+		return "disconnect slowest"
+	case OutboundLimitRejectWrites:
+		return "reject writes"
+	}
+	return ""
+}
+
+// PendingOutboundBytes implements the Server interface
+func (srv *server) PendingOutboundBytes() int64 {
+	return atomic.LoadInt64(&srv.pendingOutboundBytes)
+}
+
+// sockWrite writes the given message to the socket of the connection
+// keeping track of the outbound bytes pending to be written
+// and enforces ServerOptions.MaxPendingOutboundBytes
+func (con *connection) sockWrite(message []byte) error {
+	size := int64(len(message))
+	atomic.AddInt64(&con.pendingOutboundBytes, size)
+	total := atomic.AddInt64(&con.srv.pendingOutboundBytes, size)
+	defer func() {
+		atomic.AddInt64(&con.pendingOutboundBytes, -size)
+		atomic.AddInt64(&con.srv.pendingOutboundBytes, -size)
+	}()
+
+	max := con.srv.options.MaxPendingOutboundBytes
+	if max > 0 && total > max {
+		policy := con.srv.options.OnOutboundLimitExceeded
+		if policy == OutboundLimitRejectWrites {
+			return OutboundLimitExceededErr{}
+		}
+		con.srv.disconnectSlowest(total - max)
+		if con.isSlowConsumer() {
+			return DisconnectedErr{
+				Cause: fmt.Errorf("Connection closed as a slow consumer"),
+			}
+		}
+	}
+
+	return con.sock.Write(message)
+}
+
+// disconnectSlowest closes the connections with the most outbound bytes
+// pending until at least the given number of bytes is freed
+func (srv *server) disconnectSlowest(excess int64) {
+	srv.connectionsLock.Lock()
+	connections := make([]*connection, len(srv.connections))
+	copy(connections, srv.connections)
+	srv.connectionsLock.Unlock()
+
+	pending := make(map[*connection]int64, len(connections))
+	for _, con := range connections {
+		pending[con] = atomic.LoadInt64(&con.pendingOutboundBytes)
+	}
+	sort.Slice(connections, func(i, j int) bool {
+		return pending[connections[i]] > pending[connections[j]]
+	})
+
+	for _, con := range connections {
+		if excess <= 0 || pending[con] < 1 {
+			return
+		}
+		if !con.closeSlowConsumer() {
+			continue
+		}
+		srv.warnLog.Printf(
+			"Disconnecting slow client %v (%d outbound bytes pending)",
+			con.Info().RemoteAddr,
+			pending[con],
+		)
+		excess -= pending[con]
+	}
+}
+
+// closeSlowConsumer closes the connection aborting its pending writes
+// and returns true unless it was already closed
+func (con *connection) closeSlowConsumer() bool {
+	con.stateLock.Lock()
+	if !con.isActive || con.slowConsumer {
+		con.stateLock.Unlock()
+		return false
+	}
+	con.slowConsumer = true
+	con.stateLock.Unlock()
+
+	// Abort the socket first to fail the writes blocked on the slow client
+	// which would otherwise block closing the socket
+	if aborter, isAborter := con.sock.(Aborter); isAborter {
+		aborter.Abort()
+		con.Close()
+	} else {
+		con.srv.spawn(con.Close)
+	}
+	return true
+}
+
+// isSlowConsumer returns true if the connection was closed
+// due to ServerOptions.MaxPendingOutboundBytes being exceeded
+func (con *connection) isSlowConsumer() bool {
+	con.stateLock.RLock()
+	slowConsumer := con.slowConsumer
+	con.stateLock.RUnlock()
+	return slowConsumer
+}
@@ -0,0 +1,86 @@
+package webwire
+
+import (
+	"encoding/json"
+	"fmt"
+
+	msg "github.com/qbeon/webwire-go/message"
+)
+
+// PatchSessionInfo implements the Connection interface
+func (con *connection) PatchSessionInfo(field string, value interface{}) error {
+	if !con.srv.sessionsEnabled {
+		return SessionsDisabledErr{}
+	}
+
+	// Serialize concurrent patches to not lose any of them
+	con.sessionPatchLock.Lock()
+	defer con.sessionPatchLock.Unlock()
+
+	con.sessionLock.RLock()
+	previous := con.session
+	con.sessionLock.RUnlock()
+	if previous == nil {
+		return fmt.Errorf("Can't patch session info, no session active")
+	}
+
+	if !con.allowSessionOperation() {
+		return SessionOperationThrottledErr{}
+	}
+
+	// Merge the field into the existing info and pass it through
+	// a JSON round-trip for the session info parser to receive
+	// the same representation it receives during session restoration
+	fields := SessionInfoToVarMap(previous.Info)
+	if fields == nil {
+		fields = make(map[string]interface{}, 1)
+	}
+	fields[field] = value
+	encodedInfo, err := json.Marshal(fields)
+	if err != nil {
+		return fmt.Errorf("Couldn't marshal session info: %s", err)
+	}
+	var decodedInfo map[string]interface{}
+	if err := json.Unmarshal(encodedInfo, &decodedInfo); err != nil {
+		return fmt.Errorf("Couldn't unmarshal session info: %s", err)
+	}
+
+	patched := *previous
+	patched.Info = con.srv.storedSessionInfo(
+		con.srv.sessionInfoParser(decodedInfo),
+	)
+
+	// Abort if the session was closed or replaced in the meantime
+	con.sessionLock.Lock()
+	if con.session != previous {
+		con.sessionLock.Unlock()
+		return fmt.Errorf("Can't patch session info, session was replaced")
+	}
+	con.session = &patched
+	con.sessionLock.Unlock()
+
+	// Ephemeral sessions are neither persisted nor synchronized to the client
+	if patched.Ephemeral {
+		return nil
+	}
+
+	// Persist the patched session if supported by the session manager,
+	// roll back the patch if it fails
+	updater, isUpdater := con.srv.sessionManager.(SessionInfoUpdater)
+	if isUpdater {
+		if err := updater.OnSessionInfoUpdated(con.ctx, con); err != nil {
+			con.sessionLock.Lock()
+			if con.session == &patched {
+				con.session = previous
+			}
+			con.sessionLock.Unlock()
+			return fmt.Errorf("Couldn't persist patched session info: %s", err)
+		}
+	}
+
+	// Notify the client about the patched session info
+	message := make([]byte, 1+len(encodedInfo))
+	message[0] = msg.MsgSessionInfoUpdated
+	copy(message[1:], encodedInfo)
+	return con.sockWrite(message)
+}
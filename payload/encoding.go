@@ -14,6 +14,11 @@ const (
 	Utf16
 )
 
+// Default represents an unspecified encoding which is resolved
+// to the default encoding configured on the server
+// sending the payload. It's treated as Binary elsewhere
+const Default Encoding = -1
+
 // String stringifies the encoding type
 func (enc Encoding) String() string {
 	switch enc {
@@ -23,6 +28,8 @@ func (enc Encoding) String() string {
 		return "utf8"
 	case Utf16:
 		return "utf16"
+	case Default:
+		return "default"
 	}
 	return ""
 }
@@ -17,6 +17,9 @@ func TestEncodingStringification(t *testing.T) {
 
 	utf16Encoding := Utf16
 	require.Equal(t, "utf16", utf16Encoding.String())
+
+	defaultEncoding := Default
+	require.Equal(t, "default", defaultEncoding.String())
 }
 
 // TestConvertUtf8ToUtf8 tests the Utf8() payload conversion method
@@ -77,6 +80,22 @@ func TestConvertUtf16ToUtf8(t *testing.T) {
 	require.Len(t, result, 25)
 }
 
+// TestConvertUtf16SurrogatePairToUtf8 tests the Utf8() payload conversion
+// method with a UTF16 encoded payload containing a surrogate pair
+func TestConvertUtf16SurrogatePairToUtf8(t *testing.T) {
+	payload := Payload{
+		Encoding: Utf16,
+		Data: []byte{
+			0x41, 0x00,
+			0x3D, 0xD8, 0x00, 0xDE, // U+1F600
+		},
+	}
+
+	result, err := payload.Utf8()
+	require.NoError(t, err)
+	require.Equal(t, "A\U0001F600", result)
+}
+
 // TestConvertCorruptUtf16 tests the Utf8() payload conversion method
 // with a corrupted UTF16 payload
 func TestConvertCorruptUtf16(t *testing.T) {
@@ -89,3 +108,18 @@ func TestConvertCorruptUtf16(t *testing.T) {
 	require.Error(t, err)
 	require.Len(t, result, 0)
 }
+
+// TestPayloadLen tests the Len and IsEmpty payload methods
+func TestPayloadLen(t *testing.T) {
+	empty := Payload{Encoding: Binary}
+	require.Equal(t, 0, empty.Len())
+	require.True(t, empty.IsEmpty())
+
+	emptyUtf16 := Payload{Encoding: Utf16, Data: []byte{}}
+	require.Equal(t, 0, emptyUtf16.Len())
+	require.True(t, emptyUtf16.IsEmpty())
+
+	utf16 := Payload{Encoding: Utf16, Data: []byte{65, 0, 66, 0}}
+	require.Equal(t, 4, utf16.Len())
+	require.False(t, utf16.IsEmpty())
+}
@@ -1,16 +1,20 @@
 package payload
 
 import (
-	"bytes"
 	"fmt"
+	"hash/fnv"
 	"unicode/utf16"
-	"unicode/utf8"
 )
 
 // Payload represents an encoded message payload
 type Payload struct {
 	Encoding Encoding
 	Data     []byte
+
+	// ContentType optionally describes the application-level content type
+	// of the data such as "application/protobuf", it's empty by default.
+	// Only binary payloads can carry a content type
+	ContentType string
 }
 
 // Utf8 returns a UTF8 representation of the payload data
@@ -21,18 +25,45 @@ func (pld *Payload) Utf8() (string, error) {
 				"Cannot convert invalid UTF16 payload data to UTF8",
 			)
 		}
-		u16str := make([]uint16, 1)
-		utf8str := &bytes.Buffer{}
-		utf8buf := make([]byte, 4)
-		for i := 0; i < len(pld.Data); i += 2 {
-			u16str[0] = uint16(pld.Data[i]) + (uint16(pld.Data[i+1]) << 8)
-			rn := utf16.Decode(u16str)
-			rnSize := utf8.EncodeRune(utf8buf, rn[0])
-			utf8str.Write(utf8buf[:rnSize])
+		// Decode all code units at once to correctly decode surrogate pairs
+		u16str := make([]uint16, len(pld.Data)/2)
+		for i := range u16str {
+			u16str[i] = uint16(pld.Data[2*i]) + (uint16(pld.Data[2*i+1]) << 8)
 		}
-		return utf8str.String(), nil
+		return string(utf16.Decode(u16str)), nil
 	}
 
 	// Binary and UTF8 encoded payloads should pass through untouched
 	return string(pld.Data), nil
 }
+
+// Len returns the length of the payload data in bytes.
+// The header padding of UTF16 encoded messages isn't part of the payload data
+// and is therefore never counted
+func (pld Payload) Len() int {
+	return len(pld.Data)
+}
+
+// IsEmpty returns true if the payload carries no data
+func (pld Payload) IsEmpty() bool {
+	return len(pld.Data) < 1
+}
+
+// Hash returns a stable 64-bit FNV-1a hash of the payload computed over
+// the encoding, the content type and the data, which is equal for equal
+// payloads regardless of the capacity of the data slice.
+// It's intended for keying caches and detecting duplicate payloads,
+// it's not a cryptographic hash
+func (pld Payload) Hash() uint64 {
+	// Payloads of unspecified encoding are treated as Binary
+	encoding := pld.Encoding
+	if encoding == Default {
+		encoding = Binary
+	}
+
+	hash := fnv.New64a()
+	hash.Write([]byte{byte(encoding), byte(len(pld.ContentType))})
+	hash.Write([]byte(pld.ContentType))
+	hash.Write(pld.Data)
+	return hash.Sum64()
+}
@@ -0,0 +1,49 @@
+package payload
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// TestPayloadHash tests whether the payload hash is stable
+// and consistent for equal payloads
+func TestPayloadHash(t *testing.T) {
+	payload := Payload{Encoding: Utf8, Data: []byte("payload")}
+
+	// Expect the hash to be stable across runs
+	require.Equal(t, uint64(17600445678020579090), payload.Hash())
+
+	// Expect the capacity of the data slice to be irrelevant
+	data := make([]byte, 0, 64)
+	data = append(data, "payload"...)
+	require.Equal(
+		t,
+		payload.Hash(),
+		Payload{Encoding: Utf8, Data: data}.Hash(),
+	)
+
+	// Expect the encoding, the content type and the data to be relevant
+	require.NotEqual(
+		t,
+		payload.Hash(),
+		Payload{Encoding: Binary, Data: []byte("payload")}.Hash(),
+	)
+	require.NotEqual(
+		t,
+		Payload{Data: []byte("payload")}.Hash(),
+		Payload{ContentType: "text/plain", Data: []byte("payload")}.Hash(),
+	)
+	require.NotEqual(
+		t,
+		payload.Hash(),
+		Payload{Encoding: Utf8, Data: []byte("payloaD")}.Hash(),
+	)
+
+	// Expect payloads of unspecified encoding to be treated as binary
+	require.Equal(
+		t,
+		Payload{Encoding: Binary, Data: []byte("payload")}.Hash(),
+		Payload{Encoding: Default, Data: []byte("payload")}.Hash(),
+	)
+}
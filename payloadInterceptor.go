@@ -0,0 +1,88 @@
+package webwire
+
+import (
+	"fmt"
+
+	msg "github.com/qbeon/webwire-go/message"
+	pld "github.com/qbeon/webwire-go/payload"
+)
+
+// nopPayloadInterceptor represents the default no-op implementation
+// of the PayloadInterceptor interface
+type nopPayloadInterceptor struct{}
+
+// Outbound implements the PayloadInterceptor interface
+func (icp nopPayloadInterceptor) Outbound(data []byte) ([]byte, error) {
+	return data, nil
+}
+
+// Inbound implements the PayloadInterceptor interface
+func (icp nopPayloadInterceptor) Inbound(data []byte) ([]byte, error) {
+	return data, nil
+}
+
+// interceptsPayloads returns true if a payload interceptor other than
+// the default no-op interceptor is set, otherwise returns false
+func (srv *server) interceptsPayloads() bool {
+	_, isNop := srv.options.PayloadInterceptor.(nopPayloadInterceptor)
+	return !isNop
+}
+
+// interceptOutbound applies the outbound payload transformation
+// to the given non-empty payload data and verifies the UTF16 alignment
+// of the transformed data
+func (srv *server) interceptOutbound(
+	encoding PayloadEncoding,
+	data []byte,
+) ([]byte, error) {
+	if len(data) < 1 {
+		return data, nil
+	}
+	transformed, err := srv.options.PayloadInterceptor.Outbound(data)
+	if err != nil {
+		return nil, fmt.Errorf("Outbound payload interception failed: %s", err)
+	}
+	if encoding == EncodingUtf16 && len(transformed)%2 != 0 {
+		return nil, fmt.Errorf(
+			"Invalid UTF16 payload data length after interception: %d",
+			len(transformed),
+		)
+	}
+	return transformed, nil
+}
+
+// interceptInbound applies the inbound payload transformation
+// to the payload of the given request or signal message
+// and verifies the UTF16 alignment of the transformed data
+func (srv *server) interceptInbound(message *msg.Message) error {
+	switch message.Type {
+	case msg.MsgSignalBinary:
+	case msg.MsgSignalUtf8:
+	case msg.MsgSignalUtf16:
+	case msg.MsgSignalTyped:
+	case msg.MsgRequestBinary:
+	case msg.MsgRequestUtf8:
+	case msg.MsgRequestUtf16:
+	case msg.MsgRequestTyped:
+	default:
+		return nil
+	}
+
+	if message.Payload.IsEmpty() {
+		return nil
+	}
+	transformed, err := srv.options.PayloadInterceptor.Inbound(
+		message.Payload.Data,
+	)
+	if err != nil {
+		return fmt.Errorf("Inbound payload interception failed: %s", err)
+	}
+	if message.Payload.Encoding == pld.Utf16 && len(transformed)%2 != 0 {
+		return fmt.Errorf(
+			"Invalid UTF16 payload data length after interception: %d",
+			len(transformed),
+		)
+	}
+	message.Payload.Data = transformed
+	return nil
+}
@@ -0,0 +1,95 @@
+package webwire
+
+import (
+	"fmt"
+
+	msg "github.com/qbeon/webwire-go/message"
+)
+
+// PrepareSignal encodes a named signal containing the given payload once
+// and returns the resulting message to be sent to any number of clients
+// using Connection.SendRaw without re-encoding it for each of them.
+//
+// The returned slice is shared by all connections it's sent to
+// and must therefore be treated as immutable:
+// it must neither be modified nor reused for other data after it was prepared.
+//
+// The name is verified by the optional name validator
+// which defaults to msg.ValidateNameASCII.
+// Since prepared signals aren't bound to a server an EncodingDefault
+// encoded payload is prepared as binary
+func PrepareSignal(
+	name string,
+	payload Payload,
+	nameValidator ...msg.NameValidator,
+) ([]byte, error) {
+	if len(name) > 255 {
+		return nil, fmt.Errorf("Unsupported signal name length: %d", len(name))
+	}
+	validateName := msg.ValidateNameASCII
+	if len(nameValidator) > 0 && nameValidator[0] != nil {
+		validateName = nameValidator[0]
+	}
+	if err := validateName(name); err != nil {
+		return nil, err
+	}
+
+	var encoding PayloadEncoding
+	var data []byte
+	var contentType string
+	if payload != nil {
+		encoding = payload.Encoding()
+		data = payload.Data()
+
+		var err error
+		if contentType, err = PayloadContentType(payload); err != nil {
+			return nil, err
+		}
+	}
+
+	if contentType != "" {
+		return msg.NewTypedSignalMessage(
+			name,
+			contentType,
+			data,
+			validateName,
+		), nil
+	}
+
+	if encoding == EncodingUtf16 && len(data)%2 != 0 {
+		return nil, fmt.Errorf(
+			"Invalid UTF16 signal payload data length: %d",
+			len(data),
+		)
+	}
+
+	return msg.NewSignalMessage(name, encoding, data, validateName), nil
+}
+
+// preparedSignalName returns the name of the given prepared signal
+func preparedSignalName(prebuilt []byte) string {
+	nameLen := int(prebuilt[1])
+	if len(prebuilt) < 2+nameLen {
+		return ""
+	}
+	return string(prebuilt[2 : 2+nameLen])
+}
+
+// isPreparedSignal returns true if the given message
+// represents a signal message, otherwise returns false
+func isPreparedSignal(message []byte) bool {
+	if len(message) < msg.MsgMinLenSignal {
+		return false
+	}
+	switch message[0] {
+	case msg.MsgSignalBinary:
+		return true
+	case msg.MsgSignalUtf8:
+		return true
+	case msg.MsgSignalUtf16:
+		return true
+	case msg.MsgSignalTyped:
+		return true
+	}
+	return false
+}
@@ -0,0 +1,37 @@
+package webwire_test
+
+import (
+	"strings"
+	"testing"
+
+	wwr "github.com/qbeon/webwire-go"
+	msg "github.com/qbeon/webwire-go/message"
+	"github.com/stretchr/testify/require"
+)
+
+// TestPrepareSignal tests preparing a signal message
+func TestPrepareSignal(t *testing.T) {
+	payload := wwr.NewPayload(wwr.EncodingUtf8, []byte("payload"))
+	prepared, err := wwr.PrepareSignal("name", payload)
+	require.NoError(t, err)
+	require.Equal(t, msg.NewSignalMessage(
+		"name",
+		payload.Encoding(),
+		payload.Data(),
+	), prepared)
+}
+
+// TestPrepareSignalInvalid tests preparing invalid signal messages
+func TestPrepareSignalInvalid(t *testing.T) {
+	_, err := wwr.PrepareSignal(strings.Repeat("n", 256), nil)
+	require.Error(t, err)
+
+	_, err = wwr.PrepareSignal("invalid\nname", nil)
+	require.Error(t, err)
+
+	_, err = wwr.PrepareSignal("name", wwr.NewPayload(
+		wwr.EncodingUtf16,
+		[]byte("odd"),
+	))
+	require.Error(t, err)
+}
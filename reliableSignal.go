@@ -0,0 +1,111 @@
+package webwire
+
+import (
+	"encoding/binary"
+	"fmt"
+	"time"
+
+	msg "github.com/qbeon/webwire-go/message"
+)
+
+// ReliableSignal implements the Connection interface
+func (con *connection) ReliableSignal(name string, payload Payload) error {
+	if err := con.srv.options.NameValidator(name); err != nil {
+		return err
+	}
+	if typed, isTyped := payload.(ContentTyped); isTyped &&
+		typed.ContentType() != "" {
+		return fmt.Errorf("Reliable signals don't support content types")
+	}
+
+	// Transform the signal payload
+	encoding := con.srv.resolveEncoding(payload.Encoding())
+	data, err := con.srv.interceptOutbound(encoding, payload.Data())
+	if err != nil {
+		return err
+	}
+	if len(name) < 1 && len(data) < 1 {
+		return fmt.Errorf(
+			"Reliable signal requires either a name, a payload or both",
+		)
+	}
+
+	identifier, acknowledged := con.registerSignalAck()
+	defer con.deregisterSignalAck(identifier)
+
+	message := msg.NewReliableSignalMessage(
+		identifier,
+		name,
+		encoding,
+		data,
+		con.srv.options.NameValidator,
+	)
+
+	attempts := con.srv.options.ReliableSignalAttempts
+	for attempt := uint(0); attempt < attempts; attempt++ {
+		if err := con.write(message); err != nil {
+			return err
+		}
+
+		timeout := time.NewTimer(con.srv.options.SignalAckTimeout)
+		select {
+		case <-acknowledged:
+			timeout.Stop()
+			return nil
+		case <-con.ctx.Done():
+			timeout.Stop()
+			return DisconnectedErr{
+				Cause: fmt.Errorf(
+					"Connection closed before the reliable signal " +
+						"was acknowledged",
+				),
+			}
+		case <-timeout.C:
+		}
+	}
+
+	return NewTimeoutErr(fmt.Errorf(
+		"Reliable signal %q wasn't acknowledged after %d attempts",
+		name,
+		attempts,
+	))
+}
+
+// registerSignalAck generates a new reliable signal identifier
+// and returns it along with the channel closed on acknowledgement
+func (con *connection) registerSignalAck() ([8]byte, chan struct{}) {
+	var identifier [8]byte
+	acknowledged := make(chan struct{})
+
+	con.signalAcksLock.Lock()
+	con.lastSignalIdent++
+	binary.BigEndian.PutUint64(identifier[:], con.lastSignalIdent)
+	if con.signalAcks == nil {
+		con.signalAcks = make(map[[8]byte]chan struct{})
+	}
+	con.signalAcks[identifier] = acknowledged
+	con.signalAcksLock.Unlock()
+
+	return identifier, acknowledged
+}
+
+// deregisterSignalAck stops awaiting the acknowledgement
+// of the given reliable signal
+func (con *connection) deregisterSignalAck(identifier [8]byte) {
+	con.signalAcksLock.Lock()
+	delete(con.signalAcks, identifier)
+	con.signalAcksLock.Unlock()
+}
+
+// acknowledgeSignal marks the given reliable signal as acknowledged,
+// redundant and unknown acknowledgements are ignored
+func (con *connection) acknowledgeSignal(identifier [8]byte) {
+	con.signalAcksLock.Lock()
+	acknowledged, awaited := con.signalAcks[identifier]
+	delete(con.signalAcks, identifier)
+	con.signalAcksLock.Unlock()
+
+	if awaited {
+		close(acknowledged)
+	}
+}
@@ -0,0 +1,21 @@
+package webwire
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// Reply JSON-encodes the given value and returns it
+// as a UTF8 encoded reply payload
+func Reply(value interface{}) (Payload, error) {
+	encoded, err := json.Marshal(value)
+	if err != nil {
+		return nil, fmt.Errorf("Couldn't encode reply payload: %s", err)
+	}
+	return NewPayload(EncodingUtf8, encoded), nil
+}
+
+// ReplyBinary returns the given data as a binary reply payload
+func ReplyBinary(data []byte) Payload {
+	return NewPayload(EncodingBinary, data)
+}
@@ -0,0 +1,37 @@
+package webwire_test
+
+import (
+	"testing"
+
+	wwr "github.com/qbeon/webwire-go"
+	"github.com/stretchr/testify/require"
+)
+
+// TestReply tests the JSON encoding reply helper
+func TestReply(t *testing.T) {
+	payload, err := wwr.Reply(struct {
+		Name  string `json:"name"`
+		Value int    `json:"value"`
+	}{
+		Name:  "sample",
+		Value: 42,
+	})
+	require.NoError(t, err)
+	require.Equal(t, wwr.EncodingUtf8, payload.Encoding())
+	require.Equal(t, []byte(`{"name":"sample","value":42}`), payload.Data())
+}
+
+// TestReplyUnencodable tests the JSON encoding reply helper
+// with a value that can't be encoded
+func TestReplyUnencodable(t *testing.T) {
+	payload, err := wwr.Reply(make(chan int))
+	require.Error(t, err)
+	require.Nil(t, payload)
+}
+
+// TestReplyBinary tests the binary reply helper
+func TestReplyBinary(t *testing.T) {
+	payload := wwr.ReplyBinary([]byte{1, 2, 3})
+	require.Equal(t, wwr.EncodingBinary, payload.Encoding())
+	require.Equal(t, []byte{1, 2, 3}, payload.Data())
+}
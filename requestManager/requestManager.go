@@ -4,6 +4,7 @@ import (
 	"context"
 	"encoding/binary"
 	"fmt"
+	"sort"
 	"sync"
 	"time"
 
@@ -11,8 +12,15 @@ import (
 	pld "github.com/qbeon/webwire-go/payload"
 )
 
-// RequestIdentifier represents the universally unique, minified
-// UUIDv4 identifier of a request.
+// RequestIdentifier represents the identifier of a request.
+// Identifiers are assigned by incrementing a counter that's never reset
+// during the lifetime of a request manager, thus a client never reuses
+// an identifier even across reconnects. The counter is encoded
+// in little-endian byte order starting at 1, which makes identifiers easy
+// to correlate in logs: the n-th request of a client is identified
+// by binary.LittleEndian.Uint64(identifier[:]) == n.
+// Identifiers are not unique across different clients though
+// and servers must therefore scope identifier-keyed state by connection
 type RequestIdentifier = [8]byte
 
 // reply is used by the request manager to represent the results
@@ -33,8 +41,17 @@ type Request struct {
 	// timeout represents the configured timeout duration of this request
 	timeout time.Duration
 
-	// reply represents a channel for asynchronous reply handling
+	// reply represents a channel for asynchronous reply handling,
+	// it's buffered to never block the sender of the reply
+	// even if the request timed out in the meantime
 	reply chan reply
+
+	// chunks buffers the received chunks of a streamed reply
+	chunks []byte
+
+	// replay holds the encoded message of replayable requests,
+	// see MarkReplayable
+	replay []byte
 }
 
 // Identifier returns the assigned request identifier
@@ -42,6 +59,17 @@ func (req *Request) Identifier() RequestIdentifier {
 	return req.identifier
 }
 
+// MarkReplayable marks the request as safe to be sent again on a new
+// connection if the connection is lost before the reply is received,
+// which is the case for requests carrying an idempotency key.
+// The given encoded request message is returned by ReplayMessages
+// for as long as the request is pending, see FailLost
+func (req *Request) MarkReplayable(message []byte) {
+	req.manager.lock.Lock()
+	req.replay = message
+	req.manager.lock.Unlock()
+}
+
 // AwaitReply blocks the calling goroutine
 // until either the reply is fulfilled or failed, the request timed out
 // a user-defined deadline was exceeded or the request was prematurely canceled.
@@ -113,7 +141,9 @@ func (manager *RequestManager) Create(timeout time.Duration) *Request {
 		manager,
 		identifier,
 		timeout,
-		make(chan reply),
+		make(chan reply, 1),
+		nil,
+		nil,
 	}
 
 	// Register the newly created request
@@ -132,6 +162,19 @@ func (manager *RequestManager) deregister(identifier RequestIdentifier) {
 	manager.lock.Unlock()
 }
 
+// take deregisters and returns the pending request associated with the given
+// identifier. Returns false if no such request is pending.
+// Taking a request ensures that it's replied to at most once
+func (manager *RequestManager) take(
+	identifier RequestIdentifier,
+) (*Request, bool) {
+	manager.lock.Lock()
+	req, exists := manager.pending[identifier]
+	delete(manager.pending, identifier)
+	manager.lock.Unlock()
+	return req, exists
+}
+
 // Fulfill fulfills the request associated with the given request identifier
 // with the provided reply payload.
 // Returns true if a pending request was fulfilled and deregistered,
@@ -140,20 +183,54 @@ func (manager *RequestManager) Fulfill(
 	identifier RequestIdentifier,
 	payload pld.Payload,
 ) bool {
-	manager.lock.RLock()
-	req, exists := manager.pending[identifier]
-	manager.lock.RUnlock()
+	req, exists := manager.take(identifier)
 	if !exists {
 		return false
 	}
 
+	// Reassemble the payload of streamed replies
+	if req.chunks != nil {
+		payload.Data = append(req.chunks, payload.Data...)
+	}
+
 	req.reply <- reply{
 		Reply: &webwire.EncodedPayload{
 			Payload: payload,
 		},
 		Error: nil,
 	}
-	manager.deregister(identifier)
+	return true
+}
+
+// AppendChunk buffers a chunk of the streamed reply to the request
+// associated with the given request identifier.
+// The request is failed with a webwire.ReplyTooLargeErr if the buffered
+// reply would exceed the given maximum size in bytes.
+// Returns true if the chunk was appended to a pending request,
+// otherwise returns false
+func (manager *RequestManager) AppendChunk(
+	identifier RequestIdentifier,
+	chunk []byte,
+	maxSize int,
+) bool {
+	manager.lock.Lock()
+	req, exists := manager.pending[identifier]
+	if !exists {
+		manager.lock.Unlock()
+		return false
+	}
+	if len(req.chunks)+len(chunk) > maxSize {
+		delete(manager.pending, identifier)
+		manager.lock.Unlock()
+		req.chunks = nil
+		req.reply <- reply{
+			Reply: nil,
+			Error: webwire.ReplyTooLargeErr{MaxSize: maxSize},
+		}
+		return false
+	}
+	req.chunks = append(req.chunks, chunk...)
+	manager.lock.Unlock()
 	return true
 }
 
@@ -164,9 +241,7 @@ func (manager *RequestManager) Fail(
 	identifier RequestIdentifier,
 	err error,
 ) bool {
-	manager.lock.RLock()
-	req, exists := manager.pending[identifier]
-	manager.lock.RUnlock()
+	req, exists := manager.take(identifier)
 	if !exists {
 		return false
 	}
@@ -174,10 +249,76 @@ func (manager *RequestManager) Fail(
 		Reply: nil,
 		Error: err,
 	}
-	manager.deregister(identifier)
 	return true
 }
 
+// FailAll fails all currently pending requests with the provided error
+// and returns the number of failed requests
+func (manager *RequestManager) FailAll(err error) int {
+	manager.lock.Lock()
+	pending := manager.pending
+	manager.pending = make(map[RequestIdentifier]*Request)
+	manager.lock.Unlock()
+
+	for _, req := range pending {
+		req.reply <- reply{
+			Reply: nil,
+			Error: err,
+		}
+	}
+	return len(pending)
+}
+
+// FailLost fails all currently pending requests that aren't replayable
+// (see Request.MarkReplayable) with the provided error and returns
+// the number of failed requests. It's called when the connection is lost
+// while the replayable requests remain pending to be replayed
+// on the next connection
+func (manager *RequestManager) FailLost(err error) int {
+	manager.lock.Lock()
+	lost := make([]*Request, 0, len(manager.pending))
+	for identifier, req := range manager.pending {
+		if req.replay == nil {
+			lost = append(lost, req)
+			delete(manager.pending, identifier)
+		}
+	}
+	manager.lock.Unlock()
+
+	for _, req := range lost {
+		req.reply <- reply{
+			Reply: nil,
+			Error: err,
+		}
+	}
+	return len(lost)
+}
+
+// ReplayMessages returns the encoded messages of all currently pending
+// replayable requests (see Request.MarkReplayable)
+// in the order the requests were created
+func (manager *RequestManager) ReplayMessages() [][]byte {
+	manager.lock.RLock()
+	replayable := make([]*Request, 0)
+	for _, req := range manager.pending {
+		if req.replay != nil {
+			replayable = append(replayable, req)
+		}
+	}
+	manager.lock.RUnlock()
+
+	sort.Slice(replayable, func(i, j int) bool {
+		return binary.LittleEndian.Uint64(replayable[i].identifier[:]) <
+			binary.LittleEndian.Uint64(replayable[j].identifier[:])
+	})
+
+	messages := make([][]byte, len(replayable))
+	for i, req := range replayable {
+		messages[i] = req.replay
+	}
+	return messages
+}
+
 // PendingRequests returns the number of currently pending requests
 func (manager *RequestManager) PendingRequests() int {
 	manager.lock.RLock()
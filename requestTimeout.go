@@ -0,0 +1,113 @@
+package webwire
+
+import (
+	"context"
+	"time"
+)
+
+// SetRequestTimeout implements the Server interface
+func (srv *server) SetRequestTimeout(name string, timeout time.Duration) {
+	srv.requestTimeoutsLock.Lock()
+	if timeout < 1 {
+		delete(srv.requestTimeouts, name)
+	} else {
+		srv.requestTimeouts[name] = timeout
+	}
+	srv.requestTimeoutsLock.Unlock()
+}
+
+// requestTimeout returns the timeout of requests of the given name
+// falling back to ServerOptions.RequestTimeout
+func (srv *server) requestTimeout(name string) time.Duration {
+	srv.requestTimeoutsLock.RLock()
+	timeout, defined := srv.requestTimeouts[name]
+	srv.requestTimeoutsLock.RUnlock()
+	if defined {
+		return timeout
+	}
+	return srv.options.RequestTimeout
+}
+
+// handlerResult represents the outcome of a request handler
+// invoked by callWithRequestTimeout
+type handlerResult struct {
+	payload   Payload
+	err       error
+	recovered interface{}
+}
+
+// callWithRequestTimeout calls the given request handler and returns
+// a RequestTimeoutErr if it exceeds the timeout of requests
+// of the given name, in which case the context of the handler is canceled
+// and its result is discarded. The handler then remains registered
+// as a pending operation of both the server and the connection
+// until it returns, the server therefore awaits it during shutdown.
+// Panics of the handler are propagated to the caller unless it exceeded
+// the timeout
+func (srv *server) callWithRequestTimeout(
+	ctx context.Context,
+	con *connection,
+	name string,
+	handler func(context.Context) (Payload, error),
+) (Payload, error) {
+	timeout := srv.requestTimeout(name)
+	if timeout < 1 {
+		return handler(ctx)
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	result := make(chan handlerResult, 1)
+	srv.spawn(func() {
+		var res handlerResult
+		defer func() {
+			res.recovered = recover()
+			result <- res
+		}()
+		res.payload, res.err = handler(ctx)
+	})
+
+	select {
+	case res := <-result:
+		if res.recovered != nil {
+			panic(res.recovered)
+		}
+		return res.payload, res.err
+	case <-ctx.Done():
+	}
+
+	// Keep the handler registered until it returns unless the server
+	// already finished awaiting pending operations during shutdown
+	srv.opsLock.Lock()
+	registered := !srv.shutdown || srv.currentOps > 0
+	if registered {
+		srv.currentOps++
+	}
+	srv.opsLock.Unlock()
+	con.registerTask()
+
+	// Log panics of the handler occurring after the timeout
+	srv.spawn(func() {
+		if res := <-result; res.recovered != nil {
+			srv.errorLog.Printf(
+				"Handling request %q panicked after timing out: %v",
+				name,
+				res.recovered,
+			)
+		}
+
+		// Mark the handler as done and shutdown the server
+		// if scheduled and no ops are left
+		if registered {
+			srv.opsLock.Lock()
+			srv.currentOps--
+			if srv.shutdown && srv.currentOps < 1 {
+				close(srv.shutdownRdy)
+			}
+			srv.opsLock.Unlock()
+		}
+		con.deregisterTask()
+	})
+	return nil, RequestTimeoutErr{}
+}
@@ -0,0 +1,90 @@
+package webwire
+
+import (
+	"context"
+	"net/url"
+	"strconv"
+)
+
+// RequestVersionsHeader is the name of the upgrade request header
+// carrying the request payload schema versions agreed on by the client,
+// see ServerOptions.RequestVersions
+const RequestVersionsHeader = "Webwire-Request-Versions"
+
+// requestVersionKey is the context key of the request payload schema version
+type requestVersionKey struct{}
+
+// RequestVersion returns the payload schema version of the currently handled
+// request agreed on with the client during the connection establishment.
+// Returns 0 if no version was agreed on for the name of the request
+func RequestVersion(ctx context.Context) int {
+	version, _ := ctx.Value(requestVersionKey{}).(int)
+	return version
+}
+
+// NegotiateRequestVersions returns the highest payload schema version
+// supported by both the server and the client for each request name.
+// Request names without any commonly supported version are omitted
+func NegotiateRequestVersions(
+	serverVersions map[string][]int,
+	clientVersions map[string][]int,
+) map[string]int {
+	agreed := make(map[string]int)
+	for name, clientSupported := range clientVersions {
+		for _, version := range clientSupported {
+			if version > agreed[name] &&
+				supportsVersion(serverVersions[name], version) {
+				agreed[name] = version
+			}
+		}
+	}
+	return agreed
+}
+
+// EncodeRequestVersions encodes the given agreed request payload schema
+// versions for them to be sent in the RequestVersionsHeader header
+func EncodeRequestVersions(versions map[string]int) string {
+	values := url.Values{}
+	for name, version := range versions {
+		values.Set(name, strconv.Itoa(version))
+	}
+	return values.Encode()
+}
+
+// parseRequestVersions parses the request payload schema versions
+// agreed on by the client ignoring versions not supported by the server
+func (srv *server) parseRequestVersions(header string) map[string]int {
+	if header == "" || srv.options.RequestVersions == nil {
+		return nil
+	}
+	values, err := url.ParseQuery(header)
+	if err != nil {
+		srv.warnLog.Printf("Couldn't parse request versions: %s", err)
+		return nil
+	}
+	agreed := make(map[string]int, len(values))
+	for name := range values {
+		version, err := strconv.Atoi(values.Get(name))
+		if err != nil ||
+			!supportsVersion(srv.options.RequestVersions[name], version) {
+			srv.warnLog.Printf(
+				"Ignoring unsupported version of request %q: %q",
+				name,
+				values.Get(name),
+			)
+			continue
+		}
+		agreed[name] = version
+	}
+	return agreed
+}
+
+// supportsVersion returns true if the given version is listed
+func supportsVersion(supported []int, version int) bool {
+	for _, supportedVersion := range supported {
+		if supportedVersion == version {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,52 @@
+package webwire
+
+// RetainSignal implements the Server interface
+func (srv *server) RetainSignal(name string, payload Payload) error {
+	if err := srv.options.NameValidator(name); err != nil {
+		return err
+	}
+
+	// Copy the payload data to prevent it from being mutated
+	// after the signal is retained
+	var data []byte
+	if payload.Data() != nil {
+		data = make([]byte, len(payload.Data()))
+		copy(data, payload.Data())
+	}
+
+	srv.retainedSignalsLock.Lock()
+	srv.retainedSignals[name] = NewPayload(payload.Encoding(), data)
+	srv.retainedSignalsLock.Unlock()
+	return nil
+}
+
+// ClearRetainedSignal implements the Server interface
+func (srv *server) ClearRetainedSignal(name string) {
+	srv.retainedSignalsLock.Lock()
+	delete(srv.retainedSignals, name)
+	srv.retainedSignalsLock.Unlock()
+}
+
+// sendRetainedSignals sends all currently retained signals
+// to the given connection
+func (srv *server) sendRetainedSignals(con *connection) {
+	srv.retainedSignalsLock.RLock()
+	signals := make(map[string]Payload, len(srv.retainedSignals))
+	for name, payload := range srv.retainedSignals {
+		signals[name] = payload
+	}
+	srv.retainedSignalsLock.RUnlock()
+
+	for name, payload := range signals {
+		if !con.IsActive() {
+			return
+		}
+		if err := con.Signal(name, payload); err != nil {
+			srv.errorLog.Printf(
+				"Couldn't send retained signal %q: %s",
+				name,
+				err,
+			)
+		}
+	}
+}
@@ -3,7 +3,11 @@ package webwire
 import (
 	"fmt"
 	"net/http"
+	"net/url"
 	"time"
+
+	"github.com/gorilla/websocket"
+	msg "github.com/qbeon/webwire-go/message"
 )
 
 // ServeHTTP will make the server listen for incoming HTTP requests
@@ -24,14 +28,27 @@ func (srv *server) ServeHTTP(
 
 	switch req.Method {
 	case "OPTIONS":
-		srv.impl.OnOptions(resp)
+		srv.implementation().OnOptions(resp)
 		return
 	case "WEBWIRE":
 		srv.handleMetadata(resp)
 		return
 	}
 
-	connectionOptions := srv.impl.BeforeUpgrade(resp, req)
+	// Kindly reject plain HTTP requests such as a browser navigating
+	// to the endpoint directly
+	if !websocket.IsWebSocketUpgrade(req) {
+		resp.Header().Set("Upgrade", "websocket")
+		http.Error(
+			resp,
+			"This is a webwire endpoint, "+
+				"it only accepts WebSocket connections",
+			http.StatusUpgradeRequired,
+		)
+		return
+	}
+
+	connectionOptions := srv.beforeUpgrade(resp, req)
 
 	// Abort connection establishment if no options are provided
 	if connectionOptions == nil || !connectionOptions.Accept() {
@@ -48,9 +65,7 @@ func (srv *server) ServeHTTP(
 
 	// Set ping/pong handlers
 	conn.OnPong(func(string) error {
-		if err := conn.SetReadDeadline(
-			time.Now().Add(srv.options.HeartbeatTimeout),
-		); err != nil {
+		if err := srv.refreshReadDeadline(conn); err != nil {
 			return fmt.Errorf(
 				"Couldn't set read deadline in Pong handler: %s",
 				err,
@@ -59,9 +74,7 @@ func (srv *server) ServeHTTP(
 		return nil
 	})
 	conn.OnPing(func(string) error {
-		if err := conn.SetReadDeadline(
-			time.Now().Add(srv.options.HeartbeatTimeout),
-		); err != nil {
+		if err := srv.refreshReadDeadline(conn); err != nil {
 			return fmt.Errorf(
 				"Couldn't set read deadline in Ping handler: %s",
 				err,
@@ -69,9 +82,7 @@ func (srv *server) ServeHTTP(
 		}
 		return nil
 	})
-	if err := conn.SetReadDeadline(
-		time.Now().Add(srv.options.HeartbeatTimeout),
-	); err != nil {
+	if err := srv.refreshReadDeadline(conn); err != nil {
 		srv.errorLog.Printf("Couldn't set read deadline: %s", err)
 		return
 	}
@@ -83,39 +94,206 @@ func (srv *server) ServeHTTP(
 		srv,
 		connectionOptions,
 	)
+	connection.header = forwardHeaders(req.Header, srv.options.ForwardedHeaders)
+	connection.connectParams = retainConnectParams(
+		req.URL.Query(),
+		srv.options.ConnectParams,
+	)
+	connection.requestVersions = srv.parseRequestVersions(
+		req.Header.Get(RequestVersionsHeader),
+	)
+	if srv.options.IdempotencyStore != nil {
+		connection.idempotencyScope = connectionIdempotencyScope(
+			req.Header.Get(IdempotencyScopeHeader),
+		)
+	}
 
 	srv.connectionsLock.Lock()
 	srv.connections = append(srv.connections, connection)
 	srv.connectionsLock.Unlock()
 
+	// Don't let the connection await the capacity to handle messages
+	// if the server was shut down while it was registered
+	if srv.isShuttingDown() {
+		connection.cancelAwait()
+	}
+
+	// disconnect closes the connection and invokes the disconnection hook
+	// only once, even if serving the connection panics
+	disconnected := false
+	disconnect := func(reason DisconnectReason) {
+		if disconnected {
+			return
+		}
+		disconnected = true
+		connection.Close()
+		srv.emitEvent(ServerEvent{
+			Type:             EventClientDisconnected,
+			Connection:       connection,
+			DisconnectReason: reason,
+		})
+		srv.implementation().OnClientDisconnected(connection, reason)
+	}
+
+	// Clean up the connection if serving it panics
+	defer func() {
+		if recovered := recover(); recovered != nil {
+			srv.errorLog.Printf(
+				"Serving client %v panicked: %v",
+				connection.Info().RemoteAddr,
+				recovered,
+			)
+			disconnect(DisconnectInternalError)
+			srv.deregisterConnection(connection)
+		}
+	}()
+
 	// Call hook on successful connection
-	srv.impl.OnClientConnected(connection)
+	srv.emitEvent(ServerEvent{
+		Type:       EventClientConnected,
+		Connection: connection,
+	})
+	srv.implementation().OnClientConnected(connection)
+
+	// Replay retained signals to the newly connected client
+	srv.sendRetainedSignals(connection)
 
 	// Start heartbeat sender (if enabled)
-	stopHeartbeat := make(chan struct{}, 1)
 	if srv.options.Heartbeat == Enabled {
-		go srv.heartbeat(conn, stopHeartbeat)
+		stopHeartbeat := make(chan struct{}, 1)
+		defer func() { stopHeartbeat <- struct{}{} }()
+		srv.spawn(func() { srv.heartbeat(conn, stopHeartbeat) })
 	}
 
 	for {
 		// Await message
 		message, err := conn.Read()
 		if err != nil {
-			if err.IsAbnormalCloseErr() {
-				srv.warnLog.Printf("Abnormal closure error: %s", err)
+			srv.logClosure(err)
+
+			// Determine the disconnect reason before closing the connection
+			reason := err.DisconnectReason()
+			if connection.hasFailed() {
+				reason = DisconnectInternalError
+			} else if connection.isSlowConsumer() {
+				reason = DisconnectSlowConsumer
+			} else if !connection.IsActive() {
+				reason = DisconnectServerInitiated
+			}
+
+			// Tell the client why it's being disconnected
+			if code := reason.CloseCode(); code != 0 {
+				if closer, isCloser := conn.(CloseCoder); isCloser {
+					closer.CloseWithCode(code, reason.String())
+				}
 			}
 
-			connection.Close()
-			srv.impl.OnClientDisconnected(connection)
+			disconnect(reason)
+			srv.deregisterConnection(connection)
 			break
 		}
 
+		connection.touch()
+
+		// Postpone the read deadline if a read timeout is specified
+		if srv.options.ReadTimeout > 0 {
+			if err := srv.refreshReadDeadline(conn); err != nil {
+				srv.errorLog.Printf("Couldn't set read deadline: %s", err)
+			}
+		}
+
+		// Handle signal flow control messages synchronously
+		// to preserve their order
+		if isSignalFlowControl(message) {
+			srv.handleMessage(connection, message)
+			continue
+		}
+
+		// Wait for goroutine capacity if the number of goroutines is limited
+		// and for a free worker slot if the worker pool is bounded,
+		// blocking the read loop of this connection in the meantime
+		if err := srv.awaitHandlerCapacity(connection); err != nil {
+			// Stop serving the connection if it was closed
+			// or the server was shut down in the meantime
+			disconnect(DisconnectServerInitiated)
+			srv.deregisterConnection(connection)
+			break
+		}
+
+		// Don't count the time spent waiting for handler capacity
+		// as idle time, the read deadline could've passed in the meantime
+		if srv.goroutineSlots != nil || srv.workerSlots != nil {
+			if err := srv.refreshReadDeadline(conn); err != nil {
+				srv.errorLog.Printf("Couldn't set read deadline: %s", err)
+			}
+		}
+
 		// Parse & handle the message
-		go srv.handleMessage(connection, message)
+		srv.spawnHandler(func() { srv.handleMessage(connection, message) })
 	}
+}
 
-	// Connection closed
-	if srv.options.Heartbeat == Enabled {
-		stopHeartbeat <- struct{}{}
+// refreshReadDeadline postpones the read deadline of the given socket
+// by the read timeout, or by the heartbeat timeout if no read timeout
+// is specified
+func (srv *server) refreshReadDeadline(conn Socket) error {
+	timeout := srv.options.HeartbeatTimeout
+	if srv.options.ReadTimeout > 0 {
+		timeout = srv.options.ReadTimeout
+	}
+	return conn.SetReadDeadline(time.Now().Add(timeout))
+}
+
+// isSignalFlowControl returns true if the given message
+// is either a signal pause or resumption request
+func isSignalFlowControl(message []byte) bool {
+	return len(message) > 0 && (message[0] == msg.MsgPauseSignals ||
+		message[0] == msg.MsgResumeSignals)
+}
+
+// deregisterConnection removes the given connection
+// from the list of connections
+func (srv *server) deregisterConnection(con *connection) {
+	srv.connectionsLock.Lock()
+	for i, registered := range srv.connections {
+		if registered == con {
+			last := len(srv.connections) - 1
+			srv.connections[i] = srv.connections[last]
+			srv.connections[last] = nil
+			srv.connections = srv.connections[:last]
+			break
+		}
+	}
+	srv.connectionsLock.Unlock()
+}
+
+// forwardHeaders returns a copy of the given headers
+// containing only the headers of the given names
+func forwardHeaders(header http.Header, names []string) http.Header {
+	forwarded := make(http.Header, len(names))
+	for _, name := range names {
+		values := header[http.CanonicalHeaderKey(name)]
+		if len(values) < 1 {
+			continue
+		}
+		forwarded[http.CanonicalHeaderKey(name)] = append(
+			[]string(nil),
+			values...,
+		)
+	}
+	return forwarded
+}
+
+// retainConnectParams returns a copy of the given query parameters
+// containing only the parameters of the given keys
+func retainConnectParams(query url.Values, keys []string) url.Values {
+	retained := make(url.Values, len(keys))
+	for _, key := range keys {
+		values := query[key]
+		if len(values) < 1 {
+			continue
+		}
+		retained[key] = append([]string(nil), values...)
 	}
+	return retained
 }
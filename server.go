@@ -7,14 +7,34 @@ import (
 	"net"
 	"net/http"
 	"sync"
+	"sync/atomic"
+	"time"
+
+	"golang.org/x/sync/semaphore"
 )
 
-const protocolVersion = "1.4"
+const protocolVersion = "1.5"
+
+// ProtocolVersion returns the version of the webwire protocol implemented
+// by this package, which is reported to clients in the metadata
+func ProtocolVersion() string {
+	return protocolVersion
+}
 
 // server represents a headless WebWire server instance,
 // where headless means there's no HTTP server that's hosting it
 type server struct {
-	impl              ServerImplementation
+	// pendingOutboundBytes represents the total number of outbound bytes
+	// pending to be written to all connections
+	// while activeGoroutines represents the number of goroutines
+	// started by spawn, both are accessed atomically and must therefore
+	// remain the first fields to guarantee their 64-bit alignment
+	pendingOutboundBytes int64
+	activeGoroutines     int64
+
+	// impl holds the current implementationRef,
+	// see SetImplementation
+	impl              atomic.Value
 	httpServer        *http.Server
 	listener          net.Listener
 	sessionManager    SessionManager
@@ -33,6 +53,18 @@ type server struct {
 	sessionsEnabled bool
 	sessionRegistry *sessionRegistry
 
+	allowedNames        map[string]struct{}
+	workerSlots         *semaphore.Weighted
+	idempotencyKeys     *idempotencyKeyLocks
+	inFlightRequests    *inFlightRequests
+	requestMetrics      *requestMetrics
+	retainedSignalsLock *sync.RWMutex
+	retainedSignals     map[string]Payload
+	requestTimeoutsLock *sync.RWMutex
+	requestTimeouts     map[string]time.Duration
+	events              chan ServerEvent
+	goroutineSlots      *semaphore.Weighted
+
 	// Internals
 	connUpgrader ConnUpgrader
 	warnLog      *log.Logger
@@ -51,6 +83,10 @@ func (srv *server) shutdownHTTPServer() error {
 
 // Run implements the Server interface
 func (srv *server) Run() error {
+	if srv.httpServer == nil {
+		return HeadlessServerErr{}
+	}
+
 	// Launch HTTP server
 	if err := srv.httpServer.Serve(
 		tcpKeepAliveListener{srv.listener.(*net.TCPListener)},
@@ -66,26 +102,82 @@ func (srv *server) Addr() net.Addr {
 	return srv.addr
 }
 
+// ProtocolVersion implements the Server interface
+func (srv *server) ProtocolVersion() string {
+	return protocolVersion
+}
+
 // Shutdown implements the Server interface
 func (srv *server) Shutdown() error {
 	srv.opsLock.Lock()
 	srv.shutdown = true
+	srv.opsLock.Unlock()
+
+	// Stop the read loops awaiting the capacity to handle messages
+	srv.connectionsLock.Lock()
+	for _, con := range srv.connections {
+		con.cancelAwait()
+	}
+	srv.connectionsLock.Unlock()
+
 	// Don't block if there's no currently processed operations
-	if srv.currentOps < 1 {
-		srv.opsLock.Unlock()
+	if srv.PendingOps() < 1 {
 		return srv.shutdownHTTPServer()
 	}
-	srv.opsLock.Unlock()
-	<-srv.shutdownRdy
+
+	// Report the draining progress periodically
+	// until all pending operations are finished
+	progressTicker := time.NewTicker(srv.options.ShutdownProgressInterval)
+	defer progressTicker.Stop()
+AWAIT_LOOP:
+	for {
+		select {
+		case <-srv.shutdownRdy:
+			break AWAIT_LOOP
+		case <-progressTicker.C:
+			remaining := srv.PendingOps()
+			srv.warnLog.Printf(
+				"Shutting down, awaiting %d pending operations",
+				remaining,
+			)
+			if srv.options.OnShutdownProgress != nil {
+				srv.options.OnShutdownProgress(remaining)
+			}
+		}
+	}
 
 	return srv.shutdownHTTPServer()
 }
 
+// isShuttingDown returns true if the server is being shut down
+func (srv *server) isShuttingDown() bool {
+	srv.opsLock.Lock()
+	shutdown := srv.shutdown
+	srv.opsLock.Unlock()
+	return shutdown
+}
+
+// PendingOps implements the Server interface
+func (srv *server) PendingOps() uint32 {
+	srv.opsLock.Lock()
+	pendingOps := srv.currentOps
+	srv.opsLock.Unlock()
+	return pendingOps
+}
+
 // ActiveSessionsNum implements the Server interface
 func (srv *server) ActiveSessionsNum() int {
 	return srv.sessionRegistry.activeSessionsNum()
 }
 
+// maxActiveSessionsReached returns true if the number of active sessions
+// reached ServerOptions.MaxActiveSessions
+func (srv *server) maxActiveSessionsReached() bool {
+	return srv.options.MaxActiveSessions > 0 &&
+		uint(srv.sessionRegistry.activeSessionsNum()) >=
+			srv.options.MaxActiveSessions
+}
+
 // SessionConnectionsNum implements the Server interface
 func (srv *server) SessionConnectionsNum(sessionKey string) int {
 	return srv.sessionRegistry.sessionConnectionsNum(sessionKey)
@@ -106,6 +198,72 @@ func (srv *server) SessionConnections(sessionKey string) []Connection {
 	return list
 }
 
+// SignalSession implements the Server interface
+func (srv *server) SignalSession(
+	sessionKey,
+	name string,
+	payload Payload,
+) (int, error) {
+	if err := srv.options.NameValidator(name); err != nil {
+		return 0, err
+	}
+
+	signaled := 0
+	for con := range srv.sessionRegistry.sessionConnections(sessionKey) {
+		if err := con.Signal(name, payload); err != nil {
+			srv.errorLog.Printf(
+				"Couldn't signal connection of session %q: %s",
+				sessionKey,
+				err,
+			)
+			continue
+		}
+		signaled++
+	}
+	return signaled, nil
+}
+
+// IsOnline implements the Server interface
+func (srv *server) IsOnline(sessionKey string) bool {
+	return srv.sessionRegistry.localConnectionsNum(sessionKey) > 0
+}
+
+// ActiveSessionConnections implements the Server interface
+func (srv *server) ActiveSessionConnections(sessionKey string) int {
+	return srv.sessionRegistry.localConnectionsNum(sessionKey)
+}
+
+// SessionRegistryStats implements the Server interface
+func (srv *server) SessionRegistryStats() RegistryStats {
+	return srv.sessionRegistry.stats()
+}
+
+// CloseIdleConnections implements the Server interface
+func (srv *server) CloseIdleConnections(idleFor time.Duration) int {
+	threshold := srv.options.Clock.Now().Add(-idleFor)
+
+	srv.connectionsLock.Lock()
+	idle := make([]*connection, 0)
+	for _, con := range srv.connections {
+		if con.IsActive() && !con.LastActivity().After(threshold) {
+			idle = append(idle, con)
+		}
+	}
+	srv.connectionsLock.Unlock()
+
+	// Close the connections outside the critical section,
+	// the read loop of each of them takes care of the rest
+	for _, con := range idle {
+		con.Close()
+	}
+	return len(idle)
+}
+
+// InFlightRequests implements the Server interface
+func (srv *server) InFlightRequests() []RequestInfo {
+	return srv.inFlightRequests.list()
+}
+
 // CloseSession implements the Server interface
 func (srv *server) CloseSession(sessionKey string) (
 	affectedConnections []Connection,
@@ -142,3 +300,64 @@ func (srv *server) CloseSession(sessionKey string) (
 
 	return affectedConnections, errors, generalError
 }
+
+// CloseSessionsWhere implements the Server interface
+func (srv *server) CloseSessionsWhere(predicate func(SessionInfo) bool) int {
+	closed := 0
+	for _, connections := range srv.sessionRegistry.sessions() {
+		// All connections of a session share the same session info
+		session := connections[0].Session()
+		if session == nil || !predicate(session.Info) {
+			continue
+		}
+
+		// Count the session as closed only if it was closed
+		// on all of its connections
+		failed := false
+		for _, connection := range connections {
+			if err := connection.CloseSession(); err != nil {
+				srv.errorLog.Printf(
+					"Couldn't close session %s on connection of client %v: %s",
+					session.Key,
+					connection.Info().RemoteAddr,
+					err,
+				)
+				failed = true
+			}
+		}
+		if !failed {
+			closed++
+		}
+	}
+	return closed
+}
+
+// resolveEncoding returns the default encoding defined in the server options
+// if the given encoding is EncodingDefault, otherwise returns it as is
+func (srv *server) resolveEncoding(encoding PayloadEncoding) PayloadEncoding {
+	if encoding == EncodingDefault {
+		return srv.options.DefaultEncoding
+	}
+	return encoding
+}
+
+// implementationRef wraps the server implementation to always store
+// values of the same concrete type in the atomic.Value
+type implementationRef struct {
+	ServerImplementation
+}
+
+// implementation returns the current server implementation
+func (srv *server) implementation() ServerImplementation {
+	return srv.impl.Load().(implementationRef).ServerImplementation
+}
+
+// SetImplementation implements the Server interface
+func (srv *server) SetImplementation(implementation ServerImplementation) {
+	if implementation == nil {
+		panic(fmt.Errorf(
+			"server instance requires an implementation, got nil",
+		))
+	}
+	srv.impl.Store(implementationRef{implementation})
+}
@@ -4,6 +4,8 @@ import (
 	"log"
 	"os"
 	"time"
+
+	msg "github.com/qbeon/webwire-go/message"
 )
 
 // OptionValue represents the setting value of an option
@@ -34,6 +36,313 @@ type ServerOptions struct {
 	HeartbeatInterval     time.Duration
 	WarnLog               *log.Logger
 	ErrorLog              *log.Logger
+
+	// ReadTimeout defines the maximum duration the server awaits
+	// the next message, ping or pong frame from a client before
+	// the connection is considered dead and closed with
+	// a DisconnectIdleTimeout reason. The deadline is refreshed after
+	// each successfully read frame. Combined with an enabled heartbeat
+	// it allows reaping dead connections within seconds.
+	// HeartbeatTimeout is used instead if ReadTimeout is 0
+	ReadTimeout time.Duration
+
+	// BeforeUpgradeTimeout defines the maximum duration of the BeforeUpgrade
+	// hook. The hook is passed a request carrying a context with
+	// the according deadline. Connections are refused with a 503
+	// (service unavailable) status code if the hook exceeds it,
+	// in which case any later writes of the hook to the response are
+	// dropped. The duration of the hook isn't limited if it's 0
+	// which is the default
+	BeforeUpgradeTimeout time.Duration
+
+	// ShutdownProgressInterval defines the interval at which the progress
+	// of a graceful shutdown is reported while awaiting pending operations
+	ShutdownProgressInterval time.Duration
+
+	// OnShutdownProgress is invoked at each shutdown progress interval
+	// with the number of operations still pending, it's optional
+	OnShutdownProgress func(remaining uint32)
+
+	// ReplyChunkSize defines the size of the chunks in bytes
+	// streamed replies are split into, it's rounded up to an even number
+	ReplyChunkSize int
+
+	// EnabledMessageTypes defines the set of message types
+	// accepted by the server. Incoming requests of disabled types are
+	// rejected with a FeatureDisabledErr while signals are dropped.
+	// All message types are enabled if the set is nil
+	EnabledMessageTypes map[byte]struct{}
+
+	// Tracer defines the tracer starting a span around each request
+	// and signal handler, defaults to a no-op tracer
+	Tracer Tracer
+
+	// PayloadInterceptor defines the transformation applied to the payloads
+	// of outgoing and incoming messages, defaults to a no-op interceptor
+	PayloadInterceptor PayloadInterceptor
+
+	// SessionRegistryBackend defines the backend keeping track of
+	// the number of concurrent connections of each active session.
+	// It can be shared by multiple server instances to enforce
+	// MaxSessionConnections across all of them,
+	// defaults to an in-memory backend
+	SessionRegistryBackend SessionRegistryBackend
+
+	// MaxActiveSessions defines the maximum number of distinct active
+	// sessions, including those of other servers sharing
+	// the SessionRegistryBackend. Connection.CreateSession and
+	// Connection.CreateEphemeralSession fail with a MaxSessionsReachedErr
+	// when the limit is reached while restorations of sessions without
+	// active connections fail with a ReqErr of code MAX_SESSIONS_REACHED.
+	// Unlimited if 0. The limit is enforced atomically by each server,
+	// concurrent session creations on different servers sharing
+	// the SessionRegistryBackend may still exceed it
+	MaxActiveSessions uint
+
+	// MaxInFlightRequestsPerConn defines the maximum number of requests
+	// concurrently processed for a single connection. Excess requests are
+	// rejected with a TooManyRequestsErr, unlimited if 0
+	MaxInFlightRequestsPerConn uint
+
+	// RequestTimeout defines the default maximum duration of the OnRequest
+	// hook for requests of names without a timeout defined by
+	// Server.SetRequestTimeout. Requests exceeding it fail with
+	// a RequestTimeoutErr and the context of the hook is canceled.
+	// Timed out hooks remain pending operations awaited by Server.Shutdown
+	// until they return. Unlimited if 0
+	RequestTimeout time.Duration
+
+	// MaxSessionOperations defines the maximum number of session operations
+	// performed on a single connection within SessionOperationsInterval.
+	// Session restorations and closures requested by the client as well as
+	// session creations and session info patches performed by handlers
+	// count towards the limit. Excess operations fail
+	// with a SessionOperationThrottledErr. Session closures initiated
+	// by the server through Connection.CloseSession, Server.CloseSession
+	// or Server.CloseSessionsWhere are never throttled. Unlimited if 0
+	MaxSessionOperations uint
+
+	// SessionOperationsInterval defines the interval MaxSessionOperations
+	// applies to, defaults to 1 second
+	SessionOperationsInterval time.Duration
+
+	// WorkerPoolSize defines the maximum number of incoming messages
+	// such as requests and signals handled concurrently across
+	// all connections. When all workers are busy the reading of further
+	// messages is suspended until a worker is freed, which applies
+	// backpressure to the clients. Workers waiting for a free handler slot
+	// of a connection with a limited concurrency remain occupied,
+	// unlimited if 0
+	WorkerPoolSize uint
+
+	// MaxGoroutines defines a soft limit of the number of goroutines
+	// handling incoming messages. While it's reached the reading
+	// of further incoming messages is suspended until one of them finishes,
+	// which applies backpressure to the clients. Goroutines living as long
+	// as a connection (such as the ones sending heartbeats) and goroutines
+	// started while handling messages (such as the ones enforcing
+	// request timeouts) aren't limited, though they're included
+	// in Server.ActiveGoroutines. Unlimited if 0
+	MaxGoroutines uint
+
+	// IdempotencyStore enables the deduplication of requests carrying
+	// an application-level idempotency key (see IdempotentRequestName).
+	// Requests carrying a key already stored are replied to with the stored
+	// reply without invoking the OnRequest hook. Keys are scoped
+	// to the request name and to the session of the client, or to
+	// the idempotency scope sent by the client (see IdempotencyScopeHeader)
+	// if it has no session, thus the stored reply is replayed over
+	// another connection after a reconnect. Keys of clients not sending
+	// an idempotency scope are scoped to the connection.
+	// Concurrent requests carrying the same key are handled one at a time.
+	// Streamed replies are never stored. Idempotency keys are not recognized
+	// if no store is specified
+	IdempotencyStore IdempotencyStore
+
+	// OnSignalError is invoked with the recovered value
+	// when the OnSignal hook panics, it's optional
+	OnSignalError func(
+		conn Connection,
+		message Message,
+		recovered interface{},
+	)
+
+	// ForwardedHeaders defines the names of the headers of the upgrade
+	// request retained by the connection to be accessible through
+	// Connection.Header during its entire lifetime.
+	// Other headers are discarded to avoid retaining the request
+	ForwardedHeaders []string
+
+	// ConnectParams defines the names of the query parameters of the upgrade
+	// request URL retained by the connection to be accessible through
+	// Connection.ConnectParam during its entire lifetime.
+	// Other query parameters are discarded
+	ConnectParams []string
+
+	// CompressionThreshold defines the size in bytes a message
+	// must exceed to be sent compressed using the per-message deflate
+	// WebSocket extension. Smaller messages are sent uncompressed
+	// to avoid wasting CPU time on compressing tiny frames.
+	// Compression isn't negotiated at all if it's 0
+	// and CompressReplies is false
+	CompressionThreshold int
+
+	// CompressReplies enables the compression of outgoing messages
+	// such as replies and signals for clients advertising support for
+	// the per-message deflate WebSocket extension during the upgrade.
+	// The compression of incoming messages is decided by the client
+	// independently, the webwire client doesn't compress requests.
+	// All outgoing messages are compressed unless a CompressionThreshold
+	// is defined
+	CompressReplies bool
+
+	// MaxMessageSize defines the maximum size in bytes of incoming
+	// messages. Connections of clients sending bigger messages are closed
+	// with the 1009 (message too big) close code.
+	// Message sizes are unlimited if it's 0.
+	// The maximum message size is announced to clients
+	// in the endpoint metadata
+	MaxMessageSize int64
+
+	// MaxSignalSize defines the maximum size in bytes of the payload
+	// of incoming signals. Bigger signals are dropped with a warning
+	// without closing the connection, which allows for limiting signals
+	// more strictly than requests. Signal sizes are only limited
+	// by MaxMessageSize if it's 0
+	MaxSignalSize int
+
+	// DefaultEncoding defines the encoding of replies and signals
+	// sent with an EncodingDefault encoded payload and of replies
+	// without a payload, defaults to EncodingBinary
+	DefaultEncoding PayloadEncoding
+
+	// OnMessage is invoked for each parsed incoming message
+	// before it's dispatched, including session restoration and closure
+	// requests. Returning an error rejects the message and drops signals.
+	// Requests are failed like by the OnRequest hook: a ReqErr is sent
+	// to the client as is while other errors are replied to
+	// with an internal server error.
+	// It's optional and intended for auditing and gateway use cases
+	OnMessage func(conn Connection, message Message) error
+
+	// MaxPendingOutboundBytes defines the maximum total number of bytes
+	// of outbound messages (replies, signals etc.) pending to be written
+	// to all connections. Messages are pending while they're waiting
+	// to be written or are being written, which takes long for slow clients.
+	// The limit is enforced according to OnOutboundLimitExceeded,
+	// unlimited if 0
+	MaxPendingOutboundBytes int64
+
+	// OnOutboundLimitExceeded defines how MaxPendingOutboundBytes
+	// is enforced. The connections with the most pending outbound bytes
+	// are closed by default (OutboundLimitDisconnectSlowest)
+	OnOutboundLimitExceeded OutboundLimitPolicy
+
+	// OnUnknownMessageType defines how messages of unknown types
+	// are treated, which may be sent by clients of newer protocol versions.
+	// Such messages are silently dropped by default
+	// (UnknownMessageTypeIgnore)
+	OnUnknownMessageType UnknownMessageTypePolicy
+
+	// ClassifyCloseCode decides how the closure of a client connection
+	// with the given WebSocket close code is logged, which allows
+	// close codes expected in high-churn environments to be suppressed
+	// while still surfacing abnormal ones.
+	// Defaults to DefaultClassifyCloseCode
+	ClassifyCloseCode func(code int) CloseLogLevel
+
+	// CriticalSignal decides whether the signal of the given name
+	// is critical and must thus be sent even while the client has signals
+	// paused. All signals are considered non-critical if it's undefined
+	CriticalSignal func(name string) bool
+
+	// PreAuthRequest decides whether the request of the given name
+	// is allowed to be sent by clients without a session, such as a login.
+	// If defined then all other requests of clients without a session
+	// are rejected with an UnauthorizedErr before the OnRequest hook
+	// is invoked. Session restoration requests are always allowed.
+	// All requests are allowed if it's undefined
+	PreAuthRequest func(name string) bool
+
+	// AllowedNames defines the names of the requests and signals accepted
+	// by the server. Requests of any other name are rejected with
+	// a MethodNotFoundErr and signals of any other name are dropped
+	// right after parsing, before any hook is invoked.
+	// Idempotency keys are not considered part of the name.
+	// All names are allowed if it's nil
+	AllowedNames []string
+
+	// RequestVersions defines the payload schema versions supported
+	// by the server per request name. The supported versions are published
+	// through the endpoint metadata for clients to agree on a version
+	// of each request during the connection establishment.
+	// Request handlers retrieve the agreed version using RequestVersion
+	RequestVersions map[string][]int
+
+	// CompressSessionInfo enables holding the info of active sessions
+	// as gzip compressed JSON in memory. Each access to the session info
+	// decompresses and parses it using the SessionInfoParser, trading
+	// CPU time on every access for memory, which only pays off for
+	// large and rarely accessed session info (compare
+	// BenchmarkCompressedSessionInfoValue and BenchmarkSessionInfoValue).
+	// Session info objects are thus always subject to JSON encoding and
+	// no longer of the type originally attached to the session.
+	// Disabled by default
+	CompressSessionInfo bool
+
+	// BinarySessionEncoding enables sending session objects to clients
+	// as binary rather than UTF8 encoded JSON, both in session creation
+	// notifications and in replies to session restoration requests.
+	// Defaults to Disabled, which makes clients always receive
+	// session objects as UTF8. UTF8 encoded session creation notifications
+	// were introduced in protocol version 1.5
+	BinarySessionEncoding OptionValue
+
+	// Clock defines the source of the current time used for the creation
+	// time of sessions and connections, the last activity
+	// of connections, the start time and duration of in-flight requests
+	// and the time of server events, which allows tests to control
+	// these timestamps deterministically.
+	// It's only used for timestamps and never for timers: request timeouts,
+	// the ReliableSignal acknowledgement timeout, the expiry of entries
+	// of the default IdempotencyStore, network deadlines such as
+	// the read timeout and the heartbeat always use the system clock.
+	// A CircuitBreaker accepts its own clock (see NewCircuitBreaker).
+	// Defaults to the system clock
+	Clock Clock
+
+	// SignalAckTimeout defines the duration Connection.ReliableSignal awaits
+	// the acknowledgement of a reliable signal before it's sent again.
+	// Defaults to 5 seconds
+	SignalAckTimeout time.Duration
+
+	// ReliableSignalAttempts defines the maximum number of times
+	// Connection.ReliableSignal sends an unacknowledged reliable signal
+	// before it gives up. Defaults to 3
+	ReliableSignalAttempts uint
+
+	// NameValidator defines the validator verifying the names
+	// of signals sent to clients.
+	// If undefined then msg.ValidateNameASCII is applied allowing
+	// printable 7-bit ASCII characters only
+	NameValidator msg.NameValidator
+
+	// MaxNameLength defines the maximum length in bytes of the names
+	// of requests and signals. Longer names of incoming requests
+	// are rejected with a ProtocolErr while incoming signals
+	// of longer names are dropped. The NameValidator is wrapped
+	// to also reject longer names of outgoing signals.
+	// Idempotency keys carried by request names (see IdempotencyStore)
+	// don't count towards the length.
+	// Defaults to and can't exceed 255, the maximum length
+	// of names supported by the protocol
+	MaxNameLength int
+
+	// EventBufferSize defines the capacity of the buffer of the event stream
+	// returned by Server.Events. Events emitted while the buffer is full
+	// are dropped to prevent a slow consumer from stalling the server.
+	// Defaults to 256
+	EventBufferSize uint
 }
 
 // SetDefaults sets the defaults for undefined required values
@@ -53,6 +362,11 @@ func (srvOpt *ServerOptions) SetDefaults() {
 		srvOpt.SessionKeyGenerator = NewDefaultSessionKeyGenerator()
 	}
 
+	// Encode session objects as UTF8 by default
+	if srvOpt.BinarySessionEncoding == OptionUnset {
+		srvOpt.BinarySessionEncoding = Disabled
+	}
+
 	if srvOpt.SessionInfoParser == nil {
 		srvOpt.SessionInfoParser = GenericSessionInfoParser
 	}
@@ -74,6 +388,78 @@ func (srvOpt *ServerOptions) SetDefaults() {
 		srvOpt.HeartbeatInterval = 30 * time.Second
 	}
 
+	// Use a default 1 second shutdown progress interval
+	// if the specified interval is below 10 milliseconds
+	if srvOpt.ShutdownProgressInterval < 10*time.Millisecond {
+		srvOpt.ShutdownProgressInterval = 1 * time.Second
+	}
+
+	// Fall back to binary encoding if no default encoding is specified
+	if srvOpt.DefaultEncoding == EncodingDefault {
+		srvOpt.DefaultEncoding = EncodingBinary
+	}
+
+	// Use a default 64 KiB reply chunk size if none is specified
+	if srvOpt.ReplyChunkSize < 1 {
+		srvOpt.ReplyChunkSize = 64 * 1024
+	}
+
+	// Keep UTF16 encoded chunks aligned
+	if srvOpt.ReplyChunkSize%2 != 0 {
+		srvOpt.ReplyChunkSize++
+	}
+
+	if srvOpt.Tracer == nil {
+		srvOpt.Tracer = nopTracer{}
+	}
+
+	if srvOpt.PayloadInterceptor == nil {
+		srvOpt.PayloadInterceptor = nopPayloadInterceptor{}
+	}
+
+	if srvOpt.NameValidator == nil {
+		srvOpt.NameValidator = msg.ValidateNameASCII
+	}
+
+	if srvOpt.EventBufferSize < 1 {
+		srvOpt.EventBufferSize = 256
+	}
+
+	// Names can't exceed 255 bytes due to their single byte length flag
+	if srvOpt.MaxNameLength < 1 || srvOpt.MaxNameLength > 255 {
+		srvOpt.MaxNameLength = 255
+	} else {
+		srvOpt.NameValidator = msg.LimitNameLength(
+			srvOpt.MaxNameLength,
+			srvOpt.NameValidator,
+		)
+	}
+
+	// Use the system clock if no clock is specified
+	if srvOpt.Clock == nil {
+		srvOpt.Clock = NewRealClock()
+	}
+
+	// Use a default 5 seconds reliable signal acknowledgement timeout
+	// if none is specified
+	if srvOpt.SignalAckTimeout < 1 {
+		srvOpt.SignalAckTimeout = 5 * time.Second
+	}
+
+	// Send reliable signals up to 3 times by default
+	if srvOpt.ReliableSignalAttempts < 1 {
+		srvOpt.ReliableSignalAttempts = 3
+	}
+
+	// Limit session operations per second by default
+	if srvOpt.SessionOperationsInterval < 1 {
+		srvOpt.SessionOperationsInterval = 1 * time.Second
+	}
+
+	if srvOpt.ClassifyCloseCode == nil {
+		srvOpt.ClassifyCloseCode = DefaultClassifyCloseCode
+	}
+
 	// Create default loggers to std-out/err when no loggers are specified
 	if srvOpt.WarnLog == nil {
 		srvOpt.WarnLog = log.New(
@@ -5,6 +5,7 @@ import (
 	"encoding/base64"
 	"fmt"
 	"time"
+	"unicode/utf8"
 )
 
 // generateRandomBytes returns securely generated random bytes.
@@ -35,6 +36,19 @@ func generateSessionKey() string {
 	return base64.URLEncoding.EncodeToString(bytes)
 }
 
+// verifyGeneratedSessionKey returns an error if the given session key
+// returned by a session key generator isn't valid UTF-8,
+// since such a key couldn't be JSON encoded without altering it
+func verifyGeneratedSessionKey(key string) error {
+	if !utf8.ValidString(key) {
+		return fmt.Errorf(
+			"Invalid session key returned by the session key generator " +
+				"(invalid UTF-8)",
+		)
+	}
+	return nil
+}
+
 // JSONEncodedSession represents a JSON encoded session object.
 // This structure is used during session restoration for unmarshalling
 // TODO: move to internal shared package
@@ -53,6 +67,11 @@ type Session struct {
 	Creation   time.Time
 	LastLookup time.Time
 	Info       SessionInfo
+
+	// Ephemeral is true for server-side only sessions created by
+	// Connection.CreateEphemeralSession which are neither synchronized
+	// to the client nor passed to the session manager
+	Ephemeral bool
 }
 
 // Clone returns an exact copy of the session object
@@ -71,24 +90,33 @@ func (s *Session) Clone() *Session {
 		Creation:   s.Creation,
 		LastLookup: s.LastLookup,
 		Info:       info,
+		Ephemeral:  s.Ephemeral,
 	}
 }
 
 // NewSession generates a new session object
 // generating a cryptographically random secure key
 func NewSession(info SessionInfo, generator func() string) Session {
+	return newSession(info, generator, time.Now())
+}
+
+// newSession generates a new session object created at the given time
+func newSession(
+	info SessionInfo,
+	generator func() string,
+	timeNow time.Time,
+) Session {
 	key := generator()
 	if len(key) < 1 {
 		panic(fmt.Errorf(
 			"Invalid session key returned by the session key generator (empty)",
 		))
 	}
-	timeNow := time.Now()
 	return Session{
-		key,
-		timeNow,
-		timeNow,
-		info,
+		Key:        key,
+		Creation:   timeNow,
+		LastLookup: timeNow,
+		Info:       info,
 	}
 }
 
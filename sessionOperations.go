@@ -0,0 +1,28 @@
+package webwire
+
+// allowSessionOperation returns true if another session operation
+// may be performed on the connection within the current rate limiting
+// window, otherwise returns false, see ServerOptions.MaxSessionOperations
+func (con *connection) allowSessionOperation() bool {
+	max := con.srv.options.MaxSessionOperations
+	if max < 1 {
+		return true
+	}
+
+	con.sessionOpsLock.Lock()
+	defer con.sessionOpsLock.Unlock()
+
+	// Start a new window if the current one elapsed
+	now := con.now()
+	interval := con.srv.options.SessionOperationsInterval
+	if now.Sub(con.sessionOpsWindow) >= interval {
+		con.sessionOpsWindow = now
+		con.sessionOps = 0
+	}
+
+	if con.sessionOps >= max {
+		return false
+	}
+	con.sessionOps++
+	return true
+}
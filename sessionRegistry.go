@@ -6,48 +6,112 @@ import (
 )
 
 // sessionRegistry represents a thread safe registry
-// of all currently active sessions
+// of all currently active sessions.
+// It keeps track of the connections of each session locally while the
+// number of connections of each session is tracked by the registry backend
 type sessionRegistry struct {
-	lock     sync.RWMutex
-	maxConns uint
-	registry map[string]map[*connection]struct{}
+	lock        sync.RWMutex
+	maxConns    uint
+	maxSessions uint
+	registry    map[string]map[*connection]struct{}
+	backend     SessionRegistryBackend
+
+	// peakConns represents the highest number of local connections
+	// a single session ever had concurrently
+	peakConns int
+}
+
+// RegistryStats represents a snapshot of the state
+// of the local session registry of a server
+type RegistryStats struct {
+	// ActiveSessions is the number of sessions
+	// with at least one connection to the server
+	ActiveSessions int
+
+	// TotalConnections is the number of session connections
+	// to the server across all sessions
+	TotalConnections int
+
+	// MaxConnections is the maximum number of concurrent connections
+	// of a single session (ServerOptions.MaxSessionConnections),
+	// 0 stands for unlimited
+	MaxConnections uint
+
+	// PeakSessionConnections is the high-water mark of the number
+	// of concurrent connections of a single session to the server
+	PeakSessionConnections int
+}
+
+// PeakUtilization returns the ratio of the high-water mark
+// of concurrent session connections to the maximum number
+// of concurrent connections of a single session.
+// Returns 0 if the number of connections is unlimited
+func (stats RegistryStats) PeakUtilization() float64 {
+	if stats.MaxConnections < 1 {
+		return 0
+	}
+	return float64(stats.PeakSessionConnections) /
+		float64(stats.MaxConnections)
 }
 
 // newSessionRegistry returns a new instance of a session registry.
 // maxConns defines the maximum number of concurrent connections
-// for a single session while zero stands for unlimited
-func newSessionRegistry(maxConns uint) *sessionRegistry {
+// for a single session and maxSessions the maximum number of active
+// sessions while zero stands for unlimited.
+// If no backend is given then an in-memory backend is used
+func newSessionRegistry(
+	maxConns uint,
+	maxSessions uint,
+	backend SessionRegistryBackend,
+) *sessionRegistry {
+	if backend == nil {
+		backend = NewInMemSessionRegistryBackend()
+	}
 	return &sessionRegistry{
-		lock:     sync.RWMutex{},
-		maxConns: maxConns,
-		registry: make(map[string]map[*connection]struct{}),
+		lock:        sync.RWMutex{},
+		maxConns:    maxConns,
+		maxSessions: maxSessions,
+		registry:    make(map[string]map[*connection]struct{}),
+		backend:     backend,
 	}
 }
 
 // register registers a new connection for the given clients session.
 // Returns an error if the given clients session already reached
-// the maximum number of concurrent connections
+// the maximum number of concurrent connections or a MaxSessionsReachedErr
+// if the session isn't active yet and the maximum number
+// of active sessions is reached
 func (asr *sessionRegistry) register(con *connection) error {
 	asr.lock.Lock()
 	defer asr.lock.Unlock()
+
+	// The limit is checked under the registry lock
+	// for concurrent registrations not to exceed it
+	if asr.maxSessions > 0 &&
+		asr.backend.SessionConnectionsNum(con.session.Key) < 0 &&
+		uint(asr.backend.ActiveSessionsNum()) >= asr.maxSessions {
+		return MaxSessionsReachedErr{}
+	}
+
+	if err := asr.backend.Register(con.session.Key, asr.maxConns); err != nil {
+		return err
+	}
 	if connSet, exists := asr.registry[con.session.Key]; exists {
-		// Ensure max connections isn't exceeded
-		if asr.maxConns > 0 && uint(len(connSet)+1) > asr.maxConns {
-			return fmt.Errorf(
-				"Max conns (%d) reached for session %s",
-				asr.maxConns,
-				con.session.Key,
-			)
-		}
 		// Overwrite the current entry incrementing the number of connections
 		connSet[con] = struct{}{}
 		asr.registry[con.session.Key] = connSet
+		if len(connSet) > asr.peakConns {
+			asr.peakConns = len(connSet)
+		}
 		return nil
 	}
 	newList := map[*connection]struct{}{
 		con: {},
 	}
 	asr.registry[con.session.Key] = newList
+	if asr.peakConns < 1 {
+		asr.peakConns = 1
+	}
 	return nil
 }
 
@@ -63,49 +127,165 @@ func (asr *sessionRegistry) deregister(conn *connection) int {
 
 	asr.lock.Lock()
 	defer asr.lock.Unlock()
-	if connSet, exists := asr.registry[conn.session.Key]; exists {
-		// If a single connection is left then remove the session
-		if len(connSet) < 2 {
-			delete(asr.registry, conn.session.Key)
-			return 0
-		}
+	connSet, exists := asr.registry[conn.session.Key]
+	if !exists {
+		return -1
+	}
+
+	// Don't touch the connection counts if the connection was already
+	// deregistered, which happens when a closure races a restoration
+	if _, registered := connSet[conn]; !registered {
+		return -1
+	}
 
-		// Find and remove the client from the connections list
+	// If a single connection is left then remove the session
+	if len(connSet) < 2 {
+		delete(asr.registry, conn.session.Key)
+	} else {
+		// Remove the client from the connections list
 		delete(connSet, conn)
-		return len(connSet)
 	}
-	return -1
+	return asr.backend.Deregister(conn.session.Key)
 }
 
 // activeSessionsNum returns the number of currently active sessions
 func (asr *sessionRegistry) activeSessionsNum() int {
-	asr.lock.RLock()
-	registryLen := len(asr.registry)
-	asr.lock.RUnlock()
-	return registryLen
+	return asr.backend.ActiveSessionsNum()
 }
 
 // sessionConnectionsNum implements the sessionRegistry interface
 func (asr *sessionRegistry) sessionConnectionsNum(sessionKey string) int {
-	asr.lock.RLock()
-	if connSet, exists := asr.registry[sessionKey]; exists {
-		connSetLen := len(connSet)
-		asr.lock.RUnlock()
-		return connSetLen
-	}
-	asr.lock.RUnlock()
-	return -1
+	return asr.backend.SessionConnectionsNum(sessionKey)
 }
 
-// sessionConnections implements the sessionRegistry interface
+// sessionConnections returns a copy of the set of connections
+// of the given session to this server
+// or nil if the session has no connections
 func (asr *sessionRegistry) sessionConnections(
 	sessionKey string,
 ) map[*connection]struct{} {
 	asr.lock.RLock()
-	if connSet, exists := asr.registry[sessionKey]; exists {
-		asr.lock.RUnlock()
-		return connSet
+	defer asr.lock.RUnlock()
+	connSet, exists := asr.registry[sessionKey]
+	if !exists {
+		return nil
 	}
-	asr.lock.RUnlock()
+	connections := make(map[*connection]struct{}, len(connSet))
+	for con := range connSet {
+		connections[con] = struct{}{}
+	}
+	return connections
+}
+
+// sessions returns a copy of the connections of each session
+// with connections to this server indexed by session key
+func (asr *sessionRegistry) sessions() map[string][]*connection {
+	asr.lock.RLock()
+	defer asr.lock.RUnlock()
+	sessions := make(map[string][]*connection, len(asr.registry))
+	for sessionKey, connSet := range asr.registry {
+		connections := make([]*connection, 0, len(connSet))
+		for con := range connSet {
+			connections = append(connections, con)
+		}
+		sessions[sessionKey] = connections
+	}
+	return sessions
+}
+
+// localConnectionsNum returns the number of connections
+// of the given session to this server
+func (asr *sessionRegistry) localConnectionsNum(sessionKey string) int {
+	asr.lock.RLock()
+	defer asr.lock.RUnlock()
+	return len(asr.registry[sessionKey])
+}
+
+// stats returns a snapshot of the local state of the registry
+func (asr *sessionRegistry) stats() RegistryStats {
+	asr.lock.RLock()
+	defer asr.lock.RUnlock()
+	totalConns := 0
+	for _, connSet := range asr.registry {
+		totalConns += len(connSet)
+	}
+	return RegistryStats{
+		ActiveSessions:         len(asr.registry),
+		TotalConnections:       totalConns,
+		MaxConnections:         asr.maxConns,
+		PeakSessionConnections: asr.peakConns,
+	}
+}
+
+// memSessionRegistryBackend represents the default in-memory implementation
+// of the SessionRegistryBackend interface
+type memSessionRegistryBackend struct {
+	lock   sync.RWMutex
+	counts map[string]uint
+}
+
+// NewInMemSessionRegistryBackend returns a new in-memory session registry
+// backend instance, which can be shared by multiple server instances
+// running in the same process
+func NewInMemSessionRegistryBackend() SessionRegistryBackend {
+	return &memSessionRegistryBackend{
+		lock:   sync.RWMutex{},
+		counts: make(map[string]uint),
+	}
+}
+
+// Register implements the SessionRegistryBackend interface
+func (bck *memSessionRegistryBackend) Register(
+	sessionKey string,
+	maxConns uint,
+) error {
+	bck.lock.Lock()
+	defer bck.lock.Unlock()
+	count := bck.counts[sessionKey]
+
+	// Ensure max connections isn't exceeded
+	if maxConns > 0 && count+1 > maxConns {
+		return fmt.Errorf(
+			"Max conns (%d) reached for session %s",
+			maxConns,
+			sessionKey,
+		)
+	}
+	bck.counts[sessionKey] = count + 1
 	return nil
 }
+
+// Deregister implements the SessionRegistryBackend interface
+func (bck *memSessionRegistryBackend) Deregister(sessionKey string) int {
+	bck.lock.Lock()
+	defer bck.lock.Unlock()
+	count, exists := bck.counts[sessionKey]
+	if !exists {
+		return -1
+	}
+	if count < 2 {
+		delete(bck.counts, sessionKey)
+		return 0
+	}
+	bck.counts[sessionKey] = count - 1
+	return int(count - 1)
+}
+
+// SessionConnectionsNum implements the SessionRegistryBackend interface
+func (bck *memSessionRegistryBackend) SessionConnectionsNum(
+	sessionKey string,
+) int {
+	bck.lock.RLock()
+	defer bck.lock.RUnlock()
+	if count, exists := bck.counts[sessionKey]; exists {
+		return int(count)
+	}
+	return -1
+}
+
+// ActiveSessionsNum implements the SessionRegistryBackend interface
+func (bck *memSessionRegistryBackend) ActiveSessionsNum() int {
+	bck.lock.RLock()
+	defer bck.lock.RUnlock()
+	return len(bck.counts)
+}
@@ -1,6 +1,7 @@
 package webwire
 
 import (
+	"fmt"
 	"sync"
 	"sync/atomic"
 	"testing"
@@ -12,7 +13,7 @@ import (
 
 // TestSessRegRegistration tests registration
 func TestSessRegRegistration(t *testing.T) {
-	reg := newSessionRegistry(0)
+	reg := newSessionRegistry(0, 0, nil)
 
 	// Register connection with session
 	clt := newConnection(nil, "", nil, nil)
@@ -31,7 +32,7 @@ func TestSessRegRegistration(t *testing.T) {
 // TestSessRegActiveSessionsNum tests the ActiveSessionsNum method
 func TestSessRegActiveSessionsNum(t *testing.T) {
 	expectedSessionsNum := 2
-	reg := newSessionRegistry(0)
+	reg := newSessionRegistry(0, 0, nil)
 
 	// Register 2 connections on two separate sessions
 	cltA1 := newConnection(nil, "", nil, nil)
@@ -56,7 +57,7 @@ func TestSessRegActiveSessionsNum(t *testing.T) {
 // TestSessRegsessionConnectionsNum tests the sessionConnectionsNum method
 func TestSessRegsessionConnectionsNum(t *testing.T) {
 	expectedSessionsNum := 1
-	reg := newSessionRegistry(0)
+	reg := newSessionRegistry(0, 0, nil)
 
 	// Register first connection on session A
 	cltA1 := newConnection(nil, "", nil, nil)
@@ -83,7 +84,7 @@ func TestSessRegsessionConnectionsNum(t *testing.T) {
 // when the maximum number of concurrent connections of a session was reached
 func TestSessRegSessionMaxConns(t *testing.T) {
 	// Set the maximum number of concurrent session connection to 1
-	reg := newSessionRegistry(1)
+	reg := newSessionRegistry(1, 0, nil)
 
 	// Register first connection on session A
 	cltA1 := newConnection(nil, "", nil, nil)
@@ -106,7 +107,7 @@ func TestSessRegSessionMaxConns(t *testing.T) {
 
 // TestSessRegDeregistration tests deregistration
 func TestSessRegDeregistration(t *testing.T) {
-	reg := newSessionRegistry(0)
+	reg := newSessionRegistry(0, 0, nil)
 
 	// Register 2 connections on two separate sessions
 	cltA1 := newConnection(nil, "", nil, nil)
@@ -151,7 +152,7 @@ func TestSessRegDeregistration(t *testing.T) {
 // TestSessRegDeregistrationMultiple tests deregistration of multiple
 // connections of a single session
 func TestSessRegDeregistrationMultiple(t *testing.T) {
-	reg := newSessionRegistry(0)
+	reg := newSessionRegistry(0, 0, nil)
 
 	// Register 2 connections on the same session
 	cltA1 := newConnection(nil, "", nil, nil)
@@ -190,9 +191,88 @@ func TestSessRegDeregistrationMultiple(t *testing.T) {
 	require.Equal(t, -1, reg.sessionConnectionsNum("testkey_A"))
 }
 
+// TestSessRegDeregistrationRepeated tests whether repeatedly deregistering
+// the same connection doesn't affect the other connections of the session
+func TestSessRegDeregistrationRepeated(t *testing.T) {
+	reg := newSessionRegistry(0, 0, nil)
+
+	// Register 2 connections on the same session
+	cltA1 := newConnection(nil, "", nil, nil)
+	sessA1 := NewSession(nil, func() string { return "testkey_A" })
+	cltA1.session = &sessA1
+
+	cltA2 := newConnection(nil, "", nil, nil)
+	cltA2.session = &sessA1
+
+	require.NoError(t, reg.register(cltA1))
+	require.NoError(t, reg.register(cltA2))
+
+	// Deregister the first connection twice, expect -1 the second time
+	require.Equal(t, 1, reg.deregister(cltA1))
+	require.Equal(t, -1, reg.deregister(cltA1))
+
+	// Expect the second connection to remain registered
+	require.Equal(t, 1, reg.activeSessionsNum())
+	require.Equal(t, 1, reg.sessionConnectionsNum("testkey_A"))
+	require.Equal(t, 1, reg.localConnectionsNum("testkey_A"))
+}
+
+// TestSessRegMaxSessions tests whether the registration of connections
+// of new sessions fails with a MaxSessionsReachedErr
+// when the maximum number of active sessions is reached
+// while further connections of active sessions are registered
+func TestSessRegMaxSessions(t *testing.T) {
+	reg := newSessionRegistry(0, 1, nil)
+
+	cltA1 := newConnection(nil, "", nil, nil)
+	sessA := NewSession(nil, func() string { return "testkey_A" })
+	cltA1.session = &sessA
+
+	cltA2 := newConnection(nil, "", nil, nil)
+	cltA2.session = &sessA
+
+	cltB1 := newConnection(nil, "", nil, nil)
+	sessB := NewSession(nil, func() string { return "testkey_B" })
+	cltB1.session = &sessB
+
+	require.NoError(t, reg.register(cltA1))
+	require.NoError(t, reg.register(cltA2))
+	require.Equal(t, MaxSessionsReachedErr{}, reg.register(cltB1))
+	require.Equal(t, 1, reg.activeSessionsNum())
+	require.Equal(t, -1, reg.sessionConnectionsNum("testkey_B"))
+}
+
+// TestSessRegMaxSessionsConcurrent tests whether concurrent registrations
+// of new sessions don't exceed the maximum number of active sessions
+func TestSessRegMaxSessionsConcurrent(t *testing.T) {
+	maxSessions := 4
+	reg := newSessionRegistry(0, uint(maxSessions), nil)
+	registered := int32(0)
+	var awaitRegistration sync.WaitGroup
+
+	for i := 0; i < 16; i++ {
+		clt := newConnection(nil, "", nil, nil)
+		key := fmt.Sprintf("testkey_%d", i)
+		sess := NewSession(nil, func() string { return key })
+		clt.session = &sess
+
+		awaitRegistration.Add(1)
+		go func() {
+			defer awaitRegistration.Done()
+			if reg.register(clt) == nil {
+				atomic.AddInt32(&registered, 1)
+			}
+		}()
+	}
+	awaitRegistration.Wait()
+
+	require.Equal(t, int32(maxSessions), registered)
+	require.Equal(t, maxSessions, reg.activeSessionsNum())
+}
+
 // TestSessRegConcurrentAccess tests concurrent (de)registration
 func TestSessRegConcurrentAccess(t *testing.T) {
-	reg := newSessionRegistry(0)
+	reg := newSessionRegistry(0, 0, nil)
 	connsToRegister := uint(16)
 	registeredConns := make([]*connection, connsToRegister)
 	var awaitRegistration sync.WaitGroup
@@ -259,7 +339,7 @@ func TestSessRegConcurrentAccess(t *testing.T) {
 // TestSessRegSessionConnections tests the sessionConnections method
 func TestSessRegSessionConnections(t *testing.T) {
 	expectedSessionsNum := 1
-	reg := newSessionRegistry(0)
+	reg := newSessionRegistry(0, 0, nil)
 
 	// Register first connection on session A
 	cltA1 := newConnection(nil, "A1", nil, nil)
@@ -284,3 +364,80 @@ func TestSessRegSessionConnections(t *testing.T) {
 	require.Contains(t, list, cltA1)
 	require.Contains(t, list, cltA2)
 }
+
+// TestSessRegLocalConnectionsNum tests the localConnectionsNum method
+func TestSessRegLocalConnectionsNum(t *testing.T) {
+	backend := NewInMemSessionRegistryBackend()
+	reg := newSessionRegistry(0, 0, backend)
+
+	// Register a connection of session A to another registry
+	// sharing the same backend
+	otherClt := newConnection(nil, "", nil, nil)
+	sessA := NewSession(nil, func() string { return "testkey_A" })
+	otherClt.session = &sessA
+	require.NoError(t, newSessionRegistry(0, 0, backend).register(otherClt))
+
+	// Expect no local connections
+	require.Equal(t, 0, reg.localConnectionsNum("testkey_A"))
+	require.Equal(t, 1, reg.sessionConnectionsNum("testkey_A"))
+
+	// Register 2 local connections
+	cltA1 := newConnection(nil, "", nil, nil)
+	cltA1.session = &sessA
+	cltA2 := newConnection(nil, "", nil, nil)
+	cltA2.session = &sessA
+	require.NoError(t, reg.register(cltA1))
+	require.NoError(t, reg.register(cltA2))
+
+	require.Equal(t, 2, reg.localConnectionsNum("testkey_A"))
+	require.Equal(t, 3, reg.sessionConnectionsNum("testkey_A"))
+
+	// Expect 1 local connection to be left after deregistration
+	reg.deregister(cltA1)
+	require.Equal(t, 1, reg.localConnectionsNum("testkey_A"))
+	require.Equal(t, 0, reg.localConnectionsNum("testkey_B"))
+}
+
+// TestSessRegStats tests the stats of the session registry
+func TestSessRegStats(t *testing.T) {
+	reg := newSessionRegistry(4, 0, nil)
+	require.Equal(t, RegistryStats{MaxConnections: 4}, reg.stats())
+	require.Equal(t, float64(0), reg.stats().PeakUtilization())
+
+	// Register 3 connections of session A and 1 of session B
+	sessA := NewSession(nil, func() string { return "testkey_A" })
+	sessB := NewSession(nil, func() string { return "testkey_B" })
+	cltsA := make([]*connection, 3)
+	for i := range cltsA {
+		cltsA[i] = newConnection(nil, "", nil, nil)
+		cltsA[i].session = &sessA
+		require.NoError(t, reg.register(cltsA[i]))
+	}
+	cltB := newConnection(nil, "", nil, nil)
+	cltB.session = &sessB
+	require.NoError(t, reg.register(cltB))
+
+	require.Equal(t, RegistryStats{
+		ActiveSessions:         2,
+		TotalConnections:       4,
+		MaxConnections:         4,
+		PeakSessionConnections: 3,
+	}, reg.stats())
+	require.Equal(t, 0.75, reg.stats().PeakUtilization())
+
+	// Expect the high-water mark to remain after deregistration
+	for _, clt := range cltsA {
+		reg.deregister(clt)
+	}
+	require.Equal(t, RegistryStats{
+		ActiveSessions:         1,
+		TotalConnections:       1,
+		MaxConnections:         4,
+		PeakSessionConnections: 3,
+	}, reg.stats())
+
+	// Expect unlimited registries to report no utilization
+	require.Equal(t, float64(0), RegistryStats{
+		PeakSessionConnections: 3,
+	}.PeakUtilization())
+}
@@ -11,6 +11,18 @@ type SockReadErr interface {
 	// IsAbnormalCloseErr must return true if the error represents
 	// an abnormal closure error
 	IsAbnormalCloseErr() bool
+
+	// DisconnectReason must return the reason the socket was closed for
+	DisconnectReason() DisconnectReason
+}
+
+// SockReadCloseCoder is optionally implemented by SockReadErr errors
+// to provide the WebSocket close code of the closure they represent,
+// see ServerOptions.ClassifyCloseCode
+type SockReadCloseCoder interface {
+	// CloseCode must return the close code the socket was closed with
+	// or 0 if the error doesn't represent a closure
+	CloseCode() int
 }
 
 // Socket defines the abstract socket implementation interface
@@ -50,6 +62,49 @@ type Socket interface {
 	WritePing(data []byte, deadline time.Time) error
 }
 
+// HeaderDialer is optionally implemented by sockets
+// supporting custom headers in the connection upgrade request
+type HeaderDialer interface {
+	// DialHeader must connect the socket to the specified server
+	// sending the given headers along with the upgrade request
+	DialHeader(serverAddr string, header http.Header) error
+}
+
+// UnderlyingConnProvider is optionally implemented by sockets
+// to expose the connection of the underlying websocket implementation,
+// see Connection.UnderlyingConn
+type UnderlyingConnProvider interface {
+	// UnderlyingConn must return the underlying connection
+	UnderlyingConn() interface{}
+}
+
+// OutboundQueueLenProvider is optionally implemented by sockets
+// to expose the number of pending outgoing messages,
+// see Connection.OutboundQueueLen
+type OutboundQueueLenProvider interface {
+	// OutboundQueueLen must return the number of messages either waiting
+	// to be written or currently being written.
+	// It must be safe for concurrent use
+	OutboundQueueLen() int
+}
+
+// Aborter is optionally implemented by sockets to close the connection
+// immediately without waiting for pending writes, which must fail
+type Aborter interface {
+	// Abort must close the underlying network connection
+	// without waiting for pending writes to complete.
+	// It must be safe for concurrent use with Write
+	Abort() error
+}
+
+// CloseCoder is optionally implemented by sockets to tell the client
+// why the server closed the connection, see DisconnectReason.CloseCode
+type CloseCoder interface {
+	// CloseWithCode must send a close frame of the given close code
+	// and text to the client and close the socket
+	CloseWithCode(code int, text string) error
+}
+
 // ConnUpgrader defines the abstract interface
 // of an HTTP to WebSocket connection upgrader
 type ConnUpgrader interface {
@@ -2,10 +2,13 @@ package webwire
 
 import (
 	"fmt"
+	"io"
 	"net"
 	"net/http"
 	"net/url"
+	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/gorilla/websocket"
@@ -14,18 +17,32 @@ import (
 // connUpgrader implements the webwire.ConnUpgrader interface using
 // the gorilla/websocket library
 type connUpgrader struct {
-	gorillaWsUpgrader websocket.Upgrader
+	gorillaWsUpgrader    websocket.Upgrader
+	compressOutbound     bool
+	compressionThreshold int
+	maxMessageSize       int64
 }
 
 // newConnUpgrader constructs a new default HTTP connection upgrader
-// based on gorilla/websocket
-func newConnUpgrader() *connUpgrader {
+// based on gorilla/websocket. Per-message compression is negotiated
+// with clients advertising it if outbound compression is enabled.
+// Incoming messages exceeding the max message size are rejected
+// unless it's 0
+func newConnUpgrader(
+	compressOutbound bool,
+	compressionThreshold int,
+	maxMessageSize int64,
+) *connUpgrader {
 	return &connUpgrader{
 		gorillaWsUpgrader: websocket.Upgrader{
 			CheckOrigin: func(_ *http.Request) bool {
 				return true
 			},
+			EnableCompression: compressOutbound,
 		},
+		compressOutbound:     compressOutbound,
+		compressionThreshold: compressionThreshold,
+		maxMessageSize:       maxMessageSize,
 	}
 }
 
@@ -38,7 +55,14 @@ func (upgrader *connUpgrader) Upgrade(
 	if err != nil {
 		return nil, err
 	}
-	return newConnectedSocket(conn), nil
+	if upgrader.maxMessageSize > 0 {
+		conn.SetReadLimit(upgrader.maxMessageSize)
+	}
+	return newConnectedSocket(
+		conn,
+		upgrader.compressOutbound,
+		upgrader.compressionThreshold,
+	), nil
 }
 
 // sockReadErr implements the webwire.SockReadErr interface using
@@ -55,30 +79,99 @@ func (err sockReadErr) Error() string {
 // IsAbnormalCloseErr implements the webwire.SockReadErr interface
 func (err sockReadErr) IsAbnormalCloseErr() bool {
 	return websocket.IsUnexpectedCloseError(
-		err,
+		err.cause,
+		websocket.CloseNormalClosure,
 		websocket.CloseGoingAway,
 		websocket.CloseAbnormalClosure,
 	)
 }
 
+// CloseCode implements the webwire.SockReadCloseCoder interface
+func (err sockReadErr) CloseCode() int {
+	if closeErr, isCloseErr := err.cause.(*websocket.CloseError); isCloseErr {
+		return closeErr.Code
+	}
+	return 0
+}
+
+// DisconnectReason implements the webwire.SockReadErr interface
+func (err sockReadErr) DisconnectReason() DisconnectReason {
+	if netErr, isNetErr := err.cause.(net.Error); isNetErr && netErr.Timeout() {
+		return DisconnectIdleTimeout
+	}
+	if websocket.IsCloseError(
+		err.cause,
+		websocket.CloseNormalClosure,
+		websocket.CloseGoingAway,
+	) {
+		return DisconnectNormalClose
+	}
+	if _, isCloseErr := err.cause.(*websocket.CloseError); isCloseErr {
+		return DisconnectAbnormalClose
+	}
+	if err.cause == websocket.ErrReadLimit {
+		return DisconnectMessageTooBig
+	}
+	// gorilla/websocket doesn't export the errors it fails reading
+	// malformed frames with, all of them are prefixed though
+	if err.cause != io.ErrUnexpectedEOF &&
+		strings.HasPrefix(err.cause.Error(), "websocket: ") {
+		return DisconnectProtocolError
+	}
+	return DisconnectReadError
+}
+
+// clientDialer is the dialer used by client sockets,
+// it negotiates per-message compression with servers supporting it
+var clientDialer = &websocket.Dialer{
+	Proxy:             http.ProxyFromEnvironment,
+	EnableCompression: true,
+}
+
 // socket implements the webwire.Socket interface using
 // the gorilla/websocket library
 type socket struct {
+	// pendingWrites is the number of messages either waiting for
+	// or currently being written, it must be accessed atomically
+	pendingWrites int32
+
 	connected bool
 	lock      sync.RWMutex
 	conn      *websocket.Conn
+
+	// netConn references the network connection of server-side sockets,
+	// it's never modified after the socket is created and can thus be
+	// accessed without acquiring the lock held by pending writes
+	netConn net.Conn
+
+	// compressOutbound enables the compression of outgoing messages
+	// exceeding the compression threshold. Whether incoming messages
+	// are compressed is decided by the remote peer
+	compressOutbound     bool
+	compressionThreshold int
 }
 
 // newConnectedSocket creates a new gorilla/websocket based socket instance
-func newConnectedSocket(conn *websocket.Conn) Socket {
+// compressing outgoing messages exceeding the given compression threshold
+// if outbound compression is enabled
+func newConnectedSocket(
+	conn *websocket.Conn,
+	compressOutbound bool,
+	compressionThreshold int,
+) Socket {
 	connected := false
+	var netConn net.Conn
 	if conn != nil {
 		connected = true
+		netConn = conn.UnderlyingConn()
 	}
 	return &socket{
-		connected: connected,
-		lock:      sync.RWMutex{},
-		conn:      conn,
+		connected:            connected,
+		lock:                 sync.RWMutex{},
+		conn:                 conn,
+		netConn:              netConn,
+		compressOutbound:     compressOutbound,
+		compressionThreshold: compressionThreshold,
 	}
 }
 
@@ -92,7 +185,15 @@ func NewSocket() Socket {
 }
 
 // Dial implements the webwire.Socket interface
-func (sock *socket) Dial(serverAddr string) (err error) {
+func (sock *socket) Dial(serverAddr string) error {
+	return sock.DialHeader(serverAddr, nil)
+}
+
+// DialHeader implements the webwire.HeaderDialer interface
+func (sock *socket) DialHeader(
+	serverAddr string,
+	header http.Header,
+) (err error) {
 	connURL := url.URL{Scheme: "ws", Host: serverAddr, Path: "/"}
 	sock.lock.Lock()
 	defer sock.lock.Unlock()
@@ -100,16 +201,22 @@ func (sock *socket) Dial(serverAddr string) (err error) {
 		sock.conn.Close()
 		sock.conn = nil
 	}
-	sock.conn, _, err = websocket.DefaultDialer.Dial(connURL.String(), nil)
+	sock.conn, _, err = clientDialer.Dial(connURL.String(), header)
 	if err != nil {
 		return NewDisconnectedErr(fmt.Errorf("Dial failure: %s", err))
 	}
+	// Accept compressed messages from the server but don't compress
+	// outgoing messages, requests are usually too small to benefit from it
+	sock.conn.EnableWriteCompression(false)
 	sock.connected = true
 	return nil
 }
 
 // Write implements the webwire.Socket interface
 func (sock *socket) Write(data []byte) error {
+	atomic.AddInt32(&sock.pendingWrites, 1)
+	defer atomic.AddInt32(&sock.pendingWrites, -1)
+
 	sock.lock.Lock()
 	defer sock.lock.Unlock()
 	if !sock.connected {
@@ -117,9 +224,20 @@ func (sock *socket) Write(data []byte) error {
 			Cause: fmt.Errorf("Can't write to a socket"),
 		}
 	}
+	if sock.compressOutbound {
+		// Compress only messages exceeding the compression threshold
+		sock.conn.EnableWriteCompression(
+			len(data) > sock.compressionThreshold,
+		)
+	}
 	return sock.conn.WriteMessage(websocket.BinaryMessage, data)
 }
 
+// OutboundQueueLen implements the webwire.OutboundQueueLenProvider interface
+func (sock *socket) OutboundQueueLen() int {
+	return int(atomic.LoadInt32(&sock.pendingWrites))
+}
+
 // Read implements the webwire.Socket interface
 func (sock *socket) Read() ([]byte, SockReadErr) {
 	_, message, err := sock.conn.ReadMessage()
@@ -146,6 +264,14 @@ func (sock *socket) RemoteAddr() net.Addr {
 	return sock.conn.RemoteAddr()
 }
 
+// UnderlyingConn implements the webwire.UnderlyingConnProvider interface
+// returning the *websocket.Conn of the gorilla/websocket library
+func (sock *socket) UnderlyingConn() interface{} {
+	sock.lock.RLock()
+	defer sock.lock.RUnlock()
+	return sock.conn
+}
+
 // Close implements the webwire.Socket interface
 func (sock *socket) Close() error {
 	sock.lock.Lock()
@@ -154,6 +280,31 @@ func (sock *socket) Close() error {
 	return sock.conn.Close()
 }
 
+// Abort implements the webwire.Aborter interface
+func (sock *socket) Abort() error {
+	if sock.netConn == nil {
+		return sock.Close()
+	}
+	return sock.netConn.Close()
+}
+
+// CloseWithCode implements the webwire.CloseCoder interface
+func (sock *socket) CloseWithCode(code int, text string) error {
+	err := sock.conn.WriteControl(
+		websocket.CloseMessage,
+		websocket.FormatCloseMessage(code, text),
+		time.Now().Add(time.Second),
+	)
+	// gorilla/websocket sends close frames on protocol errors by itself
+	if err == websocket.ErrCloseSent {
+		err = nil
+	}
+	if closeErr := sock.Close(); err == nil {
+		err = closeErr
+	}
+	return err
+}
+
 // SetReadDeadline implements the webwire.Socket interface
 func (sock *socket) SetReadDeadline(deadline time.Time) error {
 	return sock.conn.SetReadDeadline(deadline)
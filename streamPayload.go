@@ -0,0 +1,77 @@
+package webwire
+
+import (
+	"context"
+	"fmt"
+	"io"
+)
+
+// StreamPayload represents a reply payload that's read from a reader
+// and streamed to the client in chunks instead of being buffered entirely.
+// A StreamPayload can only be returned from the OnRequest hook
+type StreamPayload struct {
+	encoding PayloadEncoding
+	reader   io.Reader
+	producer func(context.Context, io.Writer) error
+}
+
+// NewStreamPayload creates a new streamed reply payload reading its data
+// from the given reader. If the reader also implements io.Closer
+// then it's closed after the reply is streamed
+func NewStreamPayload(
+	encoding PayloadEncoding,
+	reader io.Reader,
+) *StreamPayload {
+	if reader == nil {
+		panic(fmt.Errorf("stream payload requires a reader, got nil"))
+	}
+	return &StreamPayload{
+		encoding: encoding,
+		reader:   reader,
+	}
+}
+
+// NewStreamWriterPayload creates a new streamed reply payload
+// that's written by the given producer. The producer is called in a separate
+// goroutine once the reply starts streaming and each write blocks until
+// the written data is read into a chunk. When the client disconnects
+// the context passed to the producer is canceled and all subsequent writes
+// fail with a DisconnectedErr so the producer can stop promptly.
+// The stream is terminated when the producer returns, a returned error
+// fails the request
+func NewStreamWriterPayload(
+	encoding PayloadEncoding,
+	producer func(ctx context.Context, writer io.Writer) error,
+) *StreamPayload {
+	if producer == nil {
+		panic(fmt.Errorf("stream payload requires a producer, got nil"))
+	}
+	return &StreamPayload{
+		encoding: encoding,
+		producer: producer,
+	}
+}
+
+// Encoding implements the WebWire payload interface
+func (pld *StreamPayload) Encoding() PayloadEncoding {
+	return pld.encoding
+}
+
+// Data implements the WebWire payload interface.
+// It always returns nil because streamed payloads aren't buffered,
+// use Reader instead
+func (pld *StreamPayload) Data() []byte {
+	return nil
+}
+
+// Utf8 implements the WebWire payload interface.
+// It always returns an error because streamed payloads aren't buffered
+func (pld *StreamPayload) Utf8() (string, error) {
+	return "", fmt.Errorf("Streamed payloads can't be converted to UTF8")
+}
+
+// Reader returns the reader the payload data is streamed from,
+// it's nil for payloads created by NewStreamWriterPayload
+func (pld *StreamPayload) Reader() io.Reader {
+	return pld.reader
+}
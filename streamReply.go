@@ -0,0 +1,135 @@
+package webwire
+
+import (
+	"context"
+	"fmt"
+	"io"
+
+	msg "github.com/qbeon/webwire-go/message"
+)
+
+// streamReply reads the given stream payload chunk by chunk sending each
+// chunk to the client and terminates the stream with a regular empty reply.
+// Only a single chunk is buffered at a time and writing blocks
+// until the chunk is sent, so a slow client slows down reading the stream
+func (srv *server) streamReply(
+	con *connection,
+	message *msg.Message,
+	stream *StreamPayload,
+) {
+	// The context of the producer is canceled when either the client
+	// disconnects or the stream ends
+	ctx, cancel := context.WithCancel(con.ctx)
+	defer cancel()
+
+	reader := stream.reader
+	if stream.producer != nil {
+		reader = srv.startStreamProducer(ctx, stream.producer)
+	}
+	if closer, isCloser := reader.(io.Closer); isCloser {
+		defer closer.Close()
+	}
+
+	// Abort pipes when the client disconnects to unblock their writers
+	pipe, isPipe := reader.(*io.PipeReader)
+	if isPipe {
+		srv.spawn(func() {
+			<-ctx.Done()
+			if con.ctx.Err() != nil {
+				pipe.CloseWithError(NewDisconnectedErr(nil))
+			}
+		})
+	}
+
+	encoding := srv.resolveEncoding(stream.encoding)
+	chunk := make([]byte, srv.options.ReplyChunkSize)
+	for {
+		chunkLen, err := io.ReadFull(reader, chunk)
+
+		// Verify the alignment of UTF16 encoded chunks before sending them,
+		// only the last chunk can be misaligned since the chunk size is even
+		if encoding == EncodingUtf16 && chunkLen%2 != 0 {
+			err := fmt.Errorf("Invalid UTF16 streamed reply chunk length")
+			srv.errorLog.Printf(
+				"Couldn't stream reply to request %x of client %v: %s",
+				message.Identifier,
+				con.Info().RemoteAddr,
+				err,
+			)
+			srv.failMsg(con, message, err)
+			return
+		}
+
+		if chunkLen > 0 {
+			// Transform each chunk separately
+			data, err := srv.interceptOutbound(encoding, chunk[:chunkLen])
+			if err != nil {
+				srv.errorLog.Printf(
+					"Couldn't stream reply to request %x of client %v: %s",
+					message.Identifier,
+					con.Info().RemoteAddr,
+					err,
+				)
+				srv.failMsg(con, message, err)
+				return
+			}
+			if err := con.sockWrite(msg.NewReplyChunkMessage(
+				message.Identifier,
+				data,
+			)); err != nil {
+				// Silently drop the stream if the client disconnected
+				if con.sock.IsConnected() {
+					srv.errorLog.Println("Writing failed:", err)
+				}
+				if isPipe {
+					pipe.CloseWithError(NewDisconnectedErr(err))
+				}
+				return
+			}
+		}
+		if err == io.EOF || err == io.ErrUnexpectedEOF {
+			break
+		} else if err != nil {
+			// Silently drop the stream if the client disconnected
+			if con.ctx.Err() != nil {
+				return
+			}
+			srv.errorLog.Printf(
+				"Couldn't read streamed reply to request %x of client %v: %s",
+				message.Identifier,
+				con.Info().RemoteAddr,
+				err,
+			)
+			srv.failMsg(con, message, err)
+			return
+		}
+	}
+
+	// Terminate the stream
+	srv.fulfillMsg(con, message, encoding, nil)
+}
+
+// startStreamProducer calls the given stream producer in a separate
+// goroutine passing it the given context
+// and returns the reader end of the pipe it's writing to
+func (srv *server) startStreamProducer(
+	ctx context.Context,
+	producer func(context.Context, io.Writer) error,
+) io.Reader {
+	reader, writer := io.Pipe()
+	srv.spawn(func() {
+		// Fail the stream if the producer panics
+		defer func() {
+			if recovered := recover(); recovered != nil {
+				srv.errorLog.Printf("Stream producer panicked: %v", recovered)
+				writer.CloseWithError(fmt.Errorf(
+					"stream producer panicked: %v",
+					recovered,
+				))
+			}
+		}()
+
+		writer.CloseWithError(producer(ctx, writer))
+	})
+	return reader
+}
@@ -0,0 +1,64 @@
+package webwire
+
+// Subscribe implements the Connection interface
+func (con *connection) Subscribe(topic string) {
+	con.stateLock.Lock()
+	if con.subscriptions == nil {
+		con.subscriptions = make(map[string]struct{})
+	}
+	con.subscriptions[topic] = struct{}{}
+	con.stateLock.Unlock()
+}
+
+// Unsubscribe implements the Connection interface
+func (con *connection) Unsubscribe(topic string) {
+	con.stateLock.Lock()
+	delete(con.subscriptions, topic)
+	con.stateLock.Unlock()
+}
+
+// IsSubscribed implements the Connection interface
+func (con *connection) IsSubscribed(topic string) bool {
+	con.stateLock.RLock()
+	_, subscribed := con.subscriptions[topic]
+	con.stateLock.RUnlock()
+	return subscribed
+}
+
+// clearSubscriptions removes all subscriptions of the connection
+func (con *connection) clearSubscriptions() {
+	con.stateLock.Lock()
+	con.subscriptions = nil
+	con.stateLock.Unlock()
+}
+
+// Publish implements the Server interface
+func (srv *server) Publish(topic string, payload Payload) (int, error) {
+	if err := srv.options.NameValidator(topic); err != nil {
+		return 0, err
+	}
+
+	srv.connectionsLock.Lock()
+	subscribers := make([]*connection, 0)
+	for _, con := range srv.connections {
+		if con.IsActive() && con.IsSubscribed(topic) {
+			subscribers = append(subscribers, con)
+		}
+	}
+	srv.connectionsLock.Unlock()
+
+	// Signal the subscribers outside the critical section
+	signaled := 0
+	for _, con := range subscribers {
+		if err := con.Signal(topic, payload); err != nil {
+			srv.errorLog.Printf(
+				"Couldn't publish signal to topic %q: %s",
+				topic,
+				err,
+			)
+			continue
+		}
+		signaled++
+	}
+	return signaled, nil
+}
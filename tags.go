@@ -0,0 +1,69 @@
+package webwire
+
+import "sort"
+
+// makeTagSet returns the set of the given tags, nil if there are none
+func makeTagSet(tags []string) map[string]struct{} {
+	if len(tags) < 1 {
+		return nil
+	}
+	set := make(map[string]struct{}, len(tags))
+	for _, tag := range tags {
+		set[tag] = struct{}{}
+	}
+	return set
+}
+
+// Tags implements the Connection interface
+func (con *connection) Tags() []string {
+	if len(con.tags) < 1 {
+		return nil
+	}
+	tags := make([]string, 0, len(con.tags))
+	for tag := range con.tags {
+		tags = append(tags, tag)
+	}
+	sort.Strings(tags)
+	return tags
+}
+
+// HasTag implements the Connection interface
+func (con *connection) HasTag(tag string) bool {
+	_, tagged := con.tags[tag]
+	return tagged
+}
+
+// BroadcastToTag implements the Server interface
+func (srv *server) BroadcastToTag(
+	tag,
+	name string,
+	payload Payload,
+) (int, error) {
+	if err := srv.options.NameValidator(name); err != nil {
+		return 0, err
+	}
+
+	srv.connectionsLock.Lock()
+	tagged := make([]*connection, 0)
+	for _, con := range srv.connections {
+		if con.IsActive() && con.HasTag(tag) {
+			tagged = append(tagged, con)
+		}
+	}
+	srv.connectionsLock.Unlock()
+
+	// Signal the tagged connections outside the critical section
+	signaled := 0
+	for _, con := range tagged {
+		if err := con.Signal(name, payload); err != nil {
+			srv.errorLog.Printf(
+				"Couldn't broadcast signal to tag %q: %s",
+				tag,
+				err,
+			)
+			continue
+		}
+		signaled++
+	}
+	return signaled, nil
+}
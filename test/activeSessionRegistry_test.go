@@ -32,7 +32,7 @@ func TestActiveSessionRegistry(t *testing.T) {
 				}
 
 				// Try to create a new session
-				err := conn.CreateSession(nil)
+				err := conn.CreateSession(context.Background(), nil)
 				assert.NoError(t, err)
 				if err != nil {
 					return nil, err
@@ -0,0 +1,103 @@
+package test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	wwr "github.com/qbeon/webwire-go"
+	wwrclt "github.com/qbeon/webwire-go/client"
+)
+
+// TestAllowedNames tests whether requests and signals of names
+// not allowed are rejected before any handler is invoked
+// and whether idempotency keys are stripped from request names only
+func TestAllowedNames(t *testing.T) {
+	handledSignals := make(chan string, 4)
+
+	// Initialize webwire server
+	server := setupServer(
+		t,
+		&serverImpl{
+			onRequest: func(
+				_ context.Context,
+				_ wwr.Connection,
+				msg wwr.Message,
+			) (wwr.Payload, error) {
+				assert.Equal(t, "known", msg.Name())
+				return nil, nil
+			},
+			onSignal: func(
+				_ context.Context,
+				_ wwr.Connection,
+				msg wwr.Message,
+			) {
+				handledSignals <- msg.Name()
+			},
+		},
+		wwr.ServerOptions{
+			AllowedNames:     []string{"known"},
+			IdempotencyStore: wwr.NewMemoryIdempotencyStore(1 * time.Minute),
+		},
+	)
+
+	// Initialize client
+	client := newCallbackPoweredClient(
+		server.Addr().String(),
+		wwrclt.Options{
+			DefaultRequestTimeout: 2 * time.Second,
+		},
+		callbackPoweredClientHooks{},
+	)
+	defer client.connection.Close()
+	require.NoError(t, client.connection.Connect())
+
+	// Expect requests of unknown names to be rejected
+	_, err := client.connection.Request(context.Background(), "unknown", nil)
+	require.Error(t, err)
+	require.IsType(t, wwr.MethodNotFoundErr{}, err)
+
+	// Expect requests of known names to be handled
+	_, err = client.connection.Request(context.Background(), "known", nil)
+	require.NoError(t, err)
+
+	// Expect requests of known names carrying an idempotency key
+	// to be handled
+	_, err = client.connection.Request(
+		context.Background(),
+		wwr.IdempotentRequestName("known", "key"),
+		nil,
+	)
+	require.NoError(t, err)
+
+	// Expect signals of unknown names to be dropped
+	require.NoError(t, client.connection.Signal(
+		"unknown",
+		wwr.NewPayload(wwr.EncodingBinary, []byte("x")),
+	))
+
+	// Expect signals never to be considered carrying an idempotency key
+	require.NoError(t, client.connection.Signal(
+		wwr.IdempotentRequestName("known", "key"),
+		wwr.NewPayload(wwr.EncodingBinary, []byte("x")),
+	))
+	require.NoError(t, client.connection.Signal(
+		"known",
+		wwr.NewPayload(wwr.EncodingBinary, []byte("x")),
+	))
+
+	select {
+	case name := <-handledSignals:
+		require.Equal(t, "known", name)
+	case <-time.After(1 * time.Second):
+		t.Fatal("signal not handled")
+	}
+	select {
+	case name := <-handledSignals:
+		t.Fatalf("unexpected signal handled: %q", name)
+	case <-time.After(50 * time.Millisecond):
+	}
+}
@@ -113,7 +113,7 @@ func TestAuthentication(t *testing.T) {
 				}
 
 				// Try to create a new session
-				err := conn.CreateSession(sessionInfo)
+				err := conn.CreateSession(context.Background(), sessionInfo)
 				assert.NoError(t, err)
 				if err != nil {
 					return nil, err
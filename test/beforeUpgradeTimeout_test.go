@@ -0,0 +1,67 @@
+package test
+
+import (
+	"net/http"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	wwr "github.com/qbeon/webwire-go"
+)
+
+// TestBeforeUpgradeTimeout tests whether connections are refused
+// with a 503 status code when the BeforeUpgrade hook exceeds the timeout
+// and whether the hook is passed a request carrying the deadline
+func TestBeforeUpgradeTimeout(t *testing.T) {
+	// Initialize webwire server
+	server := setupServer(
+		t,
+		&serverImpl{
+			beforeUpgrade: func(
+				resp http.ResponseWriter,
+				req *http.Request,
+			) wwr.ConnectionOptions {
+				_, hasDeadline := req.Context().Deadline()
+				assert.True(t, hasDeadline)
+
+				if req.URL.Query().Get("slow") == "" {
+					return wwr.AcceptConnection(wwr.UnlimitedConcurrency)
+				}
+
+				// Simulate a slow authentication backend
+				<-req.Context().Done()
+				time.Sleep(50 * time.Millisecond)
+				resp.WriteHeader(http.StatusUnauthorized)
+				return wwr.RefuseConnection("too late")
+			},
+		},
+		wwr.ServerOptions{
+			BeforeUpgradeTimeout: 100 * time.Millisecond,
+		},
+	)
+
+	// Expect the connection to be refused if the hook exceeds the timeout
+	conn, response, err := websocket.DefaultDialer.Dial(
+		(&url.URL{
+			Scheme:   "ws",
+			Host:     server.Addr().String(),
+			RawQuery: "slow=1",
+		}).String(),
+		nil,
+	)
+	require.Error(t, err)
+	require.Nil(t, conn)
+	require.Equal(t, http.StatusServiceUnavailable, response.StatusCode)
+
+	// Expect the connection to be accepted if the hook returns in time
+	conn, _, err = websocket.DefaultDialer.Dial(
+		(&url.URL{Scheme: "ws", Host: server.Addr().String()}).String(),
+		nil,
+	)
+	require.NoError(t, err)
+	require.NoError(t, conn.Close())
+}
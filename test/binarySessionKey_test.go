@@ -0,0 +1,126 @@
+package test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	wwr "github.com/qbeon/webwire-go"
+	wwrclt "github.com/qbeon/webwire-go/client"
+	"github.com/qbeon/webwire-go/wwrtest"
+)
+
+// TestBinarySessionKey tests restoring sessions by keys containing
+// null bytes and high bytes
+func TestBinarySessionKey(t *testing.T) {
+	sessionKey := "\x00bin\x00ary\xc3\xbf\xe2\x82\xac\x00"
+
+	// Initialize webwire server
+	server := setupServer(
+		t,
+		&serverImpl{
+			onRequest: func(
+				_ context.Context,
+				conn wwr.Connection,
+				_ wwr.Message,
+			) (wwr.Payload, error) {
+				// Try to create a new session
+				err := conn.CreateSession(context.Background(), nil)
+				assert.NoError(t, err)
+				return nil, err
+			},
+		},
+		wwr.ServerOptions{
+			SessionManager: wwrtest.NewInMemSessionManager(),
+			SessionKeyGenerator: &sessionKeyGen{
+				generate: func() string { return sessionKey },
+			},
+		},
+	)
+
+	// Initialize client
+	initialClient := newCallbackPoweredClient(
+		server.Addr().String(),
+		wwrclt.Options{
+			DefaultRequestTimeout: 2 * time.Second,
+		},
+		callbackPoweredClientHooks{},
+	)
+	require.NoError(t, initialClient.connection.Connect())
+
+	// Create a new session and disconnect without closing it
+	_, err := initialClient.connection.Request(
+		context.Background(),
+		"login",
+		nil,
+	)
+	require.NoError(t, err)
+	require.Equal(t, sessionKey, initialClient.connection.Session().Key)
+	initialClient.connection.Close()
+
+	// Restore the session on another client
+	secondClient := newCallbackPoweredClient(
+		server.Addr().String(),
+		wwrclt.Options{
+			DefaultRequestTimeout: 2 * time.Second,
+		},
+		callbackPoweredClientHooks{},
+	)
+	defer secondClient.connection.Close()
+	require.NoError(t, secondClient.connection.Connect())
+
+	require.NoError(t, secondClient.connection.RestoreSession(
+		[]byte(sessionKey),
+	))
+	require.Equal(t, sessionKey, secondClient.connection.Session().Key)
+}
+
+// TestInvalidUtf8SessionKey tests whether session creation fails
+// when the session key generator returns a key that isn't valid UTF-8
+func TestInvalidUtf8SessionKey(t *testing.T) {
+	// Initialize webwire server
+	server := setupServer(
+		t,
+		&serverImpl{
+			onRequest: func(
+				_ context.Context,
+				conn wwr.Connection,
+				_ wwr.Message,
+			) (wwr.Payload, error) {
+				// Try to create a new session
+				err := conn.CreateSession(context.Background(), nil)
+				assert.Error(t, err)
+				assert.Nil(t, conn.Session())
+				return nil, nil
+			},
+		},
+		wwr.ServerOptions{
+			SessionManager: wwrtest.NewInMemSessionManager(),
+			SessionKeyGenerator: &sessionKeyGen{
+				generate: func() string { return "invalid\xffkey" },
+			},
+		},
+	)
+
+	// Initialize client
+	client := newCallbackPoweredClient(
+		server.Addr().String(),
+		wwrclt.Options{
+			DefaultRequestTimeout: 2 * time.Second,
+		},
+		callbackPoweredClientHooks{},
+	)
+	defer client.connection.Close()
+	require.NoError(t, client.connection.Connect())
+
+	_, err := client.connection.Request(
+		context.Background(),
+		"login",
+		nil,
+	)
+	require.NoError(t, err)
+	require.Nil(t, client.connection.Session())
+}
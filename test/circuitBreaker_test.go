@@ -0,0 +1,159 @@
+package test
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	wwr "github.com/qbeon/webwire-go"
+	wwrclt "github.com/qbeon/webwire-go/client"
+)
+
+// TestCircuitBreaker tests whether requests are fast-failed
+// with a ServiceUnavailableErr while the circuit is open
+// and whether the circuit closes again after a successful trial request
+func TestCircuitBreaker(t *testing.T) {
+	var downstreamFailing int32 = 1
+	var handled int32
+	breaker := wwr.NewCircuitBreaker(2, 100*time.Millisecond)
+
+	// Initialize webwire server
+	server := setupServer(
+		t,
+		&serverImpl{
+			onRequest: breaker.Wrap(func(
+				_ context.Context,
+				_ wwr.Connection,
+				_ wwr.Message,
+			) (wwr.Payload, error) {
+				atomic.AddInt32(&handled, 1)
+				if atomic.LoadInt32(&downstreamFailing) == 1 {
+					return nil, fmt.Errorf("downstream failure")
+				}
+				return nil, nil
+			}),
+		},
+		wwr.ServerOptions{},
+	)
+
+	// Initialize client
+	client := newCallbackPoweredClient(
+		server.Addr().String(),
+		wwrclt.Options{
+			DefaultRequestTimeout: 2 * time.Second,
+		},
+		callbackPoweredClientHooks{},
+	)
+	defer client.connection.Close()
+	require.NoError(t, client.connection.Connect())
+
+	// Open the circuit by reaching the failure threshold
+	for i := 0; i < 2; i++ {
+		_, err := client.connection.Request(context.Background(), "r", nil)
+		require.IsType(t, wwr.ReqInternalErr{}, err)
+	}
+	require.Equal(t, wwr.CircuitOpen, breaker.State())
+
+	// Expect requests to be fast-failed without invoking the handler
+	_, err := client.connection.Request(context.Background(), "r", nil)
+	require.IsType(t, wwr.ServiceUnavailableErr{}, err)
+	require.Equal(t, int32(2), atomic.LoadInt32(&handled))
+
+	// Expect the remaining open duration to be provided as a retry hint
+	retryAfter := err.(wwr.ServiceUnavailableErr).RetryAfter()
+	require.True(t, retryAfter > 0 && retryAfter <= 100*time.Millisecond)
+
+	// Expect a successful trial request to close the circuit
+	// after the reset timeout
+	atomic.StoreInt32(&downstreamFailing, 0)
+	time.Sleep(150 * time.Millisecond)
+	require.Equal(t, wwr.CircuitHalfOpen, breaker.State())
+
+	_, err = client.connection.Request(context.Background(), "r", nil)
+	require.NoError(t, err)
+	require.Equal(t, wwr.CircuitClosed, breaker.State())
+	require.Equal(t, int32(3), atomic.LoadInt32(&handled))
+}
+
+// circuitOutcomeKey is the context key of the function determining
+// the outcome of a request passing a circuit breaker
+type circuitOutcomeKey struct{}
+
+// TestCircuitBreakerStaleOutcomes tests whether outcomes of requests
+// that passed the circuit before its last state change are ignored
+func TestCircuitBreakerStaleOutcomes(t *testing.T) {
+	clock := &manualClock{
+		lock: &sync.Mutex{},
+		now:  time.Date(2000, 1, 1, 0, 0, 0, 0, time.UTC),
+	}
+	breaker := wwr.NewCircuitBreaker(1, time.Second, clock)
+	handler := breaker.Wrap(func(
+		ctx context.Context,
+		_ wwr.Connection,
+		_ wwr.Message,
+	) (wwr.Payload, error) {
+		return nil, ctx.Value(circuitOutcomeKey{}).(func() error)()
+	})
+
+	// call passes a request with the given outcome through the circuit
+	// returning a channel receiving the returned error
+	// once the given release channel is closed
+	call := func(outcome error, release chan struct{}) chan error {
+		passed := make(chan struct{})
+		result := make(chan error, 1)
+		ctx := context.WithValue(
+			context.Background(),
+			circuitOutcomeKey{},
+			func() error {
+				close(passed)
+				<-release
+				return outcome
+			},
+		)
+		go func() {
+			_, err := handler(ctx, nil, nil)
+			result <- err
+		}()
+		select {
+		case <-passed:
+		case err := <-result:
+			require.FailNow(t, "request didn't pass the circuit", err)
+		}
+		return result
+	}
+
+	// Pass a slow successful and a slow failing request
+	releaseSuccess := make(chan struct{})
+	releaseFailure := make(chan struct{})
+	staleSuccess := call(nil, releaseSuccess)
+	staleFailure := call(fmt.Errorf("downstream failure"), releaseFailure)
+
+	// Open the circuit
+	released := make(chan struct{})
+	close(released)
+	require.Error(t, <-call(fmt.Errorf("downstream failure"), released))
+	require.Equal(t, wwr.CircuitOpen, breaker.State())
+
+	// Expect the stale outcomes to neither close the circuit
+	// nor reopen it once it's half-open
+	close(releaseSuccess)
+	require.NoError(t, <-staleSuccess)
+	require.Equal(t, wwr.CircuitOpen, breaker.State())
+
+	clock.Advance(time.Second)
+	trial := make(chan struct{})
+	trialResult := call(nil, trial)
+	close(releaseFailure)
+	require.Error(t, <-staleFailure)
+	require.Equal(t, wwr.CircuitHalfOpen, breaker.State())
+
+	// Expect the trial request to close the circuit
+	close(trial)
+	require.NoError(t, <-trialResult)
+	require.Equal(t, wwr.CircuitClosed, breaker.State())
+}
@@ -33,7 +33,7 @@ func TestConnectionClose(t *testing.T) {
 					return nil, nil
 				case "login":
 					// Try to create a new session
-					err := conn.CreateSession(nil)
+					err := conn.CreateSession(context.Background(), nil)
 					assert.NoError(t, err)
 					if err != nil {
 						return nil, err
@@ -45,7 +45,10 @@ func TestClientConnIsConnected(t *testing.T) {
 					testerGoroutineFinished.Progress(1)
 				}()
 			},
-			onClientDisconnected: func(_ wwr.Connection) {
+			onClientDisconnected: func(
+				_ wwr.Connection,
+				_ wwr.DisconnectReason,
+			) {
 				assert.False(t,
 					clientConn.IsActive(),
 					"Expected connection to be inactive",
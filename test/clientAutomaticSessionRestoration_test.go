@@ -36,7 +36,7 @@ func TestClientAutomaticSessionRestoration(t *testing.T) {
 				}
 
 				// Try to create a new session
-				err := conn.CreateSession(nil)
+				err := conn.CreateSession(context.Background(), nil)
 				assert.NoError(t, err)
 				return nil, err
 			},
@@ -44,13 +44,19 @@ func TestClientAutomaticSessionRestoration(t *testing.T) {
 		wwr.ServerOptions{
 			SessionManager: &callbackPoweredSessionManager{
 				// Saves the session
-				SessionCreated: func(conn wwr.Connection) error {
+				SessionCreated: func(
+					_ context.Context,
+					conn wwr.Connection,
+				) error {
 					session := conn.Session()
 					sessionStorage[session.Key] = session
 					return nil
 				},
 				// Finds session by key
-				SessionLookup: func(key string) (
+				SessionLookup: func(
+					_ context.Context,
+					key string,
+				) (
 					wwr.SessionLookupResult,
 					error,
 				) {
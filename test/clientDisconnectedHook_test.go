@@ -29,7 +29,10 @@ func TestClientDisconnectedHook(t *testing.T) {
 				clientConn = conn
 				connectedClientLock.Unlock()
 			},
-			onClientDisconnected: func(conn wwr.Connection) {
+			onClientDisconnected: func(
+				conn wwr.Connection,
+				_ wwr.DisconnectReason,
+			) {
 				connectedClientLock.Lock()
 				assert.Equal(t, clientConn, conn)
 				connectedClientLock.Unlock()
@@ -38,7 +38,7 @@ func TestClientOfflineSessionClosure(t *testing.T) {
 				}
 
 				// Try to create a new session
-				err := conn.CreateSession(nil)
+				err := conn.CreateSession(context.Background(), nil)
 				assert.NoError(t, err)
 				return nil, err
 			},
@@ -46,13 +46,19 @@ func TestClientOfflineSessionClosure(t *testing.T) {
 		wwr.ServerOptions{
 			SessionManager: &callbackPoweredSessionManager{
 				// Saves the session
-				SessionCreated: func(conn wwr.Connection) error {
+				SessionCreated: func(
+					_ context.Context,
+					conn wwr.Connection,
+				) error {
 					session := conn.Session()
 					sessionStorage[session.Key] = session
 					return nil
 				},
 				// Finds session by key
-				SessionLookup: func(key string) (
+				SessionLookup: func(
+					_ context.Context,
+					key string,
+				) (
 					wwr.SessionLookupResult,
 					error,
 				) {
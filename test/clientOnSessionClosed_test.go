@@ -29,7 +29,7 @@ func TestClientOnSessionClosed(t *testing.T) {
 				_ wwr.Message,
 			) (wwr.Payload, error) {
 				// Try to create a new session
-				err := conn.CreateSession(nil)
+				err := conn.CreateSession(context.Background(), nil)
 				assert.NoError(t, err)
 				if err != nil {
 					return nil, err
@@ -53,13 +53,17 @@ func TestClientOnSessionClosed(t *testing.T) {
 	)
 
 	// Initialize client
-	client := newCallbackPoweredClient(
+	var client *callbackPoweredClient
+	client = newCallbackPoweredClient(
 		server.Addr().String(),
 		wwrclt.Options{
 			DefaultRequestTimeout: 2 * time.Second,
 		},
 		callbackPoweredClientHooks{
 			OnSessionClosed: func() {
+				// Expect the local session to be cleared
+				// before the hook is invoked
+				assert.Nil(t, client.connection.Session())
 				hookCalled.Progress(1)
 			},
 		},
@@ -74,8 +78,10 @@ func TestClientOnSessionClosed(t *testing.T) {
 		wwr.NewPayload(wwr.EncodingBinary, []byte("credentials")),
 	)
 	require.NoError(t, err)
+	require.NotNil(t, client.connection.Session())
 	authenticated.Progress(1)
 
 	// Verify client session
 	require.NoError(t, hookCalled.Wait(), "Hook not called")
+	require.Nil(t, client.connection.Session())
 }
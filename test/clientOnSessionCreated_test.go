@@ -30,7 +30,7 @@ func TestClientOnSessionCreated(t *testing.T) {
 				_ wwr.Message,
 			) (wwr.Payload, error) {
 				// Try to create a new session
-				err := conn.CreateSession(nil)
+				err := conn.CreateSession(context.Background(), nil)
 				assert.NoError(t, err)
 				return nil, err
 			},
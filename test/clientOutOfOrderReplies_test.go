@@ -0,0 +1,136 @@
+package test
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	tmdwg "github.com/qbeon/tmdwg-go"
+	wwr "github.com/qbeon/webwire-go"
+	wwrclt "github.com/qbeon/webwire-go/client"
+)
+
+// TestClientOutOfOrderReplies tests whether replies arriving
+// in a different order than the requests were sent in
+// are handed to the right requests
+func TestClientOutOfOrderReplies(t *testing.T) {
+	fastReplied := make(chan struct{})
+
+	// Initialize webwire server
+	server := setupServer(
+		t,
+		&serverImpl{
+			onRequest: func(
+				_ context.Context,
+				_ wwr.Connection,
+				msg wwr.Message,
+			) (wwr.Payload, error) {
+				if msg.Name() == "slow" {
+					// Reply to the slow request only after
+					// the fast request was replied to
+					<-fastReplied
+					return wwr.NewPayload(
+						wwr.EncodingUtf8,
+						[]byte("slow reply"),
+					), nil
+				}
+				return wwr.NewPayload(
+					wwr.EncodingUtf8,
+					[]byte("fast reply"),
+				), nil
+			},
+		},
+		wwr.ServerOptions{},
+	)
+
+	// Initialize client
+	client := newCallbackPoweredClient(
+		server.Addr().String(),
+		wwrclt.Options{
+			DefaultRequestTimeout: 2 * time.Second,
+		},
+		callbackPoweredClientHooks{},
+	)
+	defer client.connection.Close()
+	require.NoError(t, client.connection.Connect())
+
+	// Send the slow request first
+	slowReplied := sync.WaitGroup{}
+	slowReplied.Add(1)
+	go func() {
+		defer slowReplied.Done()
+		reply, err := client.connection.Request(
+			context.Background(),
+			"slow",
+			nil,
+		)
+		assert.NoError(t, err)
+		if err == nil {
+			assert.Equal(t, "slow reply", string(reply.Data()))
+		}
+	}()
+
+	// Send the fast request which is replied to first
+	reply, err := client.connection.Request(
+		context.Background(),
+		"fast",
+		nil,
+	)
+	close(fastReplied)
+	require.NoError(t, err)
+	require.Equal(t, "fast reply", string(reply.Data()))
+
+	slowReplied.Wait()
+}
+
+// TestClientRequestConnectionLost tests whether pending requests
+// are failed with a ConnectionLostErr as soon as the connection is lost
+func TestClientRequestConnectionLost(t *testing.T) {
+	handlerEntered := tmdwg.NewTimedWaitGroup(1, 1*time.Second)
+	releaseHandler := make(chan struct{})
+	defer close(releaseHandler)
+
+	// Initialize webwire server
+	server := setupServer(
+		t,
+		&serverImpl{
+			onRequest: func(
+				_ context.Context,
+				conn wwr.Connection,
+				_ wwr.Message,
+			) (wwr.Payload, error) {
+				// Abruptly drop the connection without replying
+				handlerEntered.Progress(1)
+				conn.UnderlyingConn().(*websocket.Conn).Close()
+				<-releaseHandler
+				return nil, nil
+			},
+		},
+		wwr.ServerOptions{},
+	)
+
+	// Initialize client
+	client := newCallbackPoweredClient(
+		server.Addr().String(),
+		wwrclt.Options{
+			DefaultRequestTimeout: 10 * time.Second,
+			Autoconnect:           wwr.Disabled,
+		},
+		callbackPoweredClientHooks{},
+	)
+	defer client.connection.Close()
+	require.NoError(t, client.connection.Connect())
+
+	// Expect the request to fail long before it times out
+	start := time.Now()
+	_, err := client.connection.Request(context.Background(), "drop", nil)
+	require.Error(t, err)
+	require.IsType(t, wwr.ConnectionLostErr{}, err)
+	require.True(t, time.Since(start) < 5*time.Second)
+	require.NoError(t, handlerEntered.Wait())
+}
@@ -0,0 +1,84 @@
+package test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	wwrclt "github.com/qbeon/webwire-go/client"
+)
+
+// TestClientReconnectBackoff tests whether the reconnection interval
+// grows exponentially up to the maximum interval
+// when the server is unreachable
+func TestClientReconnectBackoff(t *testing.T) {
+	type scheduled struct {
+		attempt uint
+		delay   time.Duration
+	}
+	schedules := make(chan scheduled, 4)
+
+	// Initialize client
+	newCallbackPoweredClient(
+		"127.0.0.1:65000",
+		wwrclt.Options{
+			ReconnectionInterval:    5 * time.Millisecond,
+			ReconnectionMaxInterval: 20 * time.Millisecond,
+			OnReconnectScheduled: func(attempt uint, delay time.Duration) {
+				select {
+				case schedules <- scheduled{attempt, delay}:
+				default:
+				}
+			},
+		},
+		callbackPoweredClientHooks{},
+	)
+
+	for _, expected := range []scheduled{
+		{2, 5 * time.Millisecond},
+		{3, 10 * time.Millisecond},
+		{4, 20 * time.Millisecond},
+		{5, 20 * time.Millisecond},
+	} {
+		select {
+		case actual := <-schedules:
+			require.Equal(t, expected, actual)
+		case <-time.After(1 * time.Second):
+			t.Fatal("Reconnection attempt not scheduled")
+		}
+	}
+}
+
+// TestClientReconnectBackoffJitter tests whether the reconnection interval
+// is randomly reduced by up to the jitter factor
+func TestClientReconnectBackoffJitter(t *testing.T) {
+	delays := make(chan time.Duration, 8)
+
+	// Initialize client
+	newCallbackPoweredClient(
+		"127.0.0.1:65000",
+		wwrclt.Options{
+			ReconnectionInterval:    10 * time.Millisecond,
+			ReconnectionMaxInterval: 10 * time.Millisecond,
+			ReconnectionJitter:      0.5,
+			OnReconnectScheduled: func(_ uint, delay time.Duration) {
+				select {
+				case delays <- delay:
+				default:
+				}
+			},
+		},
+		callbackPoweredClientHooks{},
+	)
+
+	for i := 0; i < 8; i++ {
+		select {
+		case delay := <-delays:
+			require.True(t, delay > 5*time.Millisecond, delay)
+			require.True(t, delay <= 10*time.Millisecond, delay)
+		case <-time.After(1 * time.Second):
+			t.Fatal("Reconnection attempt not scheduled")
+		}
+	}
+}
@@ -0,0 +1,89 @@
+package test
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/stretchr/testify/require"
+
+	tmdwg "github.com/qbeon/tmdwg-go"
+	wwr "github.com/qbeon/webwire-go"
+	wwrclt "github.com/qbeon/webwire-go/client"
+)
+
+// TestClientReplayIdempotentRequests tests whether pending idempotent
+// requests are replayed after the connection was reestablished
+// while other pending requests fail with a ConnectionLostErr
+func TestClientReplayIdempotentRequests(t *testing.T) {
+	idempotentName := wwr.IdempotentRequestName("submit", "key")
+	plainArrived := tmdwg.NewTimedWaitGroup(1, 1*time.Second)
+	releasePlain := make(chan struct{})
+	defer close(releasePlain)
+	idempotentHandled := int32(0)
+
+	// Initialize webwire server dropping the connection on the first
+	// idempotent request
+	server := setupServer(
+		t,
+		&serverImpl{
+			onRequest: func(
+				_ context.Context,
+				conn wwr.Connection,
+				msg wwr.Message,
+			) (wwr.Payload, error) {
+				if msg.Name() != idempotentName {
+					plainArrived.Progress(1)
+					<-releasePlain
+					return nil, nil
+				}
+				if atomic.AddInt32(&idempotentHandled, 1) == 1 {
+					conn.UnderlyingConn().(*websocket.Conn).Close()
+					return nil, nil
+				}
+				return wwr.NewPayload(
+					wwr.EncodingUtf8,
+					[]byte("replayed"),
+				), nil
+			},
+		},
+		wwr.ServerOptions{},
+	)
+
+	// Initialize client
+	client := newCallbackPoweredClient(
+		server.Addr().String(),
+		wwrclt.Options{
+			DefaultRequestTimeout:    2 * time.Second,
+			ReconnectionInterval:     10 * time.Millisecond,
+			ReplayIdempotentRequests: wwr.Enabled,
+		},
+		callbackPoweredClientHooks{},
+	)
+	defer client.connection.Close()
+	require.NoError(t, client.connection.Connect())
+
+	// Send a non-idempotent request that's pending
+	// when the connection is lost
+	plainErr := make(chan error, 1)
+	go func() {
+		_, err := client.connection.Request(context.Background(), "plain", nil)
+		plainErr <- err
+	}()
+	require.NoError(t, plainArrived.Wait())
+
+	// Expect the idempotent request to be replayed on the new connection
+	reply, err := client.connection.Request(
+		context.Background(),
+		idempotentName,
+		nil,
+	)
+	require.NoError(t, err)
+	require.Equal(t, "replayed", string(reply.Data()))
+	require.Equal(t, int32(2), atomic.LoadInt32(&idempotentHandled))
+
+	// Expect the non-idempotent request to be considered lost
+	require.IsType(t, wwr.ConnectionLostErr{}, <-plainErr)
+}
@@ -0,0 +1,72 @@
+package test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	wwr "github.com/qbeon/webwire-go"
+	wwrclt "github.com/qbeon/webwire-go/client"
+)
+
+// TestClientRequestErrorUtf16 tests request errors carrying
+// localized UTF16 encoded error messages
+func TestClientRequestErrorUtf16(t *testing.T) {
+	// Use error codes of both odd and even length
+	// to cover the header padding
+	replyErrors := map[string]wwr.ReqErr{
+		"odd": {
+			Code:     "NOT_FOUND",
+			Message:  "Пользователь не найден: ユーザーが見つかりません \U0001F50D",
+			Encoding: wwr.EncodingUtf16,
+		},
+		"even": {
+			Code:     "NOT_FOUND_",
+			Message:  "Benutzer nicht gefunden",
+			Encoding: wwr.EncodingUtf16,
+		},
+	}
+
+	// Initialize webwire server failing all requests
+	server := setupServer(
+		t,
+		&serverImpl{
+			onRequest: func(
+				_ context.Context,
+				_ wwr.Connection,
+				message wwr.Message,
+			) (wwr.Payload, error) {
+				return nil, replyErrors[message.Name()]
+			},
+		},
+		wwr.ServerOptions{},
+	)
+
+	// Initialize client
+	client := newCallbackPoweredClient(
+		server.Addr().String(),
+		wwrclt.Options{
+			DefaultRequestTimeout: 2 * time.Second,
+		},
+		callbackPoweredClientHooks{},
+	)
+	defer client.connection.Close()
+
+	require.NoError(t, client.connection.Connect())
+
+	// Send requests and expect the error messages to be decoded
+	for name, replyErr := range replyErrors {
+		reply, err := client.connection.Request(
+			context.Background(),
+			name,
+			nil,
+		)
+		require.Equal(t, wwr.ReqErr{
+			Code:    replyErr.Code,
+			Message: replyErr.Message,
+		}, err)
+		require.Nil(t, reply)
+	}
+}
@@ -0,0 +1,57 @@
+package test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	wwr "github.com/qbeon/webwire-go"
+	wwrclt "github.com/qbeon/webwire-go/client"
+)
+
+// TestClientRequestErrorUtf8 tests request errors carrying
+// localized UTF8 encoded error messages
+func TestClientRequestErrorUtf8(t *testing.T) {
+	expectedReplyError := wwr.ReqErr{
+		Code:    "NOT_FOUND",
+		Message: "Пользователь не найден: ユーザーが見つかりません",
+	}
+
+	// Initialize webwire server failing all requests
+	server := setupServer(
+		t,
+		&serverImpl{
+			onRequest: func(
+				_ context.Context,
+				_ wwr.Connection,
+				_ wwr.Message,
+			) (wwr.Payload, error) {
+				return nil, expectedReplyError
+			},
+		},
+		wwr.ServerOptions{},
+	)
+
+	// Initialize client
+	client := newCallbackPoweredClient(
+		server.Addr().String(),
+		wwrclt.Options{
+			DefaultRequestTimeout: 2 * time.Second,
+		},
+		callbackPoweredClientHooks{},
+	)
+	defer client.connection.Close()
+
+	require.NoError(t, client.connection.Connect())
+
+	// Send request and expect the error message to be preserved
+	reply, err := client.connection.Request(
+		context.Background(),
+		"request",
+		nil,
+	)
+	require.Equal(t, expectedReplyError, err)
+	require.Nil(t, reply)
+}
@@ -0,0 +1,100 @@
+package test
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	tmdwg "github.com/qbeon/tmdwg-go"
+	wwr "github.com/qbeon/webwire-go"
+	wwrclt "github.com/qbeon/webwire-go/client"
+)
+
+// reserveAddress returns a currently unused loopback address
+func reserveAddress(t *testing.T) string {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	addr := listener.Addr().String()
+	require.NoError(t, listener.Close())
+	return addr
+}
+
+// TestClientRequestQueue tests queueing requests
+// until the connection is established
+func TestClientRequestQueue(t *testing.T) {
+	serverAddr := reserveAddress(t)
+	requestsReplied := tmdwg.NewTimedWaitGroup(3, 3*time.Second)
+
+	// Initialize client before the server is available
+	client := newCallbackPoweredClient(
+		serverAddr,
+		wwrclt.Options{
+			DefaultRequestTimeout: 3 * time.Second,
+			ReconnectionInterval:  50 * time.Millisecond,
+		},
+		callbackPoweredClientHooks{},
+	)
+	defer client.connection.Close()
+
+	for i := 0; i < 3; i++ {
+		go func() {
+			_, err := client.connection.Request(
+				context.Background(),
+				"queued",
+				nil,
+			)
+			assert.NoError(t, err)
+			requestsReplied.Progress(1)
+		}()
+	}
+
+	// Launch the server on the reserved address
+	time.Sleep(100 * time.Millisecond)
+	setupServer(
+		t,
+		&serverImpl{},
+		wwr.ServerOptions{
+			Address: serverAddr,
+		},
+	)
+
+	require.NoError(t, requestsReplied.Wait())
+}
+
+// TestClientRequestQueueFull tests rejecting requests
+// when the request queue is full
+func TestClientRequestQueueFull(t *testing.T) {
+	// Initialize client pointing to an unavailable server
+	client := newCallbackPoweredClient(
+		reserveAddress(t),
+		wwrclt.Options{
+			DefaultRequestTimeout:  300 * time.Millisecond,
+			ReconnectionInterval:   50 * time.Millisecond,
+			ReconnectQueueCapacity: 1,
+		},
+		callbackPoweredClientHooks{},
+	)
+	defer client.connection.Close()
+
+	firstRequestFailed := tmdwg.NewTimedWaitGroup(1, 1*time.Second)
+	go func() {
+		_, err := client.connection.Request(
+			context.Background(),
+			"first",
+			nil,
+		)
+		assert.True(t, wwr.IsTimeoutErr(err))
+		firstRequestFailed.Progress(1)
+	}()
+
+	// Expect the second request to be rejected immediately
+	time.Sleep(50 * time.Millisecond)
+	_, err := client.connection.Request(context.Background(), "second", nil)
+	require.IsType(t, wwr.ReqQueueFullErr{}, err)
+
+	require.NoError(t, firstRequestFailed.Wait())
+}
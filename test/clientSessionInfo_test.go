@@ -120,12 +120,12 @@ func TestClientSessionInfo(t *testing.T) {
 		t,
 		&serverImpl{
 			onRequest: func(
-				_ context.Context,
+				ctx context.Context,
 				conn wwr.Connection,
 				_ wwr.Message,
 			) (wwr.Payload, error) {
 				// Try to create a new session
-				err := conn.CreateSession(&testClientSessionInfoSessionInfo{
+				err := conn.CreateSession(ctx, &testClientSessionInfoSessionInfo{
 					Bool:   expectedBool,
 					String: expectedString,
 					Int:    expectedInt,
@@ -35,7 +35,7 @@ func TestClientSessionRestoration(t *testing.T) {
 				}
 
 				// Try to create a new session
-				err := conn.CreateSession(nil)
+				err := conn.CreateSession(context.Background(), nil)
 				assert.NoError(t, err)
 				return nil, err
 			},
@@ -43,13 +43,19 @@ func TestClientSessionRestoration(t *testing.T) {
 		wwr.ServerOptions{
 			SessionManager: &callbackPoweredSessionManager{
 				// Saves the session
-				SessionCreated: func(conn wwr.Connection) error {
+				SessionCreated: func(
+					_ context.Context,
+					conn wwr.Connection,
+				) error {
 					sess := conn.Session()
 					sessionStorage[sess.Key] = sess
 					return nil
 				},
 				// Finds session by key
-				SessionLookup: func(key string) (
+				SessionLookup: func(
+					_ context.Context,
+					key string,
+				) (
 					wwr.SessionLookupResult,
 					error,
 				) {
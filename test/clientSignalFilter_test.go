@@ -0,0 +1,66 @@
+package test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	tmdwg "github.com/qbeon/tmdwg-go"
+	wwr "github.com/qbeon/webwire-go"
+	wwrclt "github.com/qbeon/webwire-go/client"
+)
+
+// TestClientSignalFilter tests whether signals of names the client
+// isn't subscribed to are dropped while reliable ones are still acknowledged
+func TestClientSignalFilter(t *testing.T) {
+	connected := tmdwg.NewTimedWaitGroup(1, 1*time.Second)
+	var serverSideConn wwr.Connection
+	signalReceived := tmdwg.NewTimedWaitGroup(1, 1*time.Second)
+
+	// Initialize webwire server
+	server := setupServer(
+		t,
+		&serverImpl{
+			onClientConnected: func(conn wwr.Connection) {
+				serverSideConn = conn
+				connected.Progress(1)
+			},
+		},
+		wwr.ServerOptions{
+			SignalAckTimeout:       1 * time.Second,
+			ReliableSignalAttempts: 1,
+		},
+	)
+
+	// Initialize client
+	client := newCallbackPoweredClient(
+		server.Addr().String(),
+		wwrclt.Options{
+			DefaultRequestTimeout: 2 * time.Second,
+		},
+		callbackPoweredClientHooks{
+			OnSignal: func(message wwr.Message) {
+				assert.Equal(t, "wanted", message.Name())
+				signalReceived.Progress(1)
+			},
+		},
+	)
+	defer client.connection.Close()
+	client.connection.SubscribeSignal("wanted")
+	client.connection.SubscribeSignal("removed")
+	client.connection.UnsubscribeSignal("removed")
+	require.NoError(t, client.connection.Connect())
+	require.NoError(t, connected.Wait())
+
+	payload := wwr.NewPayload(wwr.EncodingUtf8, []byte("data"))
+
+	// Expect unsubscribed reliable signals to be acknowledged nonetheless
+	require.NoError(t, serverSideConn.ReliableSignal("unwanted", payload))
+
+	require.NoError(t, serverSideConn.Signal("unwanted", payload))
+	require.NoError(t, serverSideConn.Signal("removed", payload))
+	require.NoError(t, serverSideConn.Signal("wanted", payload))
+	require.NoError(t, signalReceived.Wait())
+}
@@ -0,0 +1,98 @@
+package test
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	tmdwg "github.com/qbeon/tmdwg-go"
+	wwr "github.com/qbeon/webwire-go"
+	wwrclt "github.com/qbeon/webwire-go/client"
+)
+
+// manualClock implements the webwire.Clock interface
+// and is only advanced manually
+type manualClock struct {
+	lock *sync.Mutex
+	now  time.Time
+}
+
+// Now implements the webwire.Clock interface
+func (clock *manualClock) Now() time.Time {
+	clock.lock.Lock()
+	defer clock.lock.Unlock()
+	return clock.now
+}
+
+// Advance advances the clock by the given duration
+func (clock *manualClock) Advance(duration time.Duration) {
+	clock.lock.Lock()
+	clock.now = clock.now.Add(duration)
+	clock.lock.Unlock()
+}
+
+// TestClock tests whether the server uses the configured clock
+// for session creation times and idle connection detection
+func TestClock(t *testing.T) {
+	clock := &manualClock{
+		lock: &sync.Mutex{},
+		now:  time.Date(2000, 1, 1, 0, 0, 0, 0, time.UTC),
+	}
+	start := clock.Now()
+	disconnected := tmdwg.NewTimedWaitGroup(1, 1*time.Second)
+
+	// Initialize webwire server
+	server := setupServer(
+		t,
+		&serverImpl{
+			onRequest: func(
+				ctx context.Context,
+				conn wwr.Connection,
+				_ wwr.Message,
+			) (wwr.Payload, error) {
+				assert.True(t, start.Equal(conn.Info().ConnectionTime))
+				assert.NoError(t, conn.CreateSession(ctx, nil))
+				assert.True(t, start.Equal(conn.SessionCreation()))
+				return nil, nil
+			},
+			onClientDisconnected: func(
+				_ wwr.Connection,
+				reason wwr.DisconnectReason,
+			) {
+				if reason == wwr.DisconnectServerInitiated {
+					disconnected.Progress(1)
+				}
+			},
+		},
+		wwr.ServerOptions{
+			Clock: clock,
+		},
+	)
+
+	// Initialize client
+	client := newCallbackPoweredClient(
+		server.Addr().String(),
+		wwrclt.Options{
+			DefaultRequestTimeout: 2 * time.Second,
+			Autoconnect:           wwr.Disabled,
+		},
+		callbackPoweredClientHooks{},
+	)
+	defer client.connection.Close()
+	require.NoError(t, client.connection.Connect())
+
+	_, err := client.connection.Request(context.Background(), "test", nil)
+	require.NoError(t, err)
+
+	// Expect the connection not to be idle before the clock is advanced
+	require.Equal(t, 0, server.CloseIdleConnections(1*time.Hour))
+
+	// Advance the clock and expect the connection to be idle immediately
+	clock.Advance(2 * time.Hour)
+	require.Equal(t, 1, server.CloseIdleConnections(1*time.Hour))
+	require.NoError(t, disconnected.Wait())
+}
@@ -0,0 +1,102 @@
+package test
+
+import (
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	tmdwg "github.com/qbeon/tmdwg-go"
+	wwr "github.com/qbeon/webwire-go"
+)
+
+// dialRaw connects a raw websocket client to the given server
+func dialRaw(t *testing.T, server wwr.Server) *websocket.Conn {
+	endpointURL := url.URL{
+		Scheme: "ws",
+		Host:   server.Addr().String(),
+		Path:   "/",
+	}
+	conn, _, err := websocket.DefaultDialer.Dial(endpointURL.String(), nil)
+	require.NoError(t, err)
+	return conn
+}
+
+// readCloseCode reads from the given raw websocket client
+// until the connection is closed and returns the close code
+func readCloseCode(t *testing.T, conn *websocket.Conn) int {
+	require.NoError(t, conn.SetReadDeadline(time.Now().Add(2*time.Second)))
+	for {
+		if _, _, err := conn.ReadMessage(); err != nil {
+			require.IsType(t, &websocket.CloseError{}, err)
+			return err.(*websocket.CloseError).Code
+		}
+	}
+}
+
+// TestCloseCodeMessageTooBig tests whether clients sending messages
+// exceeding the max message size are disconnected
+// with the 1009 (message too big) close code
+func TestCloseCodeMessageTooBig(t *testing.T) {
+	disconnected := tmdwg.NewTimedWaitGroup(1, 1*time.Second)
+
+	// Initialize webwire server
+	server := setupServer(
+		t,
+		&serverImpl{
+			onClientDisconnected: func(
+				_ wwr.Connection,
+				reason wwr.DisconnectReason,
+			) {
+				assert.Equal(t, wwr.DisconnectMessageTooBig, reason)
+				disconnected.Progress(1)
+			},
+		},
+		wwr.ServerOptions{
+			MaxMessageSize: 1024,
+		},
+	)
+
+	conn := dialRaw(t, server)
+	defer conn.Close()
+
+	require.NoError(t, conn.WriteMessage(
+		websocket.BinaryMessage,
+		make([]byte, 2048),
+	))
+	require.Equal(t, websocket.CloseMessageTooBig, readCloseCode(t, conn))
+	require.NoError(t, disconnected.Wait())
+}
+
+// TestCloseCodeProtocolError tests whether clients sending malformed
+// frames are disconnected with the 1002 (protocol error) close code
+func TestCloseCodeProtocolError(t *testing.T) {
+	disconnected := tmdwg.NewTimedWaitGroup(1, 1*time.Second)
+
+	// Initialize webwire server
+	server := setupServer(
+		t,
+		&serverImpl{
+			onClientDisconnected: func(
+				_ wwr.Connection,
+				reason wwr.DisconnectReason,
+			) {
+				assert.Equal(t, wwr.DisconnectProtocolError, reason)
+				disconnected.Progress(1)
+			},
+		},
+		wwr.ServerOptions{},
+	)
+
+	conn := dialRaw(t, server)
+	defer conn.Close()
+
+	// Write a masked empty binary frame with the reserved RSV2 bit set
+	_, err := conn.UnderlyingConn().Write([]byte{0xA2, 0x80, 0, 0, 0, 0})
+	require.NoError(t, err)
+	require.Equal(t, websocket.CloseProtocolError, readCloseCode(t, conn))
+	require.NoError(t, disconnected.Wait())
+}
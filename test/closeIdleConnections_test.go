@@ -0,0 +1,93 @@
+package test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	tmdwg "github.com/qbeon/tmdwg-go"
+	wwr "github.com/qbeon/webwire-go"
+	wwrclt "github.com/qbeon/webwire-go/client"
+)
+
+// TestCloseIdleConnections tests closing connections
+// that didn't receive any messages for a certain duration
+func TestCloseIdleConnections(t *testing.T) {
+	signalReceived := tmdwg.NewTimedWaitGroup(1, 1*time.Second)
+	disconnected := tmdwg.NewTimedWaitGroup(1, 1*time.Second)
+	idleFor := 100 * time.Millisecond
+
+	// Initialize webwire server
+	server := setupServer(
+		t,
+		&serverImpl{
+			onSignal: func(
+				_ context.Context,
+				conn wwr.Connection,
+				_ wwr.Message,
+			) {
+				// Expect the last activity to be updated
+				assert.WithinDuration(
+					t,
+					time.Now(),
+					conn.LastActivity(),
+					idleFor/2,
+				)
+				signalReceived.Progress(1)
+			},
+			onClientDisconnected: func(
+				_ wwr.Connection,
+				reason wwr.DisconnectReason,
+			) {
+				// Ignore the clients closing their connections
+				// when the test is over
+				if reason == wwr.DisconnectServerInitiated {
+					disconnected.Progress(1)
+				}
+			},
+		},
+		wwr.ServerOptions{},
+	)
+
+	// Initialize an idle and an active client
+	idleClient := newCallbackPoweredClient(
+		server.Addr().String(),
+		wwrclt.Options{
+			Autoconnect: wwr.Disabled,
+		},
+		callbackPoweredClientHooks{},
+	)
+	defer idleClient.connection.Close()
+
+	activeClient := newCallbackPoweredClient(
+		server.Addr().String(),
+		wwrclt.Options{
+			Autoconnect: wwr.Disabled,
+		},
+		callbackPoweredClientHooks{},
+	)
+	defer activeClient.connection.Close()
+
+	require.NoError(t, idleClient.connection.Connect())
+	require.NoError(t, activeClient.connection.Connect())
+
+	// Let both connections idle
+	time.Sleep(idleFor * 2)
+
+	// Keep the active client active
+	require.NoError(t, activeClient.connection.Signal(
+		"",
+		wwr.NewPayload(wwr.EncodingBinary, []byte("activity")),
+	))
+	require.NoError(t, signalReceived.Wait())
+
+	// Expect only the idle connection to be closed
+	require.Equal(t, 1, server.CloseIdleConnections(idleFor))
+	require.NoError(t, disconnected.Wait())
+
+	// Expect no other connections to be closed
+	require.Equal(t, 0, server.CloseIdleConnections(idleFor))
+}
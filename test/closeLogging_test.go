@@ -0,0 +1,78 @@
+package test
+
+import (
+	"bytes"
+	"log"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/stretchr/testify/require"
+
+	wwr "github.com/qbeon/webwire-go"
+)
+
+// TestCloseLogging tests whether closures are logged
+// according to ServerOptions.ClassifyCloseCode
+func TestCloseLogging(t *testing.T) {
+	disconnected := make(chan struct{}, 1)
+	warnLog := &bytes.Buffer{}
+	errorLog := &bytes.Buffer{}
+
+	// Initialize webwire server
+	server := setupServer(
+		t,
+		&serverImpl{
+			onClientDisconnected: func(
+				_ wwr.Connection,
+				_ wwr.DisconnectReason,
+			) {
+				disconnected <- struct{}{}
+			},
+		},
+		wwr.ServerOptions{
+			WarnLog:  log.New(warnLog, "", 0),
+			ErrorLog: log.New(errorLog, "", 0),
+			ClassifyCloseCode: func(code int) wwr.CloseLogLevel {
+				switch code {
+				case 4001:
+					return wwr.CloseLogWarn
+				case 4002:
+					return wwr.CloseLogError
+				}
+				return wwr.CloseLogSuppress
+			},
+		},
+	)
+
+	// closeWithCode connects a client closing the connection
+	// with the given close code and awaits the disconnection
+	closeWithCode := func(code int) {
+		conn := dialRaw(t, server)
+		defer conn.Close()
+		require.NoError(t, conn.WriteMessage(
+			websocket.CloseMessage,
+			websocket.FormatCloseMessage(code, ""),
+		))
+		select {
+		case <-disconnected:
+		case <-time.After(2 * time.Second):
+			t.Fatal("Client wasn't disconnected")
+		}
+	}
+
+	// Expect suppressed closures not to be logged
+	closeWithCode(websocket.CloseGoingAway)
+	closeWithCode(4000)
+	require.Zero(t, warnLog.Len())
+	require.Zero(t, errorLog.Len())
+
+	// Expect closures to be logged at the classified level
+	closeWithCode(4001)
+	require.Contains(t, warnLog.String(), "(4001)")
+	require.Zero(t, errorLog.Len())
+
+	closeWithCode(4002)
+	require.Contains(t, errorLog.String(), "(4002)")
+	require.NotContains(t, warnLog.String(), "(4002)")
+}
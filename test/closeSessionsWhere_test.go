@@ -0,0 +1,80 @@
+package test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	tmdwg "github.com/qbeon/tmdwg-go"
+	wwr "github.com/qbeon/webwire-go"
+	wwrclt "github.com/qbeon/webwire-go/client"
+)
+
+// TestCloseSessionsWhere tests closing all sessions
+// whose info matches a predicate
+func TestCloseSessionsWhere(t *testing.T) {
+	sessionClosed := tmdwg.NewTimedWaitGroup(2, 1*time.Second)
+
+	// Initialize webwire server
+	server := setupServer(
+		t,
+		&serverImpl{
+			onRequest: func(
+				_ context.Context,
+				conn wwr.Connection,
+				msg wwr.Message,
+			) (wwr.Payload, error) {
+				// Create a session of the requested role
+				err := conn.CreateSession(
+					context.Background(),
+					wwr.GenericSessionInfoParser(map[string]interface{}{
+						"role": msg.Name(),
+					}),
+				)
+				assert.NoError(t, err)
+				return nil, err
+			},
+		},
+		wwr.ServerOptions{},
+	)
+
+	// Initialize clients of different roles
+	roles := []string{"editor", "admin", "editor"}
+	clients := make([]*callbackPoweredClient, len(roles))
+	for i, role := range roles {
+		role := role
+		client := newCallbackPoweredClient(
+			server.Addr().String(),
+			wwrclt.Options{
+				DefaultRequestTimeout: 2 * time.Second,
+				Autoconnect:           wwr.Disabled,
+			},
+			callbackPoweredClientHooks{
+				OnSessionClosed: func() {
+					assert.Equal(t, "editor", role)
+					sessionClosed.Progress(1)
+				},
+			},
+		)
+		defer client.connection.Close()
+		require.NoError(t, client.connection.Connect())
+		_, err := client.connection.Request(context.Background(), role, nil)
+		require.NoError(t, err)
+		clients[i] = client
+	}
+
+	// Close the sessions of all editors
+	closed := server.CloseSessionsWhere(func(info wwr.SessionInfo) bool {
+		return info != nil && info.Value("role") == "editor"
+	})
+	require.Equal(t, 2, closed)
+	require.NoError(t, sessionClosed.Wait())
+
+	require.Nil(t, clients[0].connection.Session())
+	require.NotNil(t, clients[1].connection.Session())
+	require.Nil(t, clients[2].connection.Session())
+	require.Equal(t, 1, server.ActiveSessionsNum())
+}
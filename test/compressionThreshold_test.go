@@ -0,0 +1,262 @@
+package test
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"net"
+	"net/url"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	wwr "github.com/qbeon/webwire-go"
+	wwrclt "github.com/qbeon/webwire-go/client"
+	msg "github.com/qbeon/webwire-go/message"
+)
+
+// recordingConn records all data read from the underlying connection
+type recordingConn struct {
+	net.Conn
+	lock     sync.Mutex
+	recorded bytes.Buffer
+}
+
+// Read implements the net.Conn interface
+func (conn *recordingConn) Read(buf []byte) (int, error) {
+	n, err := conn.Conn.Read(buf)
+	conn.lock.Lock()
+	conn.recorded.Write(buf[:n])
+	conn.lock.Unlock()
+	return n, err
+}
+
+// Recorded returns a copy of all data read so far
+func (conn *recordingConn) Recorded() []byte {
+	conn.lock.Lock()
+	defer conn.lock.Unlock()
+	return append([]byte(nil), conn.recorded.Bytes()...)
+}
+
+// compressedFrames parses the given raw server-to-client WebSocket stream
+// returning whether each of the contained data frames is compressed
+func compressedFrames(t *testing.T, raw []byte) []bool {
+	headerEnd := bytes.Index(raw, []byte("\r\n\r\n"))
+	require.True(t, headerEnd >= 0)
+	raw = raw[headerEnd+4:]
+
+	compressed := []bool{}
+	for len(raw) >= 2 {
+		rsv1 := raw[0]&0x40 != 0
+		length := int(raw[1] & 0x7f)
+		raw = raw[2:]
+		switch length {
+		case 126:
+			length = int(binary.BigEndian.Uint16(raw))
+			raw = raw[2:]
+		case 127:
+			length = int(binary.BigEndian.Uint64(raw))
+			raw = raw[8:]
+		}
+		require.True(t, len(raw) >= length)
+		compressed = append(compressed, rsv1)
+		raw = raw[length:]
+	}
+	return compressed
+}
+
+// TestCompressionThreshold tests whether only messages
+// exceeding the compression threshold are sent compressed
+func TestCompressionThreshold(t *testing.T) {
+	smallPayload := []byte("small uncompressed payload")
+	largePayload := bytes.Repeat([]byte("a"), 64*1024)
+
+	// Initialize webwire server
+	server := setupServer(
+		t,
+		&serverImpl{
+			onClientConnected: func(conn wwr.Connection) {
+				assert.NoError(t, conn.Signal("", wwr.NewPayload(
+					wwr.EncodingBinary,
+					smallPayload,
+				)))
+				assert.NoError(t, conn.Signal("", wwr.NewPayload(
+					wwr.EncodingBinary,
+					largePayload,
+				)))
+			},
+		},
+		wwr.ServerOptions{
+			CompressionThreshold: 1024,
+		},
+	)
+
+	// Connect a raw websocket negotiating compression
+	// and recording the raw data read from the network
+	var rawConn *recordingConn
+	dialer := websocket.Dialer{
+		EnableCompression: true,
+		NetDial: func(network, addr string) (net.Conn, error) {
+			conn, err := net.Dial(network, addr)
+			if err != nil {
+				return nil, err
+			}
+			rawConn = &recordingConn{Conn: conn}
+			return rawConn, nil
+		},
+	}
+	conn, _, err := dialer.Dial(
+		(&url.URL{Scheme: "ws", Host: server.Addr().String()}).String(),
+		nil,
+	)
+	require.NoError(t, err)
+	defer conn.Close()
+
+	// Expect both signals to be received intact
+	for _, expected := range [][]byte{smallPayload, largePayload} {
+		_, message, err := conn.ReadMessage()
+		require.NoError(t, err)
+		parsed := &msg.Message{}
+		_, err = parsed.Parse(message)
+		require.NoError(t, err)
+		require.Equal(t, expected, parsed.Payload.Data)
+	}
+
+	// Expect the small signal to be sent as is
+	// while the large one is expected to be compressed
+	require.Equal(
+		t,
+		[]bool{false, true},
+		compressedFrames(t, rawConn.Recorded()),
+	)
+}
+
+// TestCompressionThresholdClient tests whether clients
+// correctly receive both compressed and uncompressed replies
+func TestCompressionThresholdClient(t *testing.T) {
+	replies := map[string][]byte{
+		"small": []byte("small"),
+		"large": bytes.Repeat([]byte("large"), 1024),
+	}
+
+	// Initialize webwire server
+	server := setupServer(
+		t,
+		&serverImpl{
+			onRequest: func(
+				_ context.Context,
+				_ wwr.Connection,
+				message wwr.Message,
+			) (wwr.Payload, error) {
+				return wwr.NewPayload(
+					wwr.EncodingBinary,
+					replies[message.Name()],
+				), nil
+			},
+		},
+		wwr.ServerOptions{
+			CompressionThreshold: 64,
+		},
+	)
+
+	// Initialize client
+	client := newCallbackPoweredClient(
+		server.Addr().String(),
+		wwrclt.Options{
+			DefaultRequestTimeout: 2 * time.Second,
+		},
+		callbackPoweredClientHooks{},
+	)
+	defer client.connection.Close()
+
+	require.NoError(t, client.connection.Connect())
+
+	for _, name := range []string{"small", "large", "small", "large"} {
+		reply, err := client.connection.Request(
+			context.Background(),
+			name,
+			nil,
+		)
+		require.NoError(t, err)
+		require.Equal(t, replies[name], reply.Data())
+	}
+}
+
+// TestCompressReplies tests whether all outgoing messages are compressed
+// if reply compression is enabled while no threshold is defined
+// and whether uncompressed requests are still accepted
+func TestCompressReplies(t *testing.T) {
+	payload := []byte("tiny")
+
+	// Initialize webwire server echoing requests
+	server := setupServer(
+		t,
+		&serverImpl{
+			onClientConnected: func(conn wwr.Connection) {
+				assert.NoError(t, conn.Signal("", wwr.NewPayload(
+					wwr.EncodingBinary,
+					payload,
+				)))
+			},
+			onRequest: func(
+				_ context.Context,
+				_ wwr.Connection,
+				message wwr.Message,
+			) (wwr.Payload, error) {
+				return message.Payload(), nil
+			},
+		},
+		wwr.ServerOptions{
+			CompressReplies: true,
+		},
+	)
+
+	// Connect a raw websocket negotiating compression
+	// and recording the raw data read from the network
+	var rawConn *recordingConn
+	dialer := websocket.Dialer{
+		EnableCompression: true,
+		NetDial: func(network, addr string) (net.Conn, error) {
+			conn, err := net.Dial(network, addr)
+			if err != nil {
+				return nil, err
+			}
+			rawConn = &recordingConn{Conn: conn}
+			return rawConn, nil
+		},
+	}
+	conn, _, err := dialer.Dial(
+		(&url.URL{Scheme: "ws", Host: server.Addr().String()}).String(),
+		nil,
+	)
+	require.NoError(t, err)
+	defer conn.Close()
+
+	// Send an uncompressed request
+	conn.EnableWriteCompression(false)
+	require.NoError(t, conn.WriteMessage(
+		websocket.BinaryMessage,
+		msg.NewRequestMessage([8]byte{1}, "echo", wwr.EncodingBinary, payload),
+	))
+
+	// Expect both the signal and the reply to be received intact
+	for i := 0; i < 2; i++ {
+		_, message, err := conn.ReadMessage()
+		require.NoError(t, err)
+		parsed := &msg.Message{}
+		_, err = parsed.Parse(message)
+		require.NoError(t, err)
+		require.Equal(t, payload, parsed.Payload.Data)
+	}
+
+	// Expect both messages to be compressed
+	require.Equal(
+		t,
+		[]bool{true, true},
+		compressedFrames(t, rawConn.Recorded()),
+	)
+}
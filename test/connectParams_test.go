@@ -0,0 +1,51 @@
+package test
+
+import (
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	tmdwg "github.com/qbeon/tmdwg-go"
+	wwr "github.com/qbeon/webwire-go"
+)
+
+// TestConnectParams tests whether only the upgrade request URL query
+// parameters listed in the server options are accessible
+// through the connection
+func TestConnectParams(t *testing.T) {
+	connected := tmdwg.NewTimedWaitGroup(1, 1*time.Second)
+
+	// Initialize webwire server
+	server := setupServer(
+		t,
+		&serverImpl{
+			onClientConnected: func(conn wwr.Connection) {
+				assert.Equal(t, "123", conn.ConnectParam("room"))
+				assert.Equal(t, "dark", conn.ConnectParam("theme"))
+				assert.Equal(t, "", conn.ConnectParam("discarded"))
+				connected.Progress(1)
+			},
+		},
+		wwr.ServerOptions{
+			ConnectParams: []string{"room", "theme"},
+		},
+	)
+
+	// Connect a raw websocket providing query parameters
+	conn, _, err := websocket.DefaultDialer.Dial(
+		(&url.URL{
+			Scheme:   "ws",
+			Host:     server.Addr().String(),
+			RawQuery: "room=123&theme=dark&discarded=discarded",
+		}).String(),
+		nil,
+	)
+	require.NoError(t, err)
+	defer conn.Close()
+
+	require.NoError(t, connected.Wait())
+}
@@ -1,6 +1,7 @@
 package test
 
 import (
+	"context"
 	"testing"
 	"time"
 
@@ -27,6 +28,7 @@ func TestConnectionSessionGetters(t *testing.T) {
 				assert.Nil(t, conn.SessionInfo("some-number"))
 
 				assert.NoError(t, conn.CreateSession(
+					context.Background(),
 					&testAuthenticationSessInfo{
 						UserIdent:  "clientidentifiergoeshere", // uid
 						SomeNumber: 12345,                      // some-number
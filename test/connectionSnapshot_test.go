@@ -0,0 +1,65 @@
+package test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	wwr "github.com/qbeon/webwire-go"
+	wwrclt "github.com/qbeon/webwire-go/client"
+)
+
+// TestConnectionSnapshot tests the Connection.Snapshot method
+func TestConnectionSnapshot(t *testing.T) {
+	// Initialize webwire server
+	server := setupServer(
+		t,
+		&serverImpl{
+			onRequest: func(
+				ctx context.Context,
+				conn wwr.Connection,
+				_ wwr.Message,
+			) (wwr.Payload, error) {
+				// Verify the snapshot before the session is created
+				before := conn.Snapshot()
+				assert.Equal(t, conn.Info().RemoteAddr, before.RemoteAddr)
+				assert.Equal(t, conn.Info().UserAgent, before.UserAgent)
+				assert.Equal(
+					t,
+					conn.Info().ConnectionTime,
+					before.ConnectionTime,
+				)
+				assert.Equal(t, conn.LastActivity(), before.LastActivity)
+				assert.Equal(t, uint(1), before.InFlightRequests)
+				assert.Equal(t, "", before.SessionKey)
+				assert.True(t, before.SessionCreation.IsZero())
+
+				// Verify the snapshot after the session is created
+				assert.NoError(t, conn.CreateSession(ctx, nil))
+				after := conn.Snapshot()
+				assert.Equal(t, conn.SessionKey(), after.SessionKey)
+				assert.Equal(t, conn.SessionCreation(), after.SessionCreation)
+				assert.NotEqual(t, "", after.SessionKey)
+				return nil, nil
+			},
+		},
+		wwr.ServerOptions{},
+	)
+
+	// Initialize client
+	client := newCallbackPoweredClient(
+		server.Addr().String(),
+		wwrclt.Options{
+			DefaultRequestTimeout: 2 * time.Second,
+		},
+		callbackPoweredClientHooks{},
+	)
+	defer client.connection.Close()
+	require.NoError(t, client.connection.Connect())
+
+	_, err := client.connection.Request(context.Background(), "snapshot", nil)
+	require.NoError(t, err)
+}
@@ -0,0 +1,101 @@
+package test
+
+import (
+	"context"
+	"net/http"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	tmdwg "github.com/qbeon/tmdwg-go"
+	wwr "github.com/qbeon/webwire-go"
+	wwrclt "github.com/qbeon/webwire-go/client"
+)
+
+// TestConnectionTags tests whether the tags attached to connections
+// by the BeforeUpgrade hook are exposed on the connection
+// and whether signals broadcast to a tag are only sent
+// to the connections tagged with it
+func TestConnectionTags(t *testing.T) {
+	signalReceived := tmdwg.NewTimedWaitGroup(1, 1*time.Second)
+	var connections int32
+
+	// Initialize webwire server
+	server := setupServer(
+		t,
+		&serverImpl{
+			beforeUpgrade: func(
+				_ http.ResponseWriter,
+				_ *http.Request,
+			) wwr.ConnectionOptions {
+				// Tag the first connection as paid
+				if atomic.AddInt32(&connections, 1) == 1 {
+					return wwr.AcceptConnection(
+						wwr.UnlimitedConcurrency,
+						"web",
+						"paid",
+					)
+				}
+				return wwr.AcceptConnection(wwr.UnlimitedConcurrency, "web")
+			},
+			onRequest: func(
+				_ context.Context,
+				conn wwr.Connection,
+				_ wwr.Message,
+			) (wwr.Payload, error) {
+				assert.True(t, conn.HasTag("paid"))
+				assert.False(t, conn.HasTag("free"))
+				assert.Equal(t, []string{"paid", "web"}, conn.Tags())
+				return nil, nil
+			},
+		},
+		wwr.ServerOptions{},
+	)
+
+	// Initialize a paid client
+	paid := newCallbackPoweredClient(
+		server.Addr().String(),
+		wwrclt.Options{
+			DefaultRequestTimeout: 2 * time.Second,
+		},
+		callbackPoweredClientHooks{
+			OnSignal: func(msg wwr.Message) {
+				assert.Equal(t, "offer", msg.Name())
+				signalReceived.Progress(1)
+			},
+		},
+	)
+	defer paid.connection.Close()
+	require.NoError(t, paid.connection.Connect())
+
+	_, err := paid.connection.Request(context.Background(), "tags", nil)
+	require.NoError(t, err)
+
+	// Initialize another client without the paid tag
+	other := newCallbackPoweredClient(
+		server.Addr().String(),
+		wwrclt.Options{
+			DefaultRequestTimeout: 2 * time.Second,
+		},
+		callbackPoweredClientHooks{
+			OnSignal: func(msg wwr.Message) {
+				t.Errorf("unexpected signal %q", msg.Name())
+			},
+		},
+	)
+	defer other.connection.Close()
+	require.NoError(t, other.connection.Connect())
+
+	// Broadcast and expect only the paid client to receive the signal
+	signaled, err := server.BroadcastToTag(
+		"paid",
+		"offer",
+		wwr.NewPayload(wwr.EncodingUtf8, []byte("50%")),
+	)
+	require.NoError(t, err)
+	require.Equal(t, 1, signaled)
+	require.NoError(t, signalReceived.Wait())
+}
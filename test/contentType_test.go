@@ -0,0 +1,121 @@
+package test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	tmdwg "github.com/qbeon/tmdwg-go"
+	wwr "github.com/qbeon/webwire-go"
+	wwrclt "github.com/qbeon/webwire-go/client"
+	pld "github.com/qbeon/webwire-go/payload"
+)
+
+// TestContentType tests whether the content type of request, reply
+// and signal payloads is transmitted in both directions
+func TestContentType(t *testing.T) {
+	signalReceived := tmdwg.NewTimedWaitGroup(2, 1*time.Second)
+	requestPayload := wwr.NewTypedPayload(
+		"application/protobuf",
+		[]byte{1, 2, 3},
+	)
+	replyPayload := wwr.NewTypedPayload("image/png", []byte{4, 5, 6})
+
+	verifyPayload := func(expected, actual wwr.Payload) {
+		assert.Equal(
+			t,
+			expected.(wwr.ContentTyped).ContentType(),
+			actual.(wwr.ContentTyped).ContentType(),
+		)
+		assert.Equal(t, wwr.EncodingBinary, actual.Encoding())
+		assert.Equal(t, expected.Data(), actual.Data())
+	}
+
+	// Initialize webwire server
+	server := setupServer(
+		t,
+		&serverImpl{
+			onSignal: func(
+				_ context.Context,
+				conn wwr.Connection,
+				msg wwr.Message,
+			) {
+				verifyPayload(requestPayload, msg.Payload())
+				signalReceived.Progress(1)
+
+				// Signal the client back
+				assert.NoError(t, conn.Signal("test", replyPayload))
+			},
+			onRequest: func(
+				_ context.Context,
+				_ wwr.Connection,
+				msg wwr.Message,
+			) (wwr.Payload, error) {
+				verifyPayload(requestPayload, msg.Payload())
+				return replyPayload, nil
+			},
+		},
+		wwr.ServerOptions{},
+	)
+
+	// Initialize client
+	client := newCallbackPoweredClient(
+		server.Addr().String(),
+		wwrclt.Options{
+			DefaultRequestTimeout: 2 * time.Second,
+		},
+		callbackPoweredClientHooks{
+			OnSignal: func(msg wwr.Message) {
+				verifyPayload(replyPayload, msg.Payload())
+				signalReceived.Progress(1)
+			},
+		},
+	)
+	defer client.connection.Close()
+	require.NoError(t, client.connection.Connect())
+
+	// Send a request and verify the reply
+	reply, err := client.connection.Request(
+		context.Background(),
+		"test",
+		requestPayload,
+	)
+	require.NoError(t, err)
+	verifyPayload(replyPayload, reply)
+
+	// Send a signal and await the signal sent back
+	require.NoError(t, client.connection.Signal("test", requestPayload))
+	require.NoError(t, signalReceived.Wait())
+
+	// Payloads of invalid content types are rejected
+	_, err = client.connection.Request(
+		context.Background(),
+		"test",
+		wwr.NewTypedPayload("invalid\n", []byte{1}),
+	)
+	require.Error(t, err)
+	require.IsType(t, wwr.ProtocolErr{}, err)
+
+	// UTF8 and UTF16 encoded payloads of a content type are rejected
+	// instead of being sent as binary
+	for _, encoding := range []wwr.PayloadEncoding{
+		wwr.EncodingUtf8,
+		wwr.EncodingUtf16,
+	} {
+		_, err = client.connection.Request(
+			context.Background(),
+			"test",
+			&wwr.EncodedPayload{
+				Payload: pld.Payload{
+					Encoding:    encoding,
+					Data:        []byte("text"),
+					ContentType: "text/plain",
+				},
+			},
+		)
+		require.IsType(t, wwr.ProtocolErr{}, err)
+	}
+}
@@ -34,7 +34,7 @@ func TestCustomSessKeyGenInvalid(t *testing.T) {
 				}()
 
 				// Try to create a new session
-				err := conn.CreateSession(nil)
+				err := conn.CreateSession(context.Background(), nil)
 				assert.NoError(t, err)
 				return nil, err
 			},
@@ -27,7 +27,7 @@ func TestCustomSessKeyGen(t *testing.T) {
 				_ wwr.Message,
 			) (wwr.Payload, error) {
 				// Try to create a new session
-				err := conn.CreateSession(nil)
+				err := conn.CreateSession(context.Background(), nil)
 				assert.NoError(t, err)
 				if err != nil {
 					return nil, err
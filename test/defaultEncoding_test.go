@@ -0,0 +1,92 @@
+package test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	tmdwg "github.com/qbeon/tmdwg-go"
+	wwr "github.com/qbeon/webwire-go"
+	wwrclt "github.com/qbeon/webwire-go/client"
+)
+
+// TestDefaultEncoding tests whether replies and signals
+// with an unspecified payload encoding are sent
+// in the default encoding defined in the server options
+func TestDefaultEncoding(t *testing.T) {
+	signalReceived := tmdwg.NewTimedWaitGroup(1, 1*time.Second)
+
+	// Initialize webwire server using UTF8 as the default encoding
+	server := setupServer(
+		t,
+		&serverImpl{
+			onClientConnected: func(conn wwr.Connection) {
+				assert.NoError(t, conn.Signal("", wwr.NewPayload(
+					wwr.EncodingDefault,
+					[]byte("signal"),
+				)))
+			},
+			onRequest: func(
+				_ context.Context,
+				_ wwr.Connection,
+				message wwr.Message,
+			) (wwr.Payload, error) {
+				if message.Name() == "binary" {
+					return wwr.NewPayload(
+						wwr.EncodingBinary,
+						[]byte("binary"),
+					), nil
+				}
+				return wwr.NewPayload(
+					wwr.EncodingDefault,
+					[]byte("default"),
+				), nil
+			},
+		},
+		wwr.ServerOptions{
+			DefaultEncoding: wwr.EncodingUtf8,
+		},
+	)
+
+	// Initialize client
+	client := newCallbackPoweredClient(
+		server.Addr().String(),
+		wwrclt.Options{
+			DefaultRequestTimeout: 2 * time.Second,
+		},
+		callbackPoweredClientHooks{
+			OnSignal: func(message wwr.Message) {
+				assert.Equal(t, wwr.EncodingUtf8, message.Payload().Encoding())
+				assert.Equal(t, []byte("signal"), message.Payload().Data())
+				signalReceived.Progress(1)
+			},
+		},
+	)
+	defer client.connection.Close()
+
+	require.NoError(t, client.connection.Connect())
+	require.NoError(t, signalReceived.Wait())
+
+	// Expect replies of unspecified encoding to be UTF8 encoded
+	reply, err := client.connection.Request(
+		context.Background(),
+		"default",
+		nil,
+	)
+	require.NoError(t, err)
+	require.Equal(t, wwr.EncodingUtf8, reply.Encoding())
+	require.Equal(t, []byte("default"), reply.Data())
+
+	// Expect explicitly encoded replies to remain untouched
+	reply, err = client.connection.Request(
+		context.Background(),
+		"binary",
+		nil,
+	)
+	require.NoError(t, err)
+	require.Equal(t, wwr.EncodingBinary, reply.Encoding())
+	require.Equal(t, []byte("binary"), reply.Data())
+}
@@ -0,0 +1,76 @@
+package test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	tmdwg "github.com/qbeon/tmdwg-go"
+	wwr "github.com/qbeon/webwire-go"
+	wwrclt "github.com/qbeon/webwire-go/client"
+	msg "github.com/qbeon/webwire-go/message"
+)
+
+// TestDisabledMessageTypes tests the rejection of requests
+// when only signals are enabled
+func TestDisabledMessageTypes(t *testing.T) {
+	signalArrived := tmdwg.NewTimedWaitGroup(1, 1*time.Second)
+
+	// Initialize webwire server accepting signals only
+	server := setupServer(
+		t,
+		&serverImpl{
+			onSignal: func(
+				_ context.Context,
+				_ wwr.Connection,
+				_ wwr.Message,
+			) {
+				signalArrived.Progress(1)
+			},
+			onRequest: func(
+				_ context.Context,
+				_ wwr.Connection,
+				_ wwr.Message,
+			) (wwr.Payload, error) {
+				t.Errorf("OnRequest was not expected to be called")
+				return nil, nil
+			},
+		},
+		wwr.ServerOptions{
+			EnabledMessageTypes: map[byte]struct{}{
+				msg.MsgSignalBinary: {},
+				msg.MsgSignalUtf8:   {},
+				msg.MsgSignalUtf16:  {},
+			},
+		},
+	)
+
+	// Initialize client
+	client := newCallbackPoweredClient(
+		server.Addr().String(),
+		wwrclt.Options{
+			DefaultRequestTimeout: 2 * time.Second,
+		},
+		callbackPoweredClientHooks{},
+	)
+	defer client.connection.Close()
+
+	require.NoError(t, client.connection.Connect())
+
+	// Send request and expect it to be rejected
+	_, err := client.connection.Request(
+		context.Background(),
+		"test",
+		nil,
+	)
+	require.IsType(t, wwr.FeatureDisabledErr{}, err)
+
+	// Send signal and expect it to be handled
+	require.NoError(t, client.connection.Signal(
+		"test",
+		wwr.NewPayload(wwr.EncodingBinary, []byte("test")),
+	))
+	require.NoError(t, signalArrived.Wait())
+}
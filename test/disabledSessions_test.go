@@ -26,7 +26,7 @@ func TestDisabledSessions(t *testing.T) {
 				_ wwr.Message,
 			) (wwr.Payload, error) {
 				// Try to create a new session and expect an error
-				createErr := conn.CreateSession(nil)
+				createErr := conn.CreateSession(context.Background(), nil)
 				assert.IsType(t, wwr.SessionsDisabledErr{}, createErr)
 
 				// Try to create a new session and expect an error
@@ -0,0 +1,87 @@
+package test
+
+import (
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	tmdwg "github.com/qbeon/tmdwg-go"
+	wwr "github.com/qbeon/webwire-go"
+	wwrclt "github.com/qbeon/webwire-go/client"
+)
+
+// TestDisconnectReasonServerInitiated tests the disconnect reason
+// of connections closed by the server
+func TestDisconnectReasonServerInitiated(t *testing.T) {
+	disconnected := tmdwg.NewTimedWaitGroup(1, 1*time.Second)
+
+	// Initialize webwire server closing connections immediately
+	server := setupServer(
+		t,
+		&serverImpl{
+			onClientConnected: func(conn wwr.Connection) {
+				conn.Close()
+			},
+			onClientDisconnected: func(
+				_ wwr.Connection,
+				reason wwr.DisconnectReason,
+			) {
+				assert.Equal(t, wwr.DisconnectServerInitiated, reason)
+				disconnected.Progress(1)
+			},
+		},
+		wwr.ServerOptions{},
+	)
+
+	// Initialize client
+	client := newCallbackPoweredClient(
+		server.Addr().String(),
+		wwrclt.Options{
+			Autoconnect: wwr.Disabled,
+		},
+		callbackPoweredClientHooks{},
+	)
+	defer client.connection.Close()
+
+	require.NoError(t, client.connection.Connect())
+	require.NoError(t, disconnected.Wait())
+}
+
+// TestDisconnectReasonNormalClose tests the disconnect reason
+// of connections closed normally by the client
+func TestDisconnectReasonNormalClose(t *testing.T) {
+	disconnected := tmdwg.NewTimedWaitGroup(1, 1*time.Second)
+
+	// Initialize webwire server
+	server := setupServer(
+		t,
+		&serverImpl{
+			onClientDisconnected: func(
+				_ wwr.Connection,
+				reason wwr.DisconnectReason,
+			) {
+				assert.Equal(t, wwr.DisconnectNormalClose, reason)
+				disconnected.Progress(1)
+			},
+		},
+		wwr.ServerOptions{},
+	)
+
+	// Connect a raw websocket and close it with a normal close frame
+	conn, _, err := websocket.DefaultDialer.Dial(
+		(&url.URL{Scheme: "ws", Host: server.Addr().String()}).String(),
+		nil,
+	)
+	require.NoError(t, err)
+	defer conn.Close()
+
+	require.NoError(t, conn.WriteMessage(
+		websocket.CloseMessage,
+		websocket.FormatCloseMessage(websocket.CloseNormalClosure, ""),
+	))
+	require.NoError(t, disconnected.Wait())
+}
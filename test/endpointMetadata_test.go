@@ -13,7 +13,7 @@ import (
 
 // TestEndpointMetadata tests server endpoint metadata
 func TestEndpointMetadata(t *testing.T) {
-	expectedVersion := "1.4"
+	expectedVersion := "1.5"
 
 	// Initialize webwire server
 	server := setupServer(t, &serverImpl{}, wwr.ServerOptions{})
@@ -40,10 +40,16 @@ func TestEndpointMetadata(t *testing.T) {
 
 	// Unmarshal response
 	var metadata struct {
-		ProtocolVersion string `json:"protocol-version"`
+		ProtocolVersion string   `json:"protocol-version"`
+		Encodings       []string `json:"encodings"`
 	}
 	require.NoError(t, json.Unmarshal(encodedData, &metadata))
 
 	// Verify metadata
 	require.Equal(t, expectedVersion, metadata.ProtocolVersion)
+	require.Equal(t, []string{"binary", "utf8", "utf16"}, metadata.Encodings)
+
+	// Verify the reported version matches the one exposed by the server
+	require.Equal(t, expectedVersion, server.ProtocolVersion())
+	require.Equal(t, expectedVersion, wwr.ProtocolVersion())
 }
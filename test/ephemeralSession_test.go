@@ -0,0 +1,94 @@
+package test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	tmdwg "github.com/qbeon/tmdwg-go"
+	wwr "github.com/qbeon/webwire-go"
+	wwrclt "github.com/qbeon/webwire-go/client"
+)
+
+// TestEphemeralSession tests the creation of server-side only sessions
+// which are neither synchronized to the client nor passed
+// to the session manager
+func TestEphemeralSession(t *testing.T) {
+	disconnected := tmdwg.NewTimedWaitGroup(1, 1*time.Second)
+
+	// Initialize server
+	server := setupServer(
+		t,
+		&serverImpl{
+			onClientConnected: func(conn wwr.Connection) {
+				assert.NoError(t, conn.CreateEphemeralSession(
+					&testAuthenticationSessInfo{
+						UserIdent:  "clientidentifiergoeshere", // uid
+						SomeNumber: 12345,                      // some-number
+					},
+				))
+
+				// Expect the getters to work identically
+				assert.True(t, conn.HasSession())
+				assert.True(t, conn.Session().Ephemeral)
+				assert.NotEqual(t, "", conn.SessionKey())
+				assert.Equal(t, "clientidentifiergoeshere", conn.SessionInfo("uid"))
+				assert.Equal(t, 12345, conn.SessionInfo("some-number"))
+
+				// Expect regular sessions to be rejected
+				assert.Error(t, conn.CreateSession(context.Background(), nil))
+			},
+			onClientDisconnected: func(
+				_ wwr.Connection,
+				_ wwr.DisconnectReason,
+			) {
+				disconnected.Progress(1)
+			},
+			onRequest: func(
+				_ context.Context,
+				conn wwr.Connection,
+				_ wwr.Message,
+			) (wwr.Payload, error) {
+				assert.Equal(t, "clientidentifiergoeshere", conn.SessionInfo("uid"))
+				return nil, nil
+			},
+		},
+		wwr.ServerOptions{
+			SessionManager: &callbackPoweredSessionManager{
+				SessionCreated: func(_ context.Context, _ wwr.Connection) error {
+					t.Errorf("OnSessionCreated was not expected to be called")
+					return nil
+				},
+			},
+		},
+	)
+
+	// Initialize client
+	client := newCallbackPoweredClient(
+		server.Addr().String(),
+		wwrclt.Options{
+			Autoconnect:           wwr.Disabled,
+			DefaultRequestTimeout: 2 * time.Second,
+		},
+		callbackPoweredClientHooks{
+			OnSessionCreated: func(_ *wwr.Session) {
+				t.Errorf("OnSessionCreated was not expected to be called")
+			},
+		},
+	)
+
+	require.NoError(t, client.connection.Connect())
+
+	_, err := client.connection.Request(context.Background(), "test", nil)
+	require.NoError(t, err)
+	require.Nil(t, client.connection.Session())
+	require.Equal(t, 1, server.ActiveSessionsNum())
+
+	// Expect the ephemeral session to be dropped on disconnection
+	client.connection.Close()
+	require.NoError(t, disconnected.Wait())
+	require.Equal(t, 0, server.ActiveSessionsNum())
+}
@@ -0,0 +1,51 @@
+package test
+
+import (
+	"net/http"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	tmdwg "github.com/qbeon/tmdwg-go"
+	wwr "github.com/qbeon/webwire-go"
+)
+
+// TestForwardedHeaders tests whether only the upgrade request headers
+// listed in the server options are accessible through the connection
+func TestForwardedHeaders(t *testing.T) {
+	connected := tmdwg.NewTimedWaitGroup(1, 1*time.Second)
+
+	// Initialize webwire server
+	server := setupServer(
+		t,
+		&serverImpl{
+			onClientConnected: func(conn wwr.Connection) {
+				assert.Equal(t, "Bearer token", conn.Header("Authorization"))
+				assert.Equal(t, "custom", conn.Header("x-custom"))
+				assert.Equal(t, "", conn.Header("X-Discarded"))
+				connected.Progress(1)
+			},
+		},
+		wwr.ServerOptions{
+			ForwardedHeaders: []string{"Authorization", "X-Custom"},
+		},
+	)
+
+	// Connect a raw websocket providing custom headers
+	header := http.Header{}
+	header.Set("Authorization", "Bearer token")
+	header.Set("X-Custom", "custom")
+	header.Set("X-Discarded", "discarded")
+	conn, _, err := websocket.DefaultDialer.Dial(
+		(&url.URL{Scheme: "ws", Host: server.Addr().String()}).String(),
+		header,
+	)
+	require.NoError(t, err)
+	defer conn.Close()
+
+	require.NoError(t, connected.Wait())
+}
@@ -0,0 +1,267 @@
+package test
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	wwr "github.com/qbeon/webwire-go"
+	wwrclt "github.com/qbeon/webwire-go/client"
+)
+
+// TestGoroutineLimit tests whether the handling of incoming messages
+// is suspended while ServerOptions.MaxGoroutines is reached
+// and whether Server.ActiveGoroutines reflects the handler goroutines
+func TestGoroutineLimit(t *testing.T) {
+	entered := int32(0)
+	release := make(chan struct{})
+
+	// Initialize webwire server limiting the number of goroutines to 2
+	server := setupServer(
+		t,
+		&serverImpl{
+			onRequest: func(
+				_ context.Context,
+				_ wwr.Connection,
+				_ wwr.Message,
+			) (wwr.Payload, error) {
+				atomic.AddInt32(&entered, 1)
+				<-release
+				return nil, nil
+			},
+		},
+		wwr.ServerOptions{
+			MaxGoroutines: 2,
+		},
+	)
+
+	// Initialize client
+	client := newCallbackPoweredClient(
+		server.Addr().String(),
+		wwrclt.Options{
+			DefaultRequestTimeout: 2 * time.Second,
+		},
+		callbackPoweredClientHooks{},
+	)
+	defer client.connection.Close()
+	require.NoError(t, client.connection.Connect())
+
+	// Send 3 requests blocking their handlers
+	replied := make(chan struct{}, 3)
+	for i := 0; i < 3; i++ {
+		go func() {
+			_, err := client.connection.Request(
+				context.Background(),
+				"block",
+				nil,
+			)
+			assert.NoError(t, err)
+			replied <- struct{}{}
+		}()
+	}
+
+	// Expect only 2 handlers to be invoked
+	awaitCondition(t, func() bool {
+		return atomic.LoadInt32(&entered) == 2
+	})
+	time.Sleep(50 * time.Millisecond)
+	require.Equal(t, int32(2), atomic.LoadInt32(&entered))
+	require.Equal(t, 2, server.ActiveGoroutines())
+
+	// Expect the third handler to be invoked after the others returned
+	close(release)
+	for i := 0; i < 3; i++ {
+		<-replied
+	}
+	require.Equal(t, int32(3), atomic.LoadInt32(&entered))
+	awaitCondition(t, func() bool {
+		return server.ActiveGoroutines() == 0
+	})
+}
+
+// awaitCondition polls the given condition until it's met
+// failing the test if it isn't met within a second
+func awaitCondition(t *testing.T, condition func() bool) {
+	deadline := time.Now().Add(1 * time.Second)
+	for !condition() {
+		if time.Now().After(deadline) {
+			t.Fatal("condition not met in time")
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
+
+// TestGoroutineLimitHeartbeat tests whether goroutines living as long
+// as a connection, such as the heartbeat senders,
+// don't count towards ServerOptions.MaxGoroutines
+func TestGoroutineLimitHeartbeat(t *testing.T) {
+	// Initialize webwire server limiting the number of goroutines to 1
+	server := setupServer(
+		t,
+		&serverImpl{
+			onRequest: func(
+				_ context.Context,
+				_ wwr.Connection,
+				_ wwr.Message,
+			) (wwr.Payload, error) {
+				return nil, nil
+			},
+		},
+		wwr.ServerOptions{
+			MaxGoroutines: 1,
+			Heartbeat:     wwr.Enabled,
+		},
+	)
+
+	// Connect more clients than goroutines are allowed
+	clients := make([]*callbackPoweredClient, 2)
+	for i := range clients {
+		clients[i] = newCallbackPoweredClient(
+			server.Addr().String(),
+			wwrclt.Options{
+				DefaultRequestTimeout: 2 * time.Second,
+			},
+			callbackPoweredClientHooks{},
+		)
+		defer clients[i].connection.Close()
+		require.NoError(t, clients[i].connection.Connect())
+	}
+
+	// Expect the requests to be handled despite the heartbeat senders
+	for _, client := range clients {
+		_, err := client.connection.Request(
+			context.Background(),
+			"request",
+			nil,
+		)
+		require.NoError(t, err)
+	}
+}
+
+// TestGoroutineLimitConnectionClosed tests whether a connection awaiting
+// goroutine capacity stops being served when it's closed
+func TestGoroutineLimitConnectionClosed(t *testing.T) {
+	entered := int32(0)
+	disconnected := int32(0)
+	release := make(chan struct{})
+	defer close(release)
+	var blockedConn atomic.Value
+
+	// Initialize webwire server limiting the number of goroutines to 1
+	server := setupServer(
+		t,
+		&serverImpl{
+			onClientDisconnected: func(
+				_ wwr.Connection,
+				_ wwr.DisconnectReason,
+			) {
+				atomic.StoreInt32(&disconnected, 1)
+			},
+			onRequest: func(
+				_ context.Context,
+				conn wwr.Connection,
+				_ wwr.Message,
+			) (wwr.Payload, error) {
+				blockedConn.Store(conn)
+				atomic.AddInt32(&entered, 1)
+				<-release
+				return nil, nil
+			},
+		},
+		wwr.ServerOptions{
+			MaxGoroutines: 1,
+		},
+	)
+
+	client := newCallbackPoweredClient(
+		server.Addr().String(),
+		wwrclt.Options{
+			DefaultRequestTimeout: 2 * time.Second,
+		},
+		callbackPoweredClientHooks{},
+	)
+	defer client.connection.Close()
+	require.NoError(t, client.connection.Connect())
+
+	// Occupy the only goroutine and block the read loop
+	// of the connection awaiting goroutine capacity
+	go client.connection.Request(context.Background(), "block", nil)
+	awaitCondition(t, func() bool {
+		return atomic.LoadInt32(&entered) == 1
+	})
+	go client.connection.Request(context.Background(), "queued", nil)
+	time.Sleep(50 * time.Millisecond)
+
+	// Expect the connection to be disconnected
+	// even though the goroutine limit is still reached
+	blockedConn.Load().(wwr.Connection).Close()
+	awaitCondition(t, func() bool {
+		return atomic.LoadInt32(&disconnected) == 1
+	})
+}
+
+// TestGoroutineLimitReadTimeout tests whether clients aren't disconnected
+// as idle while their read loop awaits handler capacity
+func TestGoroutineLimitReadTimeout(t *testing.T) {
+	blocking := make(chan struct{})
+
+	// Initialize webwire server limiting the number of goroutines to 1
+	// and disconnecting clients idle for longer than 200 milliseconds
+	server := setupServer(
+		t,
+		&serverImpl{
+			onRequest: func(
+				_ context.Context,
+				_ wwr.Connection,
+				message wwr.Message,
+			) (wwr.Payload, error) {
+				if message.Name() == "block" {
+					// Hold the only handler slot for longer
+					// than the read timeout
+					close(blocking)
+					time.Sleep(400 * time.Millisecond)
+					return nil, nil
+				}
+				time.Sleep(100 * time.Millisecond)
+				return nil, nil
+			},
+		},
+		wwr.ServerOptions{
+			MaxGoroutines: 1,
+			ReadTimeout:   200 * time.Millisecond,
+		},
+	)
+
+	// Initialize clients
+	newClient := func() *callbackPoweredClient {
+		client := newCallbackPoweredClient(
+			server.Addr().String(),
+			wwrclt.Options{
+				DefaultRequestTimeout: 2 * time.Second,
+			},
+			callbackPoweredClientHooks{},
+		)
+		require.NoError(t, client.connection.Connect())
+		return client
+	}
+	blockingClient := newClient()
+	defer blockingClient.connection.Close()
+	waitingClient := newClient()
+	defer waitingClient.connection.Close()
+
+	go blockingClient.connection.Request(context.Background(), "block", nil)
+	<-blocking
+
+	// Expect the request of the waiting client to be handled
+	// once the blocking handler returned
+	_, err := waitingClient.connection.Request(
+		context.Background(),
+		"wait",
+		nil,
+	)
+	require.NoError(t, err)
+}
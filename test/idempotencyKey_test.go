@@ -0,0 +1,221 @@
+package test
+
+import (
+	"context"
+	"fmt"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	tmdwg "github.com/qbeon/tmdwg-go"
+	wwr "github.com/qbeon/webwire-go"
+	wwrclt "github.com/qbeon/webwire-go/client"
+)
+
+// TestIdempotencyKey tests whether requests carrying an already handled
+// idempotency key are replied to with the stored reply within the same
+// connection or session while the keys of other clients don't collide
+func TestIdempotencyKey(t *testing.T) {
+	var handled int32
+
+	// Initialize webwire server
+	server := setupServer(
+		t,
+		&serverImpl{
+			onRequest: func(
+				_ context.Context,
+				conn wwr.Connection,
+				msg wwr.Message,
+			) (wwr.Payload, error) {
+				if msg.Name() == "login" {
+					return nil, conn.CreateSession(context.Background(), nil)
+				}
+
+				// Verify the idempotency key was stripped from the name
+				assert.Contains(t, []string{"submit", "cancel"}, msg.Name())
+				count := atomic.AddInt32(&handled, 1)
+				return wwr.NewPayload(
+					wwr.EncodingUtf8,
+					[]byte(fmt.Sprintf("reply %d", count)),
+				), nil
+			},
+		},
+		wwr.ServerOptions{
+			IdempotencyStore: wwr.NewMemoryIdempotencyStore(1 * time.Minute),
+		},
+	)
+
+	connect := func() *callbackPoweredClient {
+		client := newCallbackPoweredClient(
+			server.Addr().String(),
+			wwrclt.Options{
+				DefaultRequestTimeout: 2 * time.Second,
+			},
+			callbackPoweredClientHooks{},
+		)
+		require.NoError(t, client.connection.Connect())
+		return client
+	}
+
+	request := func(client *callbackPoweredClient, name string) string {
+		reply, err := client.connection.Request(
+			context.Background(),
+			name,
+			nil,
+		)
+		require.NoError(t, err)
+		return string(reply.Data())
+	}
+
+	clientA := connect()
+	defer clientA.connection.Close()
+	clientB := connect()
+	defer clientB.connection.Close()
+
+	// Send the same idempotent request twice over the same connection
+	keyA := wwr.IdempotentRequestName("submit", "key-a")
+	require.Equal(t, "reply 1", request(clientA, keyA))
+	require.Equal(t, "reply 1", request(clientA, keyA))
+	require.Equal(t, int32(1), atomic.LoadInt32(&handled))
+
+	// Expect the same key sent by another client to be handled
+	require.Equal(t, "reply 2", request(clientB, keyA))
+
+	// Expect the same key sent with another request name to be handled
+	cancelA := wwr.IdempotentRequestName("cancel", "key-a")
+	require.Equal(t, "reply 3", request(clientA, cancelA))
+
+	// Expect requests carrying another key to be handled
+	keyB := wwr.IdempotentRequestName("submit", "key-b")
+	require.Equal(t, "reply 4", request(clientA, keyB))
+
+	// Expect requests without a key to always be handled
+	require.Equal(t, "reply 5", request(clientA, "submit"))
+	require.Equal(t, "reply 6", request(clientA, "submit"))
+
+	// Expect the stored reply to be replayed over another connection
+	// of the same session
+	request(clientA, "login")
+	keyS := wwr.IdempotentRequestName("submit", "key-s")
+	require.Equal(t, "reply 7", request(clientA, keyS))
+
+	clientC := connect()
+	defer clientC.connection.Close()
+	require.NoError(t, clientC.connection.RestoreSession(
+		[]byte(clientA.connection.Session().Key),
+	))
+	require.Equal(t, "reply 7", request(clientC, keyS))
+	require.Equal(t, int32(7), atomic.LoadInt32(&handled))
+}
+
+// TestIdempotencyKeyReplyCopied tests whether the stored reply
+// isn't affected by modifications of the reply data after the handler
+// returned it
+func TestIdempotencyKeyReplyCopied(t *testing.T) {
+	replyData := []byte("original")
+
+	// Initialize webwire server
+	server := setupServer(
+		t,
+		&serverImpl{
+			onRequest: func(
+				_ context.Context,
+				_ wwr.Connection,
+				_ wwr.Message,
+			) (wwr.Payload, error) {
+				return wwr.NewPayload(wwr.EncodingUtf8, replyData), nil
+			},
+		},
+		wwr.ServerOptions{
+			IdempotencyStore: wwr.NewMemoryIdempotencyStore(1 * time.Minute),
+		},
+	)
+
+	client := newCallbackPoweredClient(
+		server.Addr().String(),
+		wwrclt.Options{
+			DefaultRequestTimeout: 2 * time.Second,
+		},
+		callbackPoweredClientHooks{},
+	)
+	defer client.connection.Close()
+	require.NoError(t, client.connection.Connect())
+
+	name := wwr.IdempotentRequestName("submit", "key")
+	reply, err := client.connection.Request(context.Background(), name, nil)
+	require.NoError(t, err)
+	require.Equal(t, "original", string(reply.Data()))
+
+	// Reuse the reply buffer and expect the stored reply to be replayed
+	copy(replyData, "modified")
+	reply, err = client.connection.Request(context.Background(), name, nil)
+	require.NoError(t, err)
+	require.Equal(t, "original", string(reply.Data()))
+}
+
+// TestIdempotencyKeyReconnect tests whether the stored reply is replayed
+// after the client reconnected without a session
+func TestIdempotencyKeyReconnect(t *testing.T) {
+	var handled int32
+
+	// Initialize webwire server
+	server := setupServer(
+		t,
+		&serverImpl{
+			onRequest: func(
+				_ context.Context,
+				conn wwr.Connection,
+				msg wwr.Message,
+			) (wwr.Payload, error) {
+				if msg.Name() == "drop" {
+					conn.UnderlyingConn().(*websocket.Conn).Close()
+					return nil, nil
+				}
+				count := atomic.AddInt32(&handled, 1)
+				return wwr.NewPayload(
+					wwr.EncodingUtf8,
+					[]byte(fmt.Sprintf("reply %d", count)),
+				), nil
+			},
+		},
+		wwr.ServerOptions{
+			IdempotencyStore: wwr.NewMemoryIdempotencyStore(1 * time.Minute),
+		},
+	)
+
+	disconnected := tmdwg.NewTimedWaitGroup(1, 1*time.Second)
+	client := newCallbackPoweredClient(
+		server.Addr().String(),
+		wwrclt.Options{
+			DefaultRequestTimeout: 2 * time.Second,
+			ReconnectionInterval:  10 * time.Millisecond,
+		},
+		callbackPoweredClientHooks{
+			OnDisconnected: func() {
+				disconnected.Progress(1)
+			},
+		},
+	)
+	defer client.connection.Close()
+	require.NoError(t, client.connection.Connect())
+
+	name := wwr.IdempotentRequestName("submit", "key")
+	reply, err := client.connection.Request(context.Background(), name, nil)
+	require.NoError(t, err)
+	require.Equal(t, "reply 1", string(reply.Data()))
+
+	// Drop the connection and expect the stored reply to be replayed
+	// after the client reconnected
+	_, err = client.connection.Request(context.Background(), "drop", nil)
+	require.IsType(t, wwr.ConnectionLostErr{}, err)
+	require.NoError(t, disconnected.Wait())
+
+	reply, err = client.connection.Request(context.Background(), name, nil)
+	require.NoError(t, err)
+	require.Equal(t, "reply 1", string(reply.Data()))
+	require.Equal(t, int32(1), atomic.LoadInt32(&handled))
+}
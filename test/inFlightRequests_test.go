@@ -0,0 +1,75 @@
+package test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	tmdwg "github.com/qbeon/tmdwg-go"
+	wwr "github.com/qbeon/webwire-go"
+	wwrclt "github.com/qbeon/webwire-go/client"
+)
+
+// TestInFlightRequests tests the introspection of requests
+// currently processed by the OnRequest hook
+func TestInFlightRequests(t *testing.T) {
+	arrived := tmdwg.NewTimedWaitGroup(1, 1*time.Second)
+	finish := make(chan struct{})
+	finished := make(chan error, 1)
+	var requestConn wwr.Connection
+	var requestIdentifier [8]byte
+
+	// Initialize webwire server
+	server := setupServer(
+		t,
+		&serverImpl{
+			onRequest: func(
+				_ context.Context,
+				conn wwr.Connection,
+				msg wwr.Message,
+			) (wwr.Payload, error) {
+				requestConn = conn
+				requestIdentifier = msg.Identifier()
+				arrived.Progress(1)
+				<-finish
+				return nil, nil
+			},
+		},
+		wwr.ServerOptions{},
+	)
+
+	// Initialize client
+	client := newCallbackPoweredClient(
+		server.Addr().String(),
+		wwrclt.Options{
+			DefaultRequestTimeout: 2 * time.Second,
+		},
+		callbackPoweredClientHooks{},
+	)
+	defer client.connection.Close()
+
+	require.NoError(t, client.connection.Connect())
+	require.Len(t, server.InFlightRequests(), 0)
+
+	// Send a hanging request
+	beforeRequest := time.Now()
+	go func() {
+		_, err := client.connection.Request(context.Background(), "hang", nil)
+		finished <- err
+	}()
+	require.NoError(t, arrived.Wait())
+
+	inFlight := server.InFlightRequests()
+	require.Len(t, inFlight, 1)
+	require.Equal(t, "hang", inFlight[0].Name)
+	require.Equal(t, requestIdentifier, inFlight[0].Identifier)
+	require.Equal(t, requestConn, inFlight[0].Connection)
+	require.False(t, inFlight[0].StartTime.Before(beforeRequest))
+
+	// Finish the request and expect it to no longer be tracked
+	close(finish)
+	require.NoError(t, <-finished)
+	require.Len(t, server.InFlightRequests(), 0)
+}
@@ -0,0 +1,139 @@
+package test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	wwr "github.com/qbeon/webwire-go"
+	wwrclt "github.com/qbeon/webwire-go/client"
+	"github.com/qbeon/webwire-go/wwrtest"
+)
+
+// TestMaxActiveSessions tests whether the creation of sessions
+// exceeding ServerOptions.MaxActiveSessions fails
+func TestMaxActiveSessions(t *testing.T) {
+	created := make(chan error, 1)
+
+	// Initialize webwire server creating a session on each request
+	server := setupServer(
+		t,
+		&serverImpl{
+			onRequest: func(
+				ctx context.Context,
+				conn wwr.Connection,
+				_ wwr.Message,
+			) (wwr.Payload, error) {
+				created <- conn.CreateSession(ctx, nil)
+				return nil, nil
+			},
+		},
+		wwr.ServerOptions{
+			SessionManager:    wwrtest.NewInMemSessionManager(),
+			MaxActiveSessions: 2,
+		},
+	)
+
+	// createSession connects a new client and creates a session for it
+	createSession := func() (*callbackPoweredClient, error) {
+		client := newCallbackPoweredClient(
+			server.Addr().String(),
+			wwrclt.Options{
+				DefaultRequestTimeout: 2 * time.Second,
+			},
+			callbackPoweredClientHooks{},
+		)
+		require.NoError(t, client.connection.Connect())
+		_, err := client.connection.Request(
+			context.Background(),
+			"login",
+			nil,
+		)
+		require.NoError(t, err)
+		return client, <-created
+	}
+
+	// Create sessions up to the limit
+	first, err := createSession()
+	require.NoError(t, err)
+	defer first.connection.Close()
+
+	second, err := createSession()
+	require.NoError(t, err)
+	defer second.connection.Close()
+	require.Equal(t, 2, server.ActiveSessionsNum())
+
+	// Expect the creation of excess sessions to fail
+	third, err := createSession()
+	defer third.connection.Close()
+	require.IsType(t, wwr.MaxSessionsReachedErr{}, err)
+	require.Equal(t, 2, server.ActiveSessionsNum())
+
+	// Expect sessions to be creatable again after one was closed
+	require.NoError(t, first.connection.CloseSession())
+	_, err = third.connection.Request(context.Background(), "login", nil)
+	require.NoError(t, err)
+	require.NoError(t, <-created)
+	require.Equal(t, 2, server.ActiveSessionsNum())
+}
+
+// TestMaxActiveSessionsRestoration tests whether the restoration
+// of sessions without active connections fails
+// when ServerOptions.MaxActiveSessions is reached
+func TestMaxActiveSessionsRestoration(t *testing.T) {
+	// Initialize webwire server creating a session on each request
+	server := setupServer(
+		t,
+		&serverImpl{
+			onRequest: func(
+				ctx context.Context,
+				conn wwr.Connection,
+				_ wwr.Message,
+			) (wwr.Payload, error) {
+				return nil, conn.CreateSession(ctx, nil)
+			},
+		},
+		wwr.ServerOptions{
+			SessionManager:    wwrtest.NewInMemSessionManager(),
+			MaxActiveSessions: 1,
+		},
+	)
+
+	newClient := func() *callbackPoweredClient {
+		client := newCallbackPoweredClient(
+			server.Addr().String(),
+			wwrclt.Options{
+				DefaultRequestTimeout: 2 * time.Second,
+				Autoconnect:           wwr.Disabled,
+			},
+			callbackPoweredClientHooks{},
+		)
+		require.NoError(t, client.connection.Connect())
+		return client
+	}
+
+	// Create a session and close its only connection
+	first := newClient()
+	_, err := first.connection.Request(context.Background(), "login", nil)
+	require.NoError(t, err)
+	sessionKey := first.connection.Session().Key
+	first.connection.Close()
+	awaitCondition(t, func() bool { return server.ActiveSessionsNum() == 0 })
+
+	// Reach the limit by creating another session
+	second := newClient()
+	defer second.connection.Close()
+	_, err = second.connection.Request(context.Background(), "login", nil)
+	require.NoError(t, err)
+
+	// Expect the restoration of the inactive session to fail
+	third := newClient()
+	defer third.connection.Close()
+	err = third.connection.RestoreSession([]byte(sessionKey))
+	require.Error(t, err)
+	require.IsType(t, wwr.ReqErr{}, err)
+	require.Equal(t, "MAX_SESSIONS_REACHED", err.(wwr.ReqErr).Code)
+	require.Equal(t, 1, server.ActiveSessionsNum())
+}
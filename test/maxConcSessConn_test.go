@@ -1,6 +1,7 @@
 package test
 
 import (
+	"context"
 	"sync"
 	"testing"
 	"time"
@@ -32,7 +33,7 @@ func TestMaxConcSessConn(t *testing.T) {
 				sessionKeyLock.Lock()
 				defer sessionKeyLock.Unlock()
 				if len(sessionKey) < 1 {
-					assert.NoError(t, conn.CreateSession(nil))
+					assert.NoError(t, conn.CreateSession(context.Background(), nil))
 					sessionKey = conn.SessionKey()
 				}
 			},
@@ -41,13 +42,19 @@ func TestMaxConcSessConn(t *testing.T) {
 			MaxSessionConnections: concurrentConns,
 			SessionManager: &callbackPoweredSessionManager{
 				// Saves the session
-				SessionCreated: func(conn wwr.Connection) error {
+				SessionCreated: func(
+					_ context.Context,
+					conn wwr.Connection,
+				) error {
 					sess := conn.Session()
 					sessionStorage[sess.Key] = sess
 					return nil
 				},
 				// Finds session by key
-				SessionLookup: func(key string) (
+				SessionLookup: func(
+					_ context.Context,
+					key string,
+				) (
 					webwire.SessionLookupResult,
 					error,
 				) {
@@ -0,0 +1,72 @@
+package test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	tmdwg "github.com/qbeon/tmdwg-go"
+	wwr "github.com/qbeon/webwire-go"
+	wwrclt "github.com/qbeon/webwire-go/client"
+)
+
+// TestMaxInFlightRequests tests the rejection of requests exceeding
+// the maximum number of in-flight requests per connection
+func TestMaxInFlightRequests(t *testing.T) {
+	firstArrived := tmdwg.NewTimedWaitGroup(1, 1*time.Second)
+	finishFirst := make(chan struct{})
+	firstFinished := make(chan error, 1)
+
+	// Initialize webwire server limiting in-flight requests to 1
+	server := setupServer(
+		t,
+		&serverImpl{
+			onRequest: func(
+				_ context.Context,
+				_ wwr.Connection,
+				msg wwr.Message,
+			) (wwr.Payload, error) {
+				if msg.Name() == "block" {
+					firstArrived.Progress(1)
+					<-finishFirst
+				}
+				return nil, nil
+			},
+		},
+		wwr.ServerOptions{
+			MaxInFlightRequestsPerConn: 1,
+		},
+	)
+
+	// Initialize client
+	client := newCallbackPoweredClient(
+		server.Addr().String(),
+		wwrclt.Options{
+			DefaultRequestTimeout: 2 * time.Second,
+		},
+		callbackPoweredClientHooks{},
+	)
+	defer client.connection.Close()
+
+	require.NoError(t, client.connection.Connect())
+
+	// Occupy the only request slot
+	go func() {
+		_, err := client.connection.Request(context.Background(), "block", nil)
+		firstFinished <- err
+	}()
+	require.NoError(t, firstArrived.Wait())
+
+	// Expect excess requests to be rejected
+	_, err := client.connection.Request(context.Background(), "excess", nil)
+	require.IsType(t, wwr.TooManyRequestsErr{}, err)
+
+	// Free the slot and expect subsequent requests to succeed
+	close(finishFirst)
+	require.NoError(t, <-firstFinished)
+
+	_, err = client.connection.Request(context.Background(), "excess", nil)
+	require.NoError(t, err)
+}
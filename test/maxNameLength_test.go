@@ -0,0 +1,100 @@
+package test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	tmdwg "github.com/qbeon/tmdwg-go"
+	wwr "github.com/qbeon/webwire-go"
+	wwrclt "github.com/qbeon/webwire-go/client"
+)
+
+// TestMaxNameLength tests whether requests and signals of names exceeding
+// the maximum name length are rejected and whether outgoing signals
+// of such names fail
+func TestMaxNameLength(t *testing.T) {
+	connected := tmdwg.NewTimedWaitGroup(1, 1*time.Second)
+	var serverSideConn wwr.Connection
+	signalHandled := tmdwg.NewTimedWaitGroup(1, 1*time.Second)
+
+	// Initialize webwire server
+	server := setupServer(
+		t,
+		&serverImpl{
+			onClientConnected: func(conn wwr.Connection) {
+				serverSideConn = conn
+				connected.Progress(1)
+			},
+			onRequest: func(
+				_ context.Context,
+				_ wwr.Connection,
+				msg wwr.Message,
+			) (wwr.Payload, error) {
+				assert.Equal(t, "short", msg.Name())
+				return nil, nil
+			},
+			onSignal: func(
+				_ context.Context,
+				_ wwr.Connection,
+				msg wwr.Message,
+			) {
+				assert.Equal(t, "short", msg.Name())
+				signalHandled.Progress(1)
+			},
+		},
+		wwr.ServerOptions{
+			MaxNameLength:    5,
+			IdempotencyStore: wwr.NewMemoryIdempotencyStore(1 * time.Minute),
+		},
+	)
+
+	// Initialize client
+	client := newCallbackPoweredClient(
+		server.Addr().String(),
+		wwrclt.Options{
+			DefaultRequestTimeout: 2 * time.Second,
+		},
+		callbackPoweredClientHooks{},
+	)
+	defer client.connection.Close()
+	require.NoError(t, client.connection.Connect())
+	require.NoError(t, connected.Wait())
+
+	// Expect requests of names exceeding the maximum length to be rejected
+	_, err := client.connection.Request(context.Background(), "toolong", nil)
+	require.Error(t, err)
+	require.IsType(t, wwr.ProtocolErr{}, err)
+
+	_, err = client.connection.Request(context.Background(), "short", nil)
+	require.NoError(t, err)
+
+	// Expect idempotency keys not to count towards the length
+	_, err = client.connection.Request(
+		context.Background(),
+		wwr.IdempotentRequestName("short", "key"),
+		nil,
+	)
+	require.NoError(t, err)
+
+	// Expect signals of names exceeding the maximum length to be dropped,
+	// the signal handler verifies the name of each handled signal
+	require.NoError(t, client.connection.Signal(
+		"toolong",
+		wwr.NewPayload(wwr.EncodingBinary, []byte("data")),
+	))
+	require.NoError(t, client.connection.Signal(
+		"short",
+		wwr.NewPayload(wwr.EncodingBinary, []byte("data")),
+	))
+	require.NoError(t, signalHandled.Wait())
+
+	// Expect outgoing signals of names exceeding the maximum length to fail
+	require.Error(t, serverSideConn.Signal(
+		"toolong",
+		wwr.NewPayload(wwr.EncodingBinary, []byte("data")),
+	))
+}
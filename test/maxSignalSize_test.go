@@ -0,0 +1,75 @@
+package test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	tmdwg "github.com/qbeon/tmdwg-go"
+	wwr "github.com/qbeon/webwire-go"
+	wwrclt "github.com/qbeon/webwire-go/client"
+)
+
+// TestMaxSignalSize tests whether signals exceeding the maximum signal size
+// are dropped while requests of the same size are handled
+func TestMaxSignalSize(t *testing.T) {
+	signalReceived := tmdwg.NewTimedWaitGroup(1, 1*time.Second)
+	bigPayload := wwr.NewPayload(wwr.EncodingBinary, make([]byte, 16))
+
+	// Initialize webwire server
+	server := setupServer(
+		t,
+		&serverImpl{
+			onSignal: func(
+				_ context.Context,
+				_ wwr.Connection,
+				msg wwr.Message,
+			) {
+				assert.Equal(t, "small", msg.Name())
+				signalReceived.Progress(1)
+			},
+			onRequest: func(
+				_ context.Context,
+				_ wwr.Connection,
+				msg wwr.Message,
+			) (wwr.Payload, error) {
+				return msg.Payload(), nil
+			},
+		},
+		wwr.ServerOptions{
+			MaxSignalSize: 8,
+		},
+	)
+
+	// Initialize client
+	client := newCallbackPoweredClient(
+		server.Addr().String(),
+		wwrclt.Options{
+			DefaultRequestTimeout: 2 * time.Second,
+		},
+		callbackPoweredClientHooks{},
+	)
+	defer client.connection.Close()
+	require.NoError(t, client.connection.Connect())
+
+	// Send an oversized signal expected to be dropped
+	// followed by a signal of the maximum size
+	require.NoError(t, client.connection.Signal("big", bigPayload))
+	require.NoError(t, client.connection.Signal(
+		"small",
+		wwr.NewPayload(wwr.EncodingBinary, make([]byte, 8)),
+	))
+	require.NoError(t, signalReceived.Wait())
+
+	// Requests aren't affected by the maximum signal size
+	reply, err := client.connection.Request(
+		context.Background(),
+		"big",
+		bigPayload,
+	)
+	require.NoError(t, err)
+	comparePayload(t, bigPayload, reply)
+}
@@ -0,0 +1,76 @@
+package test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/stretchr/testify/require"
+
+	wwr "github.com/qbeon/webwire-go"
+	msg "github.com/qbeon/webwire-go/message"
+)
+
+// TestMessageValidation tests whether semantically invalid messages
+// are rejected before they're dispatched
+func TestMessageValidation(t *testing.T) {
+	// Initialize webwire server
+	server := setupServer(
+		t,
+		&serverImpl{},
+		wwr.ServerOptions{},
+	)
+
+	conn := dialRaw(t, server)
+	defer conn.Close()
+
+	// readReplyType reads from the raw websocket client until
+	// the reply to the request of the given identifier is received
+	// and returns its message type
+	readReplyType := func(identifier byte) byte {
+		require.NoError(t, conn.SetReadDeadline(
+			time.Now().Add(2*time.Second),
+		))
+		for {
+			_, message, err := conn.ReadMessage()
+			require.NoError(t, err)
+			if len(message) > 1 && message[1] == identifier {
+				return message[0]
+			}
+		}
+	}
+
+	// Expect session restoration requests
+	// of invalid session keys to fail
+	require.NoError(t, conn.WriteMessage(
+		websocket.BinaryMessage,
+		append(
+			[]byte{msg.MsgRestoreSession, 1, 0, 0, 0, 0, 0, 0, 0},
+			[]byte("invalid\nkey")...,
+		),
+	))
+	require.Equal(t, msg.MsgSessionNotFound, readReplyType(1))
+
+	// Expect content-typed requests of invalid content types to fail
+	require.NoError(t, conn.WriteMessage(
+		websocket.BinaryMessage,
+		[]byte{
+			msg.MsgRequestTyped, 2, 0, 0, 0, 0, 0, 0, 0,
+			1, 'r', // name
+			1, 0, // content type
+			'p', // payload
+		},
+	))
+	require.Equal(t, msg.MsgReplyProtocolError, readReplyType(2))
+
+	// Expect content-typed requests of neither a name nor a payload to fail
+	require.NoError(t, conn.WriteMessage(
+		websocket.BinaryMessage,
+		[]byte{
+			msg.MsgRequestTyped, 3, 0, 0, 0, 0, 0, 0, 0,
+			0,      // name
+			1, 'a', // content type
+		},
+	))
+	require.Equal(t, msg.MsgReplyProtocolError, readReplyType(3))
+}
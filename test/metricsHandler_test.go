@@ -0,0 +1,83 @@
+package test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	wwr "github.com/qbeon/webwire-go"
+	wwrclt "github.com/qbeon/webwire-go/client"
+	"github.com/qbeon/webwire-go/wwrprom"
+	"github.com/qbeon/webwire-go/wwrtest"
+)
+
+// TestMetricsHandler tests exposing the live counters of the server
+// in the Prometheus text exposition format
+func TestMetricsHandler(t *testing.T) {
+	// Initialize webwire server
+	server := setupServer(
+		t,
+		&serverImpl{
+			onRequest: func(
+				_ context.Context,
+				conn wwr.Connection,
+				msg wwr.Message,
+			) (wwr.Payload, error) {
+				if msg.Name() == "fail" {
+					return nil, wwr.ReqErr{Code: "FAILED"}
+				}
+				err := conn.CreateSession(context.Background(), nil)
+				assert.NoError(t, err)
+				return nil, err
+			},
+		},
+		wwr.ServerOptions{
+			SessionManager: wwrtest.NewInMemSessionManager(),
+		},
+	)
+
+	// Initialize client
+	client := newCallbackPoweredClient(
+		server.Addr().String(),
+		wwrclt.Options{
+			DefaultRequestTimeout: 2 * time.Second,
+		},
+		callbackPoweredClientHooks{},
+	)
+	defer client.connection.Close()
+	require.NoError(t, client.connection.Connect())
+
+	// Send a succeeding and a failing request
+	_, err := client.connection.Request(context.Background(), "login", nil)
+	require.NoError(t, err)
+	_, err = client.connection.Request(context.Background(), "fail", nil)
+	require.Error(t, err)
+
+	metrics := server.Metrics()
+	require.Equal(t, uint64(2), metrics.RequestsTotal)
+	require.Equal(t, uint64(1), metrics.RequestsFailed)
+	require.Equal(t, 1, metrics.ActiveConnections)
+	require.Equal(t, 1, metrics.ActiveSessions)
+
+	// Scrape the metrics
+	recorder := httptest.NewRecorder()
+	wwrprom.Handler(server).ServeHTTP(
+		recorder,
+		httptest.NewRequest(http.MethodGet, "/metrics", nil),
+	)
+	require.Equal(t, http.StatusOK, recorder.Code)
+	require.Contains(t, recorder.Header().Get("Content-Type"), "text/plain")
+
+	lines := strings.Split(recorder.Body.String(), "\n")
+	require.Contains(t, lines, "webwire_requests_total 2")
+	require.Contains(t, lines, "webwire_requests_failed_total 1")
+	require.Contains(t, lines, "webwire_request_duration_seconds_count 2")
+	require.Contains(t, lines, "webwire_active_connections 1")
+	require.Contains(t, lines, "webwire_active_sessions 1")
+}
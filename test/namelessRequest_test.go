@@ -0,0 +1,62 @@
+package test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	wwr "github.com/qbeon/webwire-go"
+	webwireClient "github.com/qbeon/webwire-go/client"
+)
+
+// TestNamelessRequest tests whether nameless requests are passed
+// to the request handler with an empty name
+// and are accepted if the empty name is an allowed name
+func TestNamelessRequest(t *testing.T) {
+	// Initialize webwire server
+	server := setupServer(
+		t,
+		&serverImpl{
+			onRequest: func(
+				_ context.Context,
+				_ wwr.Connection,
+				msg wwr.Message,
+			) (wwr.Payload, error) {
+				assert.Equal(t, "", msg.Name())
+
+				// Echo the payload
+				return msg.Payload(), nil
+			},
+		},
+		wwr.ServerOptions{
+			AllowedNames: []string{""},
+		},
+	)
+
+	// Initialize client
+	client := newCallbackPoweredClient(
+		server.Addr().String(),
+		webwireClient.Options{
+			DefaultRequestTimeout: 2 * time.Second,
+		},
+		callbackPoweredClientHooks{},
+	)
+	require.NoError(t, client.connection.Connect())
+
+	// Send nameless request and await reply
+	reply, err := client.connection.Request(
+		context.Background(),
+		"",
+		wwr.NewPayload(wwr.EncodingUtf8, []byte("nameless")),
+	)
+	require.NoError(t, err)
+	require.Equal(t, wwr.EncodingUtf8, reply.Encoding())
+	require.Equal(t, []byte("nameless"), reply.Data())
+
+	// Expect requests without both a name and a payload to be rejected
+	_, err = client.connection.Request(context.Background(), "", nil)
+	require.IsType(t, wwr.ProtocolErr{}, err)
+}
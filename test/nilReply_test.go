@@ -0,0 +1,128 @@
+package test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/stretchr/testify/require"
+
+	wwr "github.com/qbeon/webwire-go"
+	webwireClient "github.com/qbeon/webwire-go/client"
+	msg "github.com/qbeon/webwire-go/message"
+	pld "github.com/qbeon/webwire-go/payload"
+)
+
+// setupNilReplyServer sets up a server replying to all requests
+// with a nil payload and a nil error
+func setupNilReplyServer(
+	t *testing.T,
+	defaultEncoding wwr.PayloadEncoding,
+) wwr.Server {
+	return setupServer(
+		t,
+		&serverImpl{
+			onRequest: func(
+				_ context.Context,
+				_ wwr.Connection,
+				_ wwr.Message,
+			) (wwr.Payload, error) {
+				return nil, nil
+			},
+		},
+		wwr.ServerOptions{
+			DefaultEncoding: defaultEncoding,
+		},
+	)
+}
+
+// testNilReply tests whether the client receives an empty reply
+// of the given default encoding when the request handler returns
+// a nil payload and a nil error
+func testNilReply(
+	t *testing.T,
+	defaultEncoding wwr.PayloadEncoding,
+	expectedEncoding wwr.PayloadEncoding,
+) {
+	server := setupNilReplyServer(t, defaultEncoding)
+
+	// Initialize client
+	client := newCallbackPoweredClient(
+		server.Addr().String(),
+		webwireClient.Options{
+			DefaultRequestTimeout: 2 * time.Second,
+		},
+		callbackPoweredClientHooks{},
+	)
+
+	require.NoError(t, client.connection.Connect())
+
+	// Send request and await reply
+	reply, err := client.connection.Request(
+		context.Background(),
+		"test",
+		nil,
+	)
+	require.NoError(t, err)
+
+	// Verify reply is empty
+	require.Equal(t, expectedEncoding, reply.Encoding())
+	require.Len(t, reply.Data(), 0)
+}
+
+// TestNilReply tests whether nil replies are binary encoded by default
+func TestNilReply(t *testing.T) {
+	testNilReply(t, wwr.EncodingDefault, wwr.EncodingBinary)
+}
+
+// TestNilReplyUtf8 tests whether nil replies are encoded
+// in the UTF8 default encoding
+func TestNilReplyUtf8(t *testing.T) {
+	testNilReply(t, wwr.EncodingUtf8, wwr.EncodingUtf8)
+}
+
+// TestNilReplyUtf16 tests whether nil replies are encoded
+// in the UTF16 default encoding
+func TestNilReplyUtf16(t *testing.T) {
+	testNilReply(t, wwr.EncodingUtf16, wwr.EncodingUtf16)
+}
+
+// TestNilReplyFrame tests whether the frame of a nil reply
+// is a well-formed empty reply message
+func TestNilReplyFrame(t *testing.T) {
+	server := setupNilReplyServer(t, wwr.EncodingDefault)
+
+	conn := dialRaw(t, server)
+	defer conn.Close()
+
+	identifier := [8]byte{1, 2, 3, 4, 5, 6, 7, 8}
+	require.NoError(t, conn.WriteMessage(
+		websocket.BinaryMessage,
+		msg.NewRequestMessage(identifier, "test", pld.Binary, nil),
+	))
+
+	// Await the reply skipping any other messages
+	require.NoError(t, conn.SetReadDeadline(time.Now().Add(2*time.Second)))
+	for {
+		_, message, err := conn.ReadMessage()
+		require.NoError(t, err)
+		if message[0] != msg.MsgReplyBinary {
+			continue
+		}
+		require.Equal(
+			t,
+			msg.NewReplyMessage(identifier, pld.Binary, nil),
+			message,
+		)
+
+		// Verify the reply parses into an empty binary reply
+		var parsed msg.Message
+		typeDetermined, err := parsed.Parse(message)
+		require.True(t, typeDetermined)
+		require.NoError(t, err)
+		require.Equal(t, pld.Binary, parsed.Payload.Encoding)
+		require.Len(t, parsed.Payload.Data, 0)
+		return
+	}
+}
@@ -0,0 +1,94 @@
+package test
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	tmdwg "github.com/qbeon/tmdwg-go"
+	wwr "github.com/qbeon/webwire-go"
+	wwrclt "github.com/qbeon/webwire-go/client"
+)
+
+// TestOnMessage tests the OnMessage server hook
+// inspecting and vetoing incoming messages before they're dispatched
+func TestOnMessage(t *testing.T) {
+	inspectedLock := sync.Mutex{}
+	inspected := []string{}
+	allowedSignalHandled := tmdwg.NewTimedWaitGroup(1, 1*time.Second)
+	allInspected := tmdwg.NewTimedWaitGroup(4, 1*time.Second)
+
+	// Initialize webwire server
+	server := setupServer(
+		t,
+		&serverImpl{
+			onSignal: func(
+				_ context.Context,
+				_ wwr.Connection,
+				message wwr.Message,
+			) {
+				assert.Equal(t, "allowed", message.Name())
+				allowedSignalHandled.Progress(1)
+			},
+			onRequest: func(
+				_ context.Context,
+				_ wwr.Connection,
+				message wwr.Message,
+			) (wwr.Payload, error) {
+				assert.Equal(t, "allowed", message.Name())
+				return nil, nil
+			},
+		},
+		wwr.ServerOptions{
+			OnMessage: func(_ wwr.Connection, message wwr.Message) error {
+				inspectedLock.Lock()
+				inspected = append(inspected, message.Name())
+				inspectedLock.Unlock()
+				allInspected.Progress(1)
+				if message.Name() == "vetoed" {
+					return wwr.ReqErr{Code: "VETOED", Message: "vetoed"}
+				}
+				return nil
+			},
+		},
+	)
+
+	// Initialize client
+	client := newCallbackPoweredClient(
+		server.Addr().String(),
+		wwrclt.Options{
+			DefaultRequestTimeout: 2 * time.Second,
+		},
+		callbackPoweredClientHooks{},
+	)
+	defer client.connection.Close()
+
+	require.NoError(t, client.connection.Connect())
+
+	payload := wwr.NewPayload(wwr.EncodingBinary, []byte("payload"))
+
+	// Expect vetoed requests to be failed with the returned error
+	_, err := client.connection.Request(context.Background(), "vetoed", nil)
+	require.Equal(t, wwr.ReqErr{Code: "VETOED", Message: "vetoed"}, err)
+
+	_, err = client.connection.Request(context.Background(), "allowed", nil)
+	require.NoError(t, err)
+
+	// Expect vetoed signals to be dropped
+	require.NoError(t, client.connection.Signal("vetoed", payload))
+	require.NoError(t, client.connection.Signal("allowed", payload))
+	require.NoError(t, allowedSignalHandled.Wait())
+	require.NoError(t, allInspected.Wait())
+
+	inspectedLock.Lock()
+	defer inspectedLock.Unlock()
+	require.ElementsMatch(
+		t,
+		[]string{"vetoed", "allowed", "vetoed", "allowed"},
+		inspected,
+	)
+}
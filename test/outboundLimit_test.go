@@ -0,0 +1,87 @@
+package test
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	tmdwg "github.com/qbeon/tmdwg-go"
+	wwr "github.com/qbeon/webwire-go"
+	wwrclt "github.com/qbeon/webwire-go/client"
+)
+
+// TestOutboundLimitDisconnectSlowest tests whether a client not reading
+// the messages sent to it is disconnected when the total number of pending
+// outbound bytes exceeds ServerOptions.MaxPendingOutboundBytes
+// while other clients remain connected
+func TestOutboundLimitDisconnectSlowest(t *testing.T) {
+	connected := make(chan wwr.Connection, 2)
+	disconnected := tmdwg.NewTimedWaitGroup(1, 5*time.Second)
+
+	// Initialize webwire server
+	server := setupServer(
+		t,
+		&serverImpl{
+			onClientConnected: func(conn wwr.Connection) {
+				connected <- conn
+			},
+			onClientDisconnected: func(
+				_ wwr.Connection,
+				reason wwr.DisconnectReason,
+			) {
+				if reason == wwr.DisconnectSlowConsumer {
+					disconnected.Progress(1)
+				}
+			},
+			onRequest: func(
+				_ context.Context,
+				_ wwr.Connection,
+				_ wwr.Message,
+			) (wwr.Payload, error) {
+				return nil, nil
+			},
+		},
+		wwr.ServerOptions{
+			MaxPendingOutboundBytes: 1024 * 1024,
+		},
+	)
+
+	// Initialize a well-behaved client
+	client := newCallbackPoweredClient(
+		server.Addr().String(),
+		wwrclt.Options{
+			DefaultRequestTimeout: 2 * time.Second,
+		},
+		callbackPoweredClientHooks{},
+	)
+	defer client.connection.Close()
+	require.NoError(t, client.connection.Connect())
+	<-connected
+
+	// Connect a slow client never reading any messages
+	slow := dialRaw(t, server)
+	defer slow.Close()
+	slowConn := <-connected
+
+	// Flood the slow client with signals until it's disconnected
+	payload := wwr.NewPayload(wwr.EncodingBinary, make([]byte, 256*1024))
+	var senders sync.WaitGroup
+	for i := 0; i < 64; i++ {
+		senders.Add(1)
+		go func() {
+			defer senders.Done()
+			slowConn.Signal("flood", payload)
+		}()
+	}
+	require.NoError(t, disconnected.Wait())
+	senders.Wait()
+
+	// Expect the pending outbound bytes to be released
+	// and the well-behaved client to remain connected
+	require.Equal(t, int64(0), server.PendingOutboundBytes())
+	_, err := client.connection.Request(context.Background(), "ping", nil)
+	require.NoError(t, err)
+}
@@ -0,0 +1,67 @@
+package test
+
+import (
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/stretchr/testify/require"
+
+	tmdwg "github.com/qbeon/tmdwg-go"
+	wwr "github.com/qbeon/webwire-go"
+)
+
+// TestOutboundQueueLen tests whether signals piling up for a client
+// that doesn't read are reflected by Connection.OutboundQueueLen
+func TestOutboundQueueLen(t *testing.T) {
+	connected := tmdwg.NewTimedWaitGroup(1, 1*time.Second)
+	var serverSideConn wwr.Connection
+
+	// Initialize webwire server
+	server := setupServer(
+		t,
+		&serverImpl{
+			onClientConnected: func(conn wwr.Connection) {
+				serverSideConn = conn
+				connected.Progress(1)
+			},
+		},
+		wwr.ServerOptions{},
+	)
+
+	// Connect a raw websocket client that never reads
+	endpointURL := url.URL{
+		Scheme: "ws",
+		Host:   server.Addr().String(),
+		Path:   "/",
+	}
+	conn, _, err := websocket.DefaultDialer.Dial(endpointURL.String(), nil)
+	require.NoError(t, err)
+	defer conn.Close()
+	require.NoError(t, connected.Wait())
+
+	require.Equal(t, 0, serverSideConn.OutboundQueueLen())
+
+	// Signal large payloads concurrently until the socket buffers are full
+	// and the signals start piling up
+	payload := wwr.NewPayload(wwr.EncodingBinary, make([]byte, 4*1024*1024))
+	for i := 0; i < 8; i++ {
+		go serverSideConn.Signal("test", payload)
+	}
+
+	deadline := time.Now().Add(2 * time.Second)
+	for serverSideConn.OutboundQueueLen() < 2 {
+		require.True(t, time.Now().Before(deadline), "signals didn't pile up")
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	// Disconnecting the client fails the pending signals
+	// and drains the queue
+	require.NoError(t, conn.Close())
+	deadline = time.Now().Add(2 * time.Second)
+	for serverSideConn.OutboundQueueLen() > 0 {
+		require.True(t, time.Now().Before(deadline), "queue wasn't drained")
+		time.Sleep(10 * time.Millisecond)
+	}
+}
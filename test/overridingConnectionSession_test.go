@@ -1,6 +1,7 @@
 package test
 
 import (
+	"context"
 	"testing"
 	"time"
 
@@ -18,11 +19,11 @@ func TestOverridingConnectionSession(t *testing.T) {
 		t,
 		&serverImpl{
 			onClientConnected: func(conn wwr.Connection) {
-				assert.NoError(t, conn.CreateSession(nil))
+				assert.NoError(t, conn.CreateSession(context.Background(), nil))
 				sessionKey := conn.SessionKey()
 
 				// Try to override the previous session
-				assert.Error(t, conn.CreateSession(nil))
+				assert.Error(t, conn.CreateSession(context.Background(), nil))
 
 				// Ensure the session didn't change
 				assert.Equal(t, sessionKey, conn.SessionKey())
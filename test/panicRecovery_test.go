@@ -0,0 +1,91 @@
+package test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	tmdwg "github.com/qbeon/tmdwg-go"
+	wwr "github.com/qbeon/webwire-go"
+	wwrclt "github.com/qbeon/webwire-go/client"
+)
+
+// TestPanicRecoveryRequestHandler tests whether a panicking request handler
+// fails the request and closes the connection without crashing the server
+func TestPanicRecoveryRequestHandler(t *testing.T) {
+	disconnected := tmdwg.NewTimedWaitGroup(1, 1*time.Second)
+
+	// Initialize webwire server
+	server := setupServer(
+		t,
+		&serverImpl{
+			onRequest: func(
+				_ context.Context,
+				_ wwr.Connection,
+				msg wwr.Message,
+			) (wwr.Payload, error) {
+				if msg.Name() == "panic" {
+					panic("request handler bug")
+				}
+				return nil, nil
+			},
+			onClientDisconnected: func(
+				_ wwr.Connection,
+				reason wwr.DisconnectReason,
+			) {
+				assert.Equal(t, wwr.DisconnectInternalError, reason)
+				disconnected.Progress(1)
+			},
+		},
+		wwr.ServerOptions{},
+	)
+
+	// Initialize client
+	client := newCallbackPoweredClient(
+		server.Addr().String(),
+		wwrclt.Options{
+			DefaultRequestTimeout: 2 * time.Second,
+			Autoconnect:           wwr.Disabled,
+		},
+		callbackPoweredClientHooks{},
+	)
+	defer client.connection.Close()
+	require.NoError(t, client.connection.Connect())
+
+	_, err := client.connection.Request(context.Background(), "panic", nil)
+	require.Error(t, err)
+	require.IsType(t, wwr.ReqInternalErr{}, err)
+	require.NoError(t, disconnected.Wait())
+	require.Empty(t, server.InFlightRequests())
+}
+
+// TestPanicRecoveryConnectedHook tests whether a panicking
+// OnClientConnected hook closes the connection reporting an internal error
+func TestPanicRecoveryConnectedHook(t *testing.T) {
+	disconnected := tmdwg.NewTimedWaitGroup(1, 1*time.Second)
+
+	// Initialize webwire server
+	server := setupServer(
+		t,
+		&serverImpl{
+			onClientConnected: func(wwr.Connection) {
+				panic("hook bug")
+			},
+			onClientDisconnected: func(
+				_ wwr.Connection,
+				reason wwr.DisconnectReason,
+			) {
+				assert.Equal(t, wwr.DisconnectInternalError, reason)
+				disconnected.Progress(1)
+			},
+		},
+		wwr.ServerOptions{},
+	)
+
+	conn := dialRaw(t, server)
+	defer conn.Close()
+	require.NoError(t, disconnected.Wait())
+}
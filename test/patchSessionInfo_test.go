@@ -0,0 +1,109 @@
+package test
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	wwr "github.com/qbeon/webwire-go"
+	wwrclt "github.com/qbeon/webwire-go/client"
+)
+
+// TestPatchSessionInfo tests whether concurrent session info patches
+// are all merged into the session info, persisted
+// and synchronized to the client
+func TestPatchSessionInfo(t *testing.T) {
+	var persisted int32
+
+	// Initialize webwire server
+	server := setupServer(
+		t,
+		&serverImpl{
+			onRequest: func(
+				_ context.Context,
+				conn wwr.Connection,
+				msg wwr.Message,
+			) (wwr.Payload, error) {
+				if msg.Name() == "login" {
+					return nil, conn.CreateSession(
+						context.Background(),
+						wwr.GenericSessionInfoParser(map[string]interface{}{
+							"role": "editor",
+						}),
+					)
+				}
+
+				// Patch the session info concurrently
+				var wg sync.WaitGroup
+				for i := 0; i < 10; i++ {
+					wg.Add(1)
+					go func(i int) {
+						defer wg.Done()
+						assert.NoError(t, conn.PatchSessionInfo(
+							fmt.Sprintf("field%d", i),
+							i,
+						))
+					}(i)
+				}
+				wg.Wait()
+
+				assert.Equal(t, "editor", conn.SessionInfo("role"))
+				for i := 0; i < 10; i++ {
+					assert.Equal(
+						t,
+						float64(i),
+						conn.SessionInfo(fmt.Sprintf("field%d", i)),
+					)
+				}
+				return nil, nil
+			},
+		},
+		wwr.ServerOptions{
+			SessionManager: &callbackPoweredSessionManager{
+				SessionInfoUpdated: func(
+					_ context.Context,
+					conn wwr.Connection,
+				) error {
+					atomic.AddInt32(&persisted, 1)
+					return nil
+				},
+			},
+		},
+	)
+
+	// Initialize client
+	client := newCallbackPoweredClient(
+		server.Addr().String(),
+		wwrclt.Options{
+			DefaultRequestTimeout: 2 * time.Second,
+			Autoconnect:           wwr.Disabled,
+		},
+		callbackPoweredClientHooks{},
+	)
+	defer client.connection.Close()
+	require.NoError(t, client.connection.Connect())
+
+	_, err := client.connection.Request(context.Background(), "login", nil)
+	require.NoError(t, err)
+
+	_, err = client.connection.Request(context.Background(), "patch", nil)
+	require.NoError(t, err)
+	require.Equal(t, int32(10), atomic.LoadInt32(&persisted))
+
+	// Expect the client to have received the patched session info
+	// before the reply
+	require.Equal(t, "editor", client.connection.SessionInfo("role"))
+	for i := 0; i < 10; i++ {
+		require.Equal(
+			t,
+			float64(i),
+			client.connection.SessionInfo(fmt.Sprintf("field%d", i)),
+		)
+	}
+}
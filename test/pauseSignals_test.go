@@ -0,0 +1,89 @@
+package test
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	tmdwg "github.com/qbeon/tmdwg-go"
+	wwr "github.com/qbeon/webwire-go"
+	wwrclt "github.com/qbeon/webwire-go/client"
+)
+
+// TestPauseSignals tests pausing and resuming non-critical signals
+func TestPauseSignals(t *testing.T) {
+	receivedLock := sync.Mutex{}
+	received := []string{}
+	signalsReceived := tmdwg.NewTimedWaitGroup(3, 1*time.Second)
+	payload := wwr.NewPayload(wwr.EncodingBinary, []byte("payload"))
+
+	// Initialize webwire server sending a regular and a critical signal
+	// on each request
+	server := setupServer(
+		t,
+		&serverImpl{
+			onRequest: func(
+				_ context.Context,
+				conn wwr.Connection,
+				message wwr.Message,
+			) (wwr.Payload, error) {
+				assert.Equal(
+					t,
+					message.Name() == "paused",
+					conn.SignalsPaused(),
+				)
+				assert.NoError(t, conn.Signal("regular", payload))
+				assert.NoError(t, conn.Signal("critical", payload))
+				return nil, nil
+			},
+		},
+		wwr.ServerOptions{
+			CriticalSignal: func(name string) bool {
+				return name == "critical"
+			},
+		},
+	)
+
+	// Initialize client
+	client := newCallbackPoweredClient(
+		server.Addr().String(),
+		wwrclt.Options{
+			DefaultRequestTimeout: 2 * time.Second,
+		},
+		callbackPoweredClientHooks{
+			OnSignal: func(message wwr.Message) {
+				receivedLock.Lock()
+				received = append(received, message.Name())
+				receivedLock.Unlock()
+				signalsReceived.Progress(1)
+			},
+		},
+	)
+	defer client.connection.Close()
+
+	require.NoError(t, client.connection.Connect())
+
+	// Expect only the critical signal while paused
+	require.NoError(t, client.connection.Pause())
+	_, err := client.connection.Request(context.Background(), "paused", nil)
+	require.NoError(t, err)
+
+	// Expect both signals after resuming
+	require.NoError(t, client.connection.Resume())
+	_, err = client.connection.Request(context.Background(), "resumed", nil)
+	require.NoError(t, err)
+
+	require.NoError(t, signalsReceived.Wait())
+
+	receivedLock.Lock()
+	defer receivedLock.Unlock()
+	require.ElementsMatch(
+		t,
+		[]string{"critical", "regular", "critical"},
+		received,
+	)
+}
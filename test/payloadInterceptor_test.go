@@ -0,0 +1,217 @@
+package test
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	tmdwg "github.com/qbeon/tmdwg-go"
+	wwr "github.com/qbeon/webwire-go"
+	wwrclt "github.com/qbeon/webwire-go/client"
+)
+
+// xorInterceptor implements the webwire.PayloadInterceptor interface
+// flipping all bits of the payload data
+type xorInterceptor struct {
+	inboundErr error
+}
+
+func xorBytes(data []byte) []byte {
+	flipped := make([]byte, len(data))
+	for i, b := range data {
+		flipped[i] = b ^ 0xFF
+	}
+	return flipped
+}
+
+// Outbound implements the webwire.PayloadInterceptor interface
+func (icp *xorInterceptor) Outbound(data []byte) ([]byte, error) {
+	return xorBytes(data), nil
+}
+
+// Inbound implements the webwire.PayloadInterceptor interface
+func (icp *xorInterceptor) Inbound(data []byte) ([]byte, error) {
+	if icp.inboundErr != nil {
+		return nil, icp.inboundErr
+	}
+	return xorBytes(data), nil
+}
+
+// TestPayloadInterceptor tests the transformation
+// of incoming and outgoing payloads
+func TestPayloadInterceptor(t *testing.T) {
+	// Initialize webwire server echoing the request payload
+	server := setupServer(
+		t,
+		&serverImpl{
+			onRequest: func(
+				_ context.Context,
+				_ wwr.Connection,
+				message wwr.Message,
+			) (wwr.Payload, error) {
+				assert.Equal(t, []byte("plain"), message.Payload().Data())
+				return message.Payload(), nil
+			},
+		},
+		wwr.ServerOptions{
+			PayloadInterceptor: &xorInterceptor{},
+		},
+	)
+
+	// Initialize client
+	client := newCallbackPoweredClient(
+		server.Addr().String(),
+		wwrclt.Options{
+			DefaultRequestTimeout: 2 * time.Second,
+		},
+		callbackPoweredClientHooks{},
+	)
+	defer client.connection.Close()
+
+	require.NoError(t, client.connection.Connect())
+
+	reply, err := client.connection.Request(
+		context.Background(),
+		"test",
+		wwr.NewPayload(wwr.EncodingBinary, xorBytes([]byte("plain"))),
+	)
+	require.NoError(t, err)
+	require.Equal(t, xorBytes([]byte("plain")), reply.Data())
+}
+
+// TestPayloadInterceptorInboundFailure tests the rejection of requests
+// the inbound transformation failed for
+func TestPayloadInterceptorInboundFailure(t *testing.T) {
+	// Initialize webwire server
+	server := setupServer(
+		t,
+		&serverImpl{
+			onRequest: func(
+				_ context.Context,
+				_ wwr.Connection,
+				_ wwr.Message,
+			) (wwr.Payload, error) {
+				t.Errorf("OnRequest was not expected to be called")
+				return nil, nil
+			},
+		},
+		wwr.ServerOptions{
+			PayloadInterceptor: &xorInterceptor{
+				inboundErr: fmt.Errorf("expected failure"),
+			},
+		},
+	)
+
+	// Initialize client
+	client := newCallbackPoweredClient(
+		server.Addr().String(),
+		wwrclt.Options{
+			DefaultRequestTimeout: 2 * time.Second,
+		},
+		callbackPoweredClientHooks{},
+	)
+	defer client.connection.Close()
+
+	require.NoError(t, client.connection.Connect())
+
+	_, err := client.connection.Request(
+		context.Background(),
+		"test",
+		wwr.NewPayload(wwr.EncodingBinary, []byte("data")),
+	)
+	require.IsType(t, wwr.ProtocolErr{}, err)
+}
+
+// TestPayloadInterceptorStreamedReply tests the transformation
+// of each chunk of a streamed reply
+func TestPayloadInterceptorStreamedReply(t *testing.T) {
+	expectedReplyData := make([]byte, 200)
+	for i := range expectedReplyData {
+		expectedReplyData[i] = byte(i)
+	}
+
+	// Initialize webwire server streaming the reply
+	server := setupServer(
+		t,
+		&serverImpl{
+			onRequest: func(
+				_ context.Context,
+				_ wwr.Connection,
+				_ wwr.Message,
+			) (wwr.Payload, error) {
+				return wwr.NewStreamPayload(
+					wwr.EncodingBinary,
+					bytes.NewReader(expectedReplyData),
+				), nil
+			},
+		},
+		wwr.ServerOptions{
+			PayloadInterceptor: &xorInterceptor{},
+			ReplyChunkSize:     64,
+		},
+	)
+
+	// Initialize client
+	client := newCallbackPoweredClient(
+		server.Addr().String(),
+		wwrclt.Options{
+			DefaultRequestTimeout: 2 * time.Second,
+		},
+		callbackPoweredClientHooks{},
+	)
+	defer client.connection.Close()
+
+	require.NoError(t, client.connection.Connect())
+
+	reply, err := client.connection.Request(
+		context.Background(),
+		"stream",
+		nil,
+	)
+	require.NoError(t, err)
+	require.Equal(t, xorBytes(expectedReplyData), reply.Data())
+}
+
+// TestPayloadInterceptorPreparedSignal tests the rejection
+// of prepared signals when a payload interceptor is set
+func TestPayloadInterceptorPreparedSignal(t *testing.T) {
+	prepared, err := wwr.PrepareSignal(
+		"tick",
+		wwr.NewPayload(wwr.EncodingBinary, []byte("data")),
+	)
+	require.NoError(t, err)
+
+	rejected := tmdwg.NewTimedWaitGroup(1, 1*time.Second)
+
+	// Initialize webwire server sending the prepared signal
+	server := setupServer(
+		t,
+		&serverImpl{
+			onClientConnected: func(conn wwr.Connection) {
+				assert.Error(t, conn.SendRaw(prepared))
+				rejected.Progress(1)
+			},
+		},
+		wwr.ServerOptions{
+			PayloadInterceptor: &xorInterceptor{},
+		},
+	)
+
+	// Initialize client
+	client := newCallbackPoweredClient(
+		server.Addr().String(),
+		wwrclt.Options{
+			DefaultRequestTimeout: 2 * time.Second,
+		},
+		callbackPoweredClientHooks{},
+	)
+	defer client.connection.Close()
+
+	require.NoError(t, client.connection.Connect())
+	require.NoError(t, rejected.Wait())
+}
@@ -0,0 +1,44 @@
+package test
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	wwr "github.com/qbeon/webwire-go"
+)
+
+// TestPlainHttpRequest tests whether plain HTTP requests not asking
+// for a connection upgrade are rejected with 426 Upgrade Required
+func TestPlainHttpRequest(t *testing.T) {
+	// Initialize webwire server
+	server := setupServer(
+		t,
+		&serverImpl{
+			beforeUpgrade: func(
+				_ http.ResponseWriter,
+				_ *http.Request,
+			) wwr.ConnectionOptions {
+				t.Error("BeforeUpgrade unexpectedly invoked")
+				return wwr.AcceptConnection(wwr.UnlimitedConcurrency)
+			},
+		},
+		wwr.ServerOptions{},
+	)
+
+	response, err := http.Get(
+		(&url.URL{Scheme: "http", Host: server.Addr().String()}).String(),
+	)
+	require.NoError(t, err)
+	defer response.Body.Close()
+
+	require.Equal(t, http.StatusUpgradeRequired, response.StatusCode)
+	require.Equal(t, "websocket", response.Header.Get("Upgrade"))
+
+	body, err := ioutil.ReadAll(response.Body)
+	require.NoError(t, err)
+	require.Contains(t, string(body), "WebSocket")
+}
@@ -0,0 +1,66 @@
+package test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	wwr "github.com/qbeon/webwire-go"
+	wwrclt "github.com/qbeon/webwire-go/client"
+)
+
+// TestPreAuthRequest tests whether requests not allowed before the session
+// establishment are rejected with an UnauthorizedErr
+func TestPreAuthRequest(t *testing.T) {
+	// Initialize webwire server
+	server := setupServer(
+		t,
+		&serverImpl{
+			onRequest: func(
+				ctx context.Context,
+				conn wwr.Connection,
+				msg wwr.Message,
+			) (wwr.Payload, error) {
+				if msg.Name() == "login" {
+					assert.NoError(t, conn.CreateSession(ctx, nil))
+					return nil, nil
+				}
+				// Only authorized requests are expected to be handled
+				assert.True(t, conn.HasSession())
+				return nil, nil
+			},
+		},
+		wwr.ServerOptions{
+			PreAuthRequest: func(name string) bool {
+				return name == "login"
+			},
+		},
+	)
+
+	// Initialize client
+	client := newCallbackPoweredClient(
+		server.Addr().String(),
+		wwrclt.Options{
+			DefaultRequestTimeout: 2 * time.Second,
+		},
+		callbackPoweredClientHooks{},
+	)
+	defer client.connection.Close()
+	require.NoError(t, client.connection.Connect())
+
+	// Expect requests to be rejected before the session establishment
+	_, err := client.connection.Request(context.Background(), "data", nil)
+	require.Error(t, err)
+	require.IsType(t, wwr.UnauthorizedErr{}, err)
+
+	// Log in
+	_, err = client.connection.Request(context.Background(), "login", nil)
+	require.NoError(t, err)
+
+	// Expect requests to be handled after the session establishment
+	_, err = client.connection.Request(context.Background(), "data", nil)
+	require.NoError(t, err)
+}
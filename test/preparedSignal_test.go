@@ -0,0 +1,96 @@
+package test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	tmdwg "github.com/qbeon/tmdwg-go"
+	wwr "github.com/qbeon/webwire-go"
+	wwrclt "github.com/qbeon/webwire-go/client"
+)
+
+// TestPreparedSignal tests sending a single prepared signal
+// to multiple clients
+func TestPreparedSignal(t *testing.T) {
+	expectedSignalPayload := wwr.NewPayload(
+		wwr.EncodingUtf8,
+		[]byte("webwire_test_PREPARED_SIGNAL_payload"),
+	)
+	prepared, err := wwr.PrepareSignal("tick", expectedSignalPayload)
+	require.NoError(t, err)
+
+	signalsProcessed := tmdwg.NewTimedWaitGroup(2, 1*time.Second)
+
+	// Initialize webwire server sending the prepared signal
+	server := setupServer(
+		t,
+		&serverImpl{
+			onClientConnected: func(conn wwr.Connection) {
+				assert.NoError(t, conn.SendRaw(prepared))
+			},
+		},
+		wwr.ServerOptions{},
+	)
+
+	// Initialize clients
+	for i := 0; i < 2; i++ {
+		client := newCallbackPoweredClient(
+			server.Addr().String(),
+			wwrclt.Options{
+				DefaultRequestTimeout: 2 * time.Second,
+			},
+			callbackPoweredClientHooks{
+				OnSignal: func(signalMessage wwr.Message) {
+					assert.Equal(t, "tick", signalMessage.Name())
+					comparePayload(
+						t,
+						expectedSignalPayload,
+						signalMessage.Payload(),
+					)
+					signalsProcessed.Progress(1)
+				},
+			},
+		)
+		defer client.connection.Close()
+
+		require.NoError(t, client.connection.Connect())
+	}
+
+	require.NoError(t,
+		signalsProcessed.Wait(),
+		"Prepared signals didn't arrive",
+	)
+}
+
+// TestPreparedSignalInvalid tests sending raw messages
+// not representing a prepared signal
+func TestPreparedSignalInvalid(t *testing.T) {
+	rejected := tmdwg.NewTimedWaitGroup(1, 1*time.Second)
+
+	// Initialize webwire server
+	server := setupServer(
+		t,
+		&serverImpl{
+			onClientConnected: func(conn wwr.Connection) {
+				assert.Error(t, conn.SendRaw(nil))
+				assert.Error(t, conn.SendRaw([]byte{255, 0}))
+				rejected.Progress(1)
+			},
+		},
+		wwr.ServerOptions{},
+	)
+
+	// Initialize client
+	client := newCallbackPoweredClient(
+		server.Addr().String(),
+		wwrclt.Options{},
+		callbackPoweredClientHooks{},
+	)
+	defer client.connection.Close()
+
+	require.NoError(t, client.connection.Connect())
+	require.NoError(t, rejected.Wait())
+}
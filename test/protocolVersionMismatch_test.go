@@ -0,0 +1,47 @@
+package test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	wwr "github.com/qbeon/webwire-go"
+	wwrclt "github.com/qbeon/webwire-go/client"
+)
+
+// TestProtocolVersionMismatch tests connecting to a server
+// implementing an unsupported protocol version
+func TestProtocolVersionMismatch(t *testing.T) {
+	// Initialize a fake endpoint reporting an unsupported protocol version
+	server := httptest.NewServer(http.HandlerFunc(
+		func(resp http.ResponseWriter, req *http.Request) {
+			json.NewEncoder(resp).Encode(struct {
+				ProtocolVersion string `json:"protocol-version"`
+			}{
+				"0.1",
+			})
+		},
+	))
+	defer server.Close()
+
+	// Initialize client
+	client := newCallbackPoweredClient(
+		strings.TrimPrefix(server.URL, "http://"),
+		wwrclt.Options{
+			Autoconnect: wwr.Disabled,
+		},
+		callbackPoweredClientHooks{},
+	)
+
+	err := client.connection.Connect()
+	require.Error(t, err)
+	require.IsType(t, wwr.ProtocolVersionMismatchErr{}, err)
+
+	mismatchErr := err.(wwr.ProtocolVersionMismatchErr)
+	require.Equal(t, "0.1", mismatchErr.ServerVersion)
+	require.Equal(t, "1.5", mismatchErr.ClientVersion)
+}
@@ -0,0 +1,93 @@
+package test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	tmdwg "github.com/qbeon/tmdwg-go"
+	wwr "github.com/qbeon/webwire-go"
+	wwrclt "github.com/qbeon/webwire-go/client"
+)
+
+// TestPublish tests whether published signals are only sent
+// to the clients subscribed to the topic
+func TestPublish(t *testing.T) {
+	signalReceived := tmdwg.NewTimedWaitGroup(1, 1*time.Second)
+
+	// Initialize webwire server
+	server := setupServer(
+		t,
+		&serverImpl{
+			onRequest: func(
+				_ context.Context,
+				conn wwr.Connection,
+				msg wwr.Message,
+			) (wwr.Payload, error) {
+				topic := string(msg.Payload().Data())
+				conn.Subscribe(topic)
+				assert.True(t, conn.IsSubscribed(topic))
+				return nil, nil
+			},
+		},
+		wwr.ServerOptions{},
+	)
+
+	// Initialize a subscribed client
+	subscriber := newCallbackPoweredClient(
+		server.Addr().String(),
+		wwrclt.Options{
+			DefaultRequestTimeout: 2 * time.Second,
+		},
+		callbackPoweredClientHooks{
+			OnSignal: func(msg wwr.Message) {
+				assert.Equal(t, "prices", msg.Name())
+				assert.Equal(t, []byte("42"), msg.Payload().Data())
+				signalReceived.Progress(1)
+			},
+		},
+	)
+	defer subscriber.connection.Close()
+	require.NoError(t, subscriber.connection.Connect())
+
+	_, err := subscriber.connection.Request(
+		context.Background(),
+		"subscribe",
+		wwr.NewPayload(wwr.EncodingUtf8, []byte("prices")),
+	)
+	require.NoError(t, err)
+
+	// Initialize a client subscribed to another topic
+	other := newCallbackPoweredClient(
+		server.Addr().String(),
+		wwrclt.Options{
+			DefaultRequestTimeout: 2 * time.Second,
+		},
+		callbackPoweredClientHooks{
+			OnSignal: func(msg wwr.Message) {
+				t.Errorf("unexpected signal %q", msg.Name())
+			},
+		},
+	)
+	defer other.connection.Close()
+	require.NoError(t, other.connection.Connect())
+
+	_, err = other.connection.Request(
+		context.Background(),
+		"subscribe",
+		wwr.NewPayload(wwr.EncodingUtf8, []byte("news")),
+	)
+	require.NoError(t, err)
+
+	// Publish and expect only the subscriber to receive the signal
+	signaled, err := server.Publish(
+		"prices",
+		wwr.NewPayload(wwr.EncodingUtf8, []byte("42")),
+	)
+	require.NoError(t, err)
+	require.Equal(t, 1, signaled)
+	require.NoError(t, signalReceived.Wait())
+}
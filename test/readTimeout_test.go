@@ -0,0 +1,94 @@
+package test
+
+import (
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	tmdwg "github.com/qbeon/tmdwg-go"
+	wwr "github.com/qbeon/webwire-go"
+	wwrclt "github.com/qbeon/webwire-go/client"
+)
+
+// TestReadTimeout tests whether silent connections are closed
+// with an idle timeout reason after the read timeout elapsed
+func TestReadTimeout(t *testing.T) {
+	disconnected := tmdwg.NewTimedWaitGroup(1, 1*time.Second)
+
+	// Initialize webwire server
+	server := setupServer(
+		t,
+		&serverImpl{
+			onClientDisconnected: func(
+				_ wwr.Connection,
+				reason wwr.DisconnectReason,
+			) {
+				assert.Equal(t, wwr.DisconnectIdleTimeout, reason)
+				disconnected.Progress(1)
+			},
+		},
+		wwr.ServerOptions{
+			ReadTimeout: 100 * time.Millisecond,
+		},
+	)
+
+	// Connect a raw websocket never sending anything
+	// and never responding to pings
+	conn, _, err := websocket.DefaultDialer.Dial(
+		(&url.URL{Scheme: "ws", Host: server.Addr().String()}).String(),
+		nil,
+	)
+	require.NoError(t, err)
+	defer conn.Close()
+
+	require.NoError(t, disconnected.Wait())
+}
+
+// TestReadTimeoutActiveConnection tests whether the read deadline
+// is postponed for connections that keep sending messages
+func TestReadTimeoutActiveConnection(t *testing.T) {
+	readTimeout := 200 * time.Millisecond
+
+	// Initialize webwire server
+	server := setupServer(
+		t,
+		&serverImpl{
+			onClientDisconnected: func(
+				_ wwr.Connection,
+				reason wwr.DisconnectReason,
+			) {
+				assert.NotEqual(t, wwr.DisconnectIdleTimeout, reason)
+			},
+		},
+		wwr.ServerOptions{
+			ReadTimeout: readTimeout,
+		},
+	)
+
+	// Initialize client
+	client := newCallbackPoweredClient(
+		server.Addr().String(),
+		wwrclt.Options{
+			Autoconnect: wwr.Disabled,
+		},
+		callbackPoweredClientHooks{},
+	)
+	defer client.connection.Close()
+
+	require.NoError(t, client.connection.Connect())
+
+	// Keep sending signals for longer than the read timeout
+	for i := 0; i < 6; i++ {
+		time.Sleep(readTimeout / 2)
+		require.NoError(t, client.connection.Signal(
+			"",
+			wwr.NewPayload(wwr.EncodingBinary, []byte("keepalive")),
+		))
+	}
+
+	require.Equal(t, wwrclt.Connected, client.connection.Status())
+}
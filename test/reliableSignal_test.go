@@ -0,0 +1,116 @@
+package test
+
+import (
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	tmdwg "github.com/qbeon/tmdwg-go"
+	wwr "github.com/qbeon/webwire-go"
+	wwrclt "github.com/qbeon/webwire-go/client"
+	msg "github.com/qbeon/webwire-go/message"
+)
+
+// TestReliableSignal tests whether reliable signals
+// are acknowledged by the client
+func TestReliableSignal(t *testing.T) {
+	connected := tmdwg.NewTimedWaitGroup(1, 1*time.Second)
+	var serverSideConn wwr.Connection
+	signalReceived := tmdwg.NewTimedWaitGroup(1, 1*time.Second)
+
+	// Initialize webwire server
+	server := setupServer(
+		t,
+		&serverImpl{
+			onClientConnected: func(conn wwr.Connection) {
+				serverSideConn = conn
+				connected.Progress(1)
+			},
+		},
+		wwr.ServerOptions{
+			SignalAckTimeout:       1 * time.Second,
+			ReliableSignalAttempts: 1,
+		},
+	)
+
+	// Initialize client
+	client := newCallbackPoweredClient(
+		server.Addr().String(),
+		wwrclt.Options{
+			DefaultRequestTimeout: 2 * time.Second,
+		},
+		callbackPoweredClientHooks{
+			OnSignal: func(message wwr.Message) {
+				assert.Equal(t, "notification", message.Name())
+				assert.Equal(t, []byte("data"), message.Payload().Data())
+				signalReceived.Progress(1)
+			},
+		},
+	)
+	defer client.connection.Close()
+	require.NoError(t, client.connection.Connect())
+	require.NoError(t, connected.Wait())
+
+	// Expect the signal to be acknowledged
+	require.NoError(t, serverSideConn.ReliableSignal(
+		"notification",
+		wwr.NewPayload(wwr.EncodingUtf8, []byte("data")),
+	))
+	require.NoError(t, signalReceived.Wait())
+}
+
+// TestReliableSignalUnacknowledged tests whether unacknowledged reliable
+// signals are retried and eventually fail with a TimeoutErr
+func TestReliableSignalUnacknowledged(t *testing.T) {
+	connected := tmdwg.NewTimedWaitGroup(1, 1*time.Second)
+	var serverSideConn wwr.Connection
+
+	// Initialize webwire server
+	server := setupServer(
+		t,
+		&serverImpl{
+			onClientConnected: func(conn wwr.Connection) {
+				serverSideConn = conn
+				connected.Progress(1)
+			},
+		},
+		wwr.ServerOptions{
+			SignalAckTimeout:       50 * time.Millisecond,
+			ReliableSignalAttempts: 2,
+		},
+	)
+
+	// Connect a raw websocket never acknowledging any signal
+	conn, _, err := websocket.DefaultDialer.Dial(
+		(&url.URL{Scheme: "ws", Host: server.Addr().String()}).String(),
+		nil,
+	)
+	require.NoError(t, err)
+	defer conn.Close()
+	require.NoError(t, connected.Wait())
+
+	err = serverSideConn.ReliableSignal(
+		"notification",
+		wwr.NewPayload(wwr.EncodingBinary, []byte("data")),
+	)
+	require.Error(t, err)
+	require.IsType(t, wwr.TimeoutErr{}, err)
+
+	// Expect the signal to have been sent twice using the same identifier
+	var identifiers [][8]byte
+	for len(identifiers) < 2 {
+		_, raw, err := conn.ReadMessage()
+		require.NoError(t, err)
+		var parsed msg.Message
+		_, err = parsed.Parse(raw)
+		require.NoError(t, err)
+		if parsed.Type == msg.MsgReliableSignalBinary {
+			identifiers = append(identifiers, parsed.Identifier)
+		}
+	}
+	require.Equal(t, identifiers[0], identifiers[1])
+}
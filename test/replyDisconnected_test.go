@@ -0,0 +1,79 @@
+package test
+
+import (
+	"bytes"
+	"context"
+	"log"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/stretchr/testify/require"
+
+	tmdwg "github.com/qbeon/tmdwg-go"
+	wwr "github.com/qbeon/webwire-go"
+	msg "github.com/qbeon/webwire-go/message"
+	pld "github.com/qbeon/webwire-go/payload"
+)
+
+// TestReplyDisconnected tests whether replies to requests
+// of disconnected clients are silently dropped
+func TestReplyDisconnected(t *testing.T) {
+	requestArrived := tmdwg.NewTimedWaitGroup(1, 1*time.Second)
+	disconnected := tmdwg.NewTimedWaitGroup(1, 1*time.Second)
+	errorLog := &bytes.Buffer{}
+
+	// Initialize webwire server replying only after the client disconnected
+	server := setupServer(
+		t,
+		&serverImpl{
+			onClientDisconnected: func(
+				_ wwr.Connection,
+				_ wwr.DisconnectReason,
+			) {
+				disconnected.Progress(1)
+			},
+			onRequest: func(
+				_ context.Context,
+				_ wwr.Connection,
+				_ wwr.Message,
+			) (wwr.Payload, error) {
+				requestArrived.Progress(1)
+				disconnected.Wait()
+				return wwr.NewPayload(
+					wwr.EncodingBinary,
+					[]byte("too late"),
+				), nil
+			},
+		},
+		wwr.ServerOptions{
+			ErrorLog: log.New(errorLog, "", 0),
+		},
+	)
+
+	// Connect a raw websocket and send a request
+	conn, _, err := websocket.DefaultDialer.Dial(
+		(&url.URL{Scheme: "ws", Host: server.Addr().String()}).String(),
+		nil,
+	)
+	require.NoError(t, err)
+	require.NoError(t, conn.WriteMessage(
+		websocket.BinaryMessage,
+		msg.NewRequestMessage([8]byte{1}, "request", pld.Binary, nil),
+	))
+	require.NoError(t, requestArrived.Wait())
+
+	// Disconnect before the reply is sent
+	require.NoError(t, conn.Close())
+	require.NoError(t, disconnected.Wait())
+
+	// Await the request handler to return
+	deadline := time.Now().Add(1 * time.Second)
+	for server.PendingOps() > 0 {
+		require.True(t, time.Now().Before(deadline), "handler didn't return")
+		time.Sleep(5 * time.Millisecond)
+	}
+
+	require.Empty(t, errorLog.String())
+}
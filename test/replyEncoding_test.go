@@ -0,0 +1,82 @@
+package test
+
+import (
+	"bytes"
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	wwr "github.com/qbeon/webwire-go"
+	wwrclt "github.com/qbeon/webwire-go/client"
+)
+
+// TestReplyEncoding tests whether the encoding of replies
+// is correctly reflected by the reply payloads received by the client
+func TestReplyEncoding(t *testing.T) {
+	replies := map[string]wwr.Payload{
+		"binary": wwr.NewPayload(wwr.EncodingBinary, []byte{0, 1, 2}),
+		"utf8":   wwr.NewPayload(wwr.EncodingUtf8, []byte("utf8")),
+		"utf16": wwr.NewPayload(
+			wwr.EncodingUtf16,
+			[]byte{'u', 0, 't', 0, 'f', 0, '1', 0, '6', 0},
+		),
+		"utf16-empty": wwr.NewPayload(wwr.EncodingUtf16, nil),
+		"utf16-stream": wwr.NewStreamPayload(
+			wwr.EncodingUtf16,
+			bytes.NewReader([]byte{'s', 0, 't', 0}),
+		),
+	}
+
+	// Initialize webwire server replying with the requested encoding
+	server := setupServer(
+		t,
+		&serverImpl{
+			onRequest: func(
+				_ context.Context,
+				_ wwr.Connection,
+				message wwr.Message,
+			) (wwr.Payload, error) {
+				return replies[message.Name()], nil
+			},
+		},
+		wwr.ServerOptions{},
+	)
+
+	// Initialize client
+	client := newCallbackPoweredClient(
+		server.Addr().String(),
+		wwrclt.Options{
+			DefaultRequestTimeout: 2 * time.Second,
+		},
+		callbackPoweredClientHooks{},
+	)
+	defer client.connection.Close()
+
+	require.NoError(t, client.connection.Connect())
+
+	expected := map[string]wwr.Payload{
+		"binary":      replies["binary"],
+		"utf8":        replies["utf8"],
+		"utf16":       replies["utf16"],
+		"utf16-empty": wwr.NewPayload(wwr.EncodingUtf16, nil),
+		"utf16-stream": wwr.NewPayload(
+			wwr.EncodingUtf16,
+			[]byte{'s', 0, 't', 0},
+		),
+	}
+	for name, expectedReply := range expected {
+		reply, err := client.connection.Request(
+			context.Background(),
+			name,
+			nil,
+		)
+		require.NoError(t, err, name)
+		require.Equal(t, expectedReply.Encoding(), reply.Encoding(), name)
+		require.Equal(t, len(expectedReply.Data()), len(reply.Data()), name)
+		if len(expectedReply.Data()) > 0 {
+			require.Equal(t, expectedReply.Data(), reply.Data(), name)
+		}
+	}
+}
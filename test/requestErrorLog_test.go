@@ -0,0 +1,65 @@
+package test
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"log"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	wwr "github.com/qbeon/webwire-go"
+	wwrclt "github.com/qbeon/webwire-go/client"
+)
+
+// TestRequestErrorLog tests whether internal request errors are logged
+// along with the request identifier and the address of the client
+func TestRequestErrorLog(t *testing.T) {
+	errorLog := &bytes.Buffer{}
+	clientAddr := make(chan string, 1)
+
+	// Initialize webwire server failing all requests
+	server := setupServer(
+		t,
+		&serverImpl{
+			onRequest: func(
+				_ context.Context,
+				conn wwr.Connection,
+				_ wwr.Message,
+			) (wwr.Payload, error) {
+				clientAddr <- conn.Info().RemoteAddr.String()
+				return nil, fmt.Errorf("internal failure")
+			},
+		},
+		wwr.ServerOptions{
+			ErrorLog: log.New(errorLog, "", 0),
+		},
+	)
+
+	// Initialize client
+	client := newCallbackPoweredClient(
+		server.Addr().String(),
+		wwrclt.Options{
+			DefaultRequestTimeout: 2 * time.Second,
+		},
+		callbackPoweredClientHooks{},
+	)
+	defer client.connection.Close()
+	require.NoError(t, client.connection.Connect())
+
+	_, err := client.connection.Request(context.Background(), "fail", nil)
+	require.IsType(t, wwr.ReqInternalErr{}, err)
+
+	// Verify the log line, the first request of a client is identified by 1
+	require.Contains(
+		t,
+		errorLog.String(),
+		fmt.Sprintf(
+			"request %q (0100000000000000) of client %s: internal failure",
+			"fail",
+			<-clientAddr,
+		),
+	)
+}
@@ -0,0 +1,70 @@
+package test
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	wwr "github.com/qbeon/webwire-go"
+	wwrclt "github.com/qbeon/webwire-go/client"
+)
+
+// TestRequestIdentifierReconnect tests whether request identifiers
+// are not reused by a client across reconnects
+func TestRequestIdentifierReconnect(t *testing.T) {
+	lock := sync.Mutex{}
+	identifiers := make(map[[8]byte]wwr.Connection)
+
+	// Initialize webwire server recording the request identifiers
+	server := setupServer(
+		t,
+		&serverImpl{
+			onRequest: func(
+				_ context.Context,
+				conn wwr.Connection,
+				msg wwr.Message,
+			) (wwr.Payload, error) {
+				lock.Lock()
+				defer lock.Unlock()
+				_, reused := identifiers[msg.Identifier()]
+				assert.False(t, reused, "Request identifier reused")
+				identifiers[msg.Identifier()] = conn
+				return nil, nil
+			},
+		},
+		wwr.ServerOptions{},
+	)
+
+	// Initialize client
+	client := newCallbackPoweredClient(
+		server.Addr().String(),
+		wwrclt.Options{
+			Autoconnect:           wwr.Disabled,
+			DefaultRequestTimeout: 2 * time.Second,
+		},
+		callbackPoweredClientHooks{},
+	)
+	defer client.connection.Close()
+
+	// Send requests over two consecutive connections
+	for i := 0; i < 2; i++ {
+		require.NoError(t, client.connection.Connect())
+		for j := 0; j < 3; j++ {
+			_, err := client.connection.Request(
+				context.Background(),
+				"test",
+				nil,
+			)
+			require.NoError(t, err)
+		}
+		client.connection.Close()
+	}
+
+	lock.Lock()
+	defer lock.Unlock()
+	require.Len(t, identifiers, 6)
+}
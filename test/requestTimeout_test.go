@@ -0,0 +1,143 @@
+package test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	wwr "github.com/qbeon/webwire-go"
+	webwireClient "github.com/qbeon/webwire-go/client"
+)
+
+// TestRequestTimeout tests whether requests exceeding the request timeout
+// fail with a RequestTimeoutErr and whether the handler context is canceled
+// while requests of names with a longer timeout are processed
+func TestRequestTimeout(t *testing.T) {
+	handlerCanceled := make(chan struct{})
+
+	// Initialize webwire server
+	server := setupServer(
+		t,
+		&serverImpl{
+			onRequest: func(
+				ctx context.Context,
+				_ wwr.Connection,
+				msg wwr.Message,
+			) (wwr.Payload, error) {
+				select {
+				case <-ctx.Done():
+					if string(msg.Name()) == "fast" {
+						close(handlerCanceled)
+					}
+					return nil, ctx.Err()
+				case <-time.After(100 * time.Millisecond):
+					return wwr.NewPayload(
+						wwr.EncodingUtf8,
+						[]byte("done"),
+					), nil
+				}
+			},
+		},
+		wwr.ServerOptions{
+			RequestTimeout: 20 * time.Millisecond,
+		},
+	)
+	server.SetRequestTimeout("slow", 2*time.Second)
+
+	// Initialize client
+	client := newCallbackPoweredClient(
+		server.Addr().String(),
+		webwireClient.Options{
+			DefaultRequestTimeout: 2 * time.Second,
+		},
+		callbackPoweredClientHooks{},
+	)
+	require.NoError(t, client.connection.Connect())
+
+	// Expect requests of names without a defined timeout
+	// to fall back to the default timeout
+	_, err := client.connection.Request(context.Background(), "fast", nil)
+	require.Error(t, err)
+	require.IsType(t, wwr.RequestTimeoutErr{}, err)
+
+	select {
+	case <-handlerCanceled:
+	case <-time.After(1 * time.Second):
+		t.Fatal("handler context wasn't canceled")
+	}
+
+	// Expect requests of names with a longer timeout to succeed
+	reply, err := client.connection.Request(context.Background(), "slow", nil)
+	require.NoError(t, err)
+	require.Equal(t, []byte("done"), reply.Data())
+
+	// Expect the default timeout to apply again after removing the timeout
+	server.SetRequestTimeout("slow", 0)
+	_, err = client.connection.Request(context.Background(), "slow", nil)
+	assert.IsType(t, wwr.RequestTimeoutErr{}, err)
+}
+
+// TestRequestTimeoutShutdown tests whether the server awaits handlers
+// ignoring the cancellation of their context after they timed out
+// before finishing the shutdown
+func TestRequestTimeoutShutdown(t *testing.T) {
+	releaseHandler := make(chan struct{})
+
+	// Initialize webwire server
+	server := setupServer(
+		t,
+		&serverImpl{
+			onRequest: func(
+				_ context.Context,
+				_ wwr.Connection,
+				_ wwr.Message,
+			) (wwr.Payload, error) {
+				<-releaseHandler
+				return nil, nil
+			},
+		},
+		wwr.ServerOptions{
+			RequestTimeout: 20 * time.Millisecond,
+		},
+	)
+
+	// Initialize client
+	client := newCallbackPoweredClient(
+		server.Addr().String(),
+		webwireClient.Options{
+			DefaultRequestTimeout: 2 * time.Second,
+		},
+		callbackPoweredClientHooks{},
+	)
+	defer client.connection.Close()
+	require.NoError(t, client.connection.Connect())
+
+	_, err := client.connection.Request(context.Background(), "test", nil)
+	require.IsType(t, wwr.RequestTimeoutErr{}, err)
+
+	// Expect the timed out handler to remain a pending operation
+	// after the handling of the request finished
+	awaitCondition(t, func() bool { return server.PendingOps() == 1 })
+
+	shutdownFinished := make(chan error, 1)
+	go func() {
+		shutdownFinished <- server.Shutdown()
+	}()
+
+	select {
+	case <-shutdownFinished:
+		t.Fatal("shutdown finished before the timed out handler returned")
+	case <-time.After(100 * time.Millisecond):
+	}
+
+	close(releaseHandler)
+	select {
+	case err := <-shutdownFinished:
+		require.NoError(t, err)
+	case <-time.After(1 * time.Second):
+		t.Fatal("shutdown didn't finish after the handler returned")
+	}
+}
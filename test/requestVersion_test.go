@@ -0,0 +1,71 @@
+package test
+
+import (
+	"context"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	wwr "github.com/qbeon/webwire-go"
+	wwrclt "github.com/qbeon/webwire-go/client"
+)
+
+// TestRequestVersion tests the negotiation of request payload schema versions
+func TestRequestVersion(t *testing.T) {
+	// Initialize webwire server
+	server := setupServer(
+		t,
+		&serverImpl{
+			onRequest: func(
+				ctx context.Context,
+				_ wwr.Connection,
+				_ wwr.Message,
+			) (wwr.Payload, error) {
+				return wwr.NewPayload(
+					wwr.EncodingUtf8,
+					[]byte(strconv.Itoa(wwr.RequestVersion(ctx))),
+				), nil
+			},
+		},
+		wwr.ServerOptions{
+			RequestVersions: map[string][]int{
+				"create": {1, 2, 3},
+			},
+		},
+	)
+
+	agreedVersion := func(clientVersions map[string][]int) int {
+		client := newCallbackPoweredClient(
+			server.Addr().String(),
+			wwrclt.Options{
+				DefaultRequestTimeout: 2 * time.Second,
+				RequestVersions:       clientVersions,
+			},
+			callbackPoweredClientHooks{},
+		)
+		defer client.connection.Close()
+		require.NoError(t, client.connection.Connect())
+
+		reply, err := client.connection.Request(
+			context.Background(),
+			"create",
+			nil,
+		)
+		require.NoError(t, err)
+		version, err := strconv.Atoi(string(reply.Data()))
+		require.NoError(t, err)
+
+		// Expect the client to agree on the same version
+		require.Equal(t, version, client.connection.RequestVersion("create"))
+		return version
+	}
+
+	// Expect the highest commonly supported version to be agreed on
+	require.Equal(t, 2, agreedVersion(map[string][]int{"create": {1, 2}}))
+
+	// Expect no version to be agreed on if there's no common version
+	require.Equal(t, 0, agreedVersion(map[string][]int{"create": {4}}))
+	require.Equal(t, 0, agreedVersion(nil))
+}
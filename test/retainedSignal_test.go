@@ -0,0 +1,103 @@
+package test
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	tmdwg "github.com/qbeon/tmdwg-go"
+	wwr "github.com/qbeon/webwire-go"
+	wwrclt "github.com/qbeon/webwire-go/client"
+)
+
+// TestRetainedSignal tests whether retained signals are replayed
+// to newly connected clients and whether cleared ones are not
+func TestRetainedSignal(t *testing.T) {
+	// Initialize webwire server
+	server := setupServer(
+		t,
+		&serverImpl{},
+		wwr.ServerOptions{},
+	)
+
+	// Retain two signals, the first one being replaced later
+	require.NoError(t, server.RetainSignal(
+		"state",
+		wwr.NewPayload(wwr.EncodingBinary, []byte("outdated")),
+	))
+	require.NoError(t, server.RetainSignal(
+		"state",
+		wwr.NewPayload(wwr.EncodingBinary, []byte("latest")),
+	))
+	require.NoError(t, server.RetainSignal(
+		"temporary",
+		wwr.NewPayload(wwr.EncodingBinary, []byte("temporary")),
+	))
+
+	// connect connects a new client returning the retained signals
+	// it received right after connecting
+	connect := func(expected int) map[string]string {
+		lock := sync.Mutex{}
+		received := make(map[string]string)
+		signalsReceived := tmdwg.NewTimedWaitGroup(expected, 1*time.Second)
+
+		client := newCallbackPoweredClient(
+			server.Addr().String(),
+			wwrclt.Options{
+				Autoconnect: wwr.Disabled,
+			},
+			callbackPoweredClientHooks{
+				OnSignal: func(message wwr.Message) {
+					lock.Lock()
+					received[message.Name()] = string(
+						message.Payload().Data(),
+					)
+					lock.Unlock()
+					signalsReceived.Progress(1)
+				},
+			},
+		)
+		defer client.connection.Close()
+
+		require.NoError(t, client.connection.Connect())
+		require.NoError(t, signalsReceived.Wait())
+
+		// Make sure no further signals arrive
+		time.Sleep(50 * time.Millisecond)
+
+		lock.Lock()
+		defer lock.Unlock()
+		return received
+	}
+
+	assert.Equal(t, map[string]string{
+		"state":     "latest",
+		"temporary": "temporary",
+	}, connect(2))
+
+	// Clear a retained signal
+	server.ClearRetainedSignal("temporary")
+
+	assert.Equal(t, map[string]string{
+		"state": "latest",
+	}, connect(1))
+}
+
+// TestRetainedSignalInvalidName tests retaining a signal
+// with an invalid name
+func TestRetainedSignalInvalidName(t *testing.T) {
+	// Initialize webwire server
+	server := setupServer(
+		t,
+		&serverImpl{},
+		wwr.ServerOptions{},
+	)
+
+	require.Error(t, server.RetainSignal(
+		"invalid\x00name",
+		wwr.NewPayload(wwr.EncodingBinary, []byte("payload")),
+	))
+}
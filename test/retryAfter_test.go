@@ -0,0 +1,73 @@
+package test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	wwr "github.com/qbeon/webwire-go"
+	wwrclt "github.com/qbeon/webwire-go/client"
+)
+
+// TestRetryAfter tests whether retry-after hints of request errors
+// returned by the OnRequest hook are provided to the client
+func TestRetryAfter(t *testing.T) {
+	// Initialize webwire server
+	server := setupServer(
+		t,
+		&serverImpl{
+			onRequest: func(
+				_ context.Context,
+				_ wwr.Connection,
+				msg wwr.Message,
+			) (wwr.Payload, error) {
+				switch msg.Name() {
+				case "rate-limited":
+					return nil, wwr.NewTooManyRequestsErr(3 * time.Second)
+				case "busy":
+					return nil, wwr.NewServiceUnavailableErr(
+						1500 * time.Millisecond,
+					)
+				}
+				return nil, wwr.TooManyRequestsErr{}
+			},
+		},
+		wwr.ServerOptions{},
+	)
+
+	// Initialize client
+	client := newCallbackPoweredClient(
+		server.Addr().String(),
+		wwrclt.Options{
+			DefaultRequestTimeout: 2 * time.Second,
+		},
+		callbackPoweredClientHooks{},
+	)
+	defer client.connection.Close()
+	require.NoError(t, client.connection.Connect())
+
+	request := func(name string) error {
+		_, err := client.connection.Request(context.Background(), name, nil)
+		return err
+	}
+
+	// Verify the hints
+	err := request("rate-limited")
+	require.IsType(t, wwr.TooManyRequestsErr{}, err)
+	require.Equal(t, 3*time.Second, err.(wwr.TooManyRequestsErr).RetryAfter())
+
+	err = request("busy")
+	require.IsType(t, wwr.ServiceUnavailableErr{}, err)
+	require.Equal(
+		t,
+		1500*time.Millisecond,
+		err.(wwr.ServiceUnavailableErr).RetryAfter(),
+	)
+
+	// Verify errors without a hint
+	err = request("no-hint")
+	require.IsType(t, wwr.TooManyRequestsErr{}, err)
+	require.Zero(t, err.(wwr.TooManyRequestsErr).RetryAfter())
+}
@@ -0,0 +1,92 @@
+package test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	wwr "github.com/qbeon/webwire-go"
+	wwrclt "github.com/qbeon/webwire-go/client"
+)
+
+// TestServerEvents tests whether connection lifecycle events
+// are emitted to the server event stream
+func TestServerEvents(t *testing.T) {
+	// Initialize webwire server
+	server := setupServer(
+		t,
+		&serverImpl{
+			onRequest: func(
+				ctx context.Context,
+				conn wwr.Connection,
+				msg wwr.Message,
+			) (wwr.Payload, error) {
+				switch msg.Name() {
+				case "login":
+					assert.NoError(t, conn.CreateSession(ctx, nil))
+				case "logout":
+					assert.NoError(t, conn.CloseSession())
+				default:
+					return nil, wwr.ReqErr{Code: "FAILED"}
+				}
+				return nil, nil
+			},
+		},
+		wwr.ServerOptions{},
+	)
+
+	// nextEvent returns the next event of the server event stream
+	nextEvent := func() wwr.ServerEvent {
+		select {
+		case event := <-server.Events():
+			return event
+		case <-time.After(1 * time.Second):
+			t.Fatal("Event not emitted")
+		}
+		return wwr.ServerEvent{}
+	}
+
+	// Initialize client
+	client := newCallbackPoweredClient(
+		server.Addr().String(),
+		wwrclt.Options{
+			DefaultRequestTimeout: 2 * time.Second,
+			Autoconnect:           wwr.Disabled,
+		},
+		callbackPoweredClientHooks{},
+	)
+	require.NoError(t, client.connection.Connect())
+
+	event := nextEvent()
+	require.Equal(t, wwr.EventClientConnected, event.Type)
+	require.NotNil(t, event.Connection)
+
+	_, err := client.connection.Request(context.Background(), "login", nil)
+	require.NoError(t, err)
+	event = nextEvent()
+	require.Equal(t, wwr.EventSessionCreated, event.Type)
+	require.Equal(t, client.connection.Session().Key, event.SessionKey)
+	sessionKey := event.SessionKey
+
+	_, err = client.connection.Request(context.Background(), "logout", nil)
+	require.NoError(t, err)
+	event = nextEvent()
+	require.Equal(t, wwr.EventSessionClosed, event.Type)
+	require.Equal(t, sessionKey, event.SessionKey)
+
+	_, err = client.connection.Request(context.Background(), "fail", nil)
+	require.Error(t, err)
+	event = nextEvent()
+	require.Equal(t, wwr.EventRequestFailed, event.Type)
+	require.Equal(t, "fail", event.Name)
+	require.Equal(t, wwr.ReqErr{Code: "FAILED"}, event.Err)
+
+	// The client closes the socket without sending a close frame
+	client.connection.Close()
+	event = nextEvent()
+	require.Equal(t, wwr.EventClientDisconnected, event.Type)
+	require.Equal(t, wwr.DisconnectAbnormalClose, event.DisconnectReason)
+}
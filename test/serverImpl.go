@@ -14,8 +14,11 @@ type serverImpl struct {
 		req *http.Request,
 	) wwr.ConnectionOptions
 	onClientConnected    func(connection wwr.Connection)
-	onClientDisconnected func(connection wwr.Connection)
-	onSignal             func(
+	onClientDisconnected func(
+		connection wwr.Connection,
+		reason wwr.DisconnectReason,
+	)
+	onSignal func(
 		ctx context.Context,
 		connection wwr.Connection,
 		message wwr.Message,
@@ -47,8 +50,11 @@ func (srv *serverImpl) OnClientConnected(conn wwr.Connection) {
 }
 
 // OnClientDisconnected implements the webwire.ServerImplementation interface
-func (srv *serverImpl) OnClientDisconnected(conn wwr.Connection) {
-	srv.onClientDisconnected(conn)
+func (srv *serverImpl) OnClientDisconnected(
+	conn wwr.Connection,
+	reason wwr.DisconnectReason,
+) {
+	srv.onClientDisconnected(conn, reason)
 }
 
 // OnSignal implements the webwire.ServerImplementation interface
@@ -77,7 +77,7 @@ func TestServerInitiatedSessionDestruction(t *testing.T) {
 				}
 
 				// On step 1 - authenticate and create a new session
-				err := conn.CreateSession(nil)
+				err := conn.CreateSession(context.Background(), nil)
 				assert.NoError(t, err)
 				if err != nil {
 					return nil, err
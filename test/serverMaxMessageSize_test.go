@@ -0,0 +1,68 @@
+package test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	wwr "github.com/qbeon/webwire-go"
+	webwireClient "github.com/qbeon/webwire-go/client"
+)
+
+// TestServerMaxMessageSize tests whether the client adopts the maximum
+// message size announced by the server and rejects oversized requests
+// and signals before sending them if configured
+func TestServerMaxMessageSize(t *testing.T) {
+	// Initialize webwire server
+	server := setupServer(
+		t,
+		&serverImpl{
+			onClientDisconnected: func(
+				_ wwr.Connection,
+				reason wwr.DisconnectReason,
+			) {
+				// Oversized messages must never reach the server
+				assert.NotEqual(t, wwr.DisconnectMessageTooBig, reason)
+			},
+		},
+		wwr.ServerOptions{
+			MaxMessageSize: 64,
+		},
+	)
+
+	// Initialize client
+	client := newCallbackPoweredClient(
+		server.Addr().String(),
+		webwireClient.Options{
+			DefaultRequestTimeout: 2 * time.Second,
+			EnforceMaxMessageSize: wwr.Enabled,
+		},
+		callbackPoweredClientHooks{},
+	)
+	require.Equal(t, int64(0), client.connection.ServerMaxMessageSize())
+	require.NoError(t, client.connection.Connect())
+	require.Equal(t, int64(64), client.connection.ServerMaxMessageSize())
+
+	oversized := wwr.NewPayload(wwr.EncodingBinary, make([]byte, 64))
+
+	// Expect oversized requests to be rejected
+	_, err := client.connection.Request(context.Background(), "r", oversized)
+	require.IsType(t, webwireClient.MessageTooBigErr{}, err)
+	require.Equal(t, 0, client.connection.PendingRequests())
+
+	// Expect oversized signals to be rejected
+	err = client.connection.Signal("s", oversized)
+	require.IsType(t, webwireClient.MessageTooBigErr{}, err)
+
+	// Expect messages within the limit to be sent
+	_, err = client.connection.Request(
+		context.Background(),
+		"r",
+		wwr.NewPayload(wwr.EncodingBinary, []byte("small")),
+	)
+	require.NoError(t, err)
+	require.Equal(t, webwireClient.Connected, client.connection.Status())
+}
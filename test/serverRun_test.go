@@ -0,0 +1,81 @@
+package test
+
+import (
+	"context"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	wwr "github.com/qbeon/webwire-go"
+	wwrclt "github.com/qbeon/webwire-go/client"
+)
+
+// TestServerRun tests running a headed server until it's shut down
+func TestServerRun(t *testing.T) {
+	server, err := wwr.NewServer(
+		&serverImpl{},
+		wwr.ServerOptions{
+			Address:  "127.0.0.1:0",
+			Sessions: wwr.Disabled,
+		},
+	)
+	require.NoError(t, err)
+	require.NotNil(t, server.Addr())
+
+	runResult := make(chan error, 1)
+	go func() {
+		runResult <- server.Run()
+	}()
+
+	require.NoError(t, server.Shutdown())
+
+	select {
+	case err := <-runResult:
+		require.NoError(t, err)
+	case <-time.After(1 * time.Second):
+		t.Fatal("Run didn't return after the shutdown")
+	}
+}
+
+// TestServerRunHeadless tests hosting a headless server
+// by an external HTTP server and running it
+func TestServerRunHeadless(t *testing.T) {
+	impl := &serverImpl{}
+	setupServerImplDefaults(impl)
+	server, err := wwr.NewHeadlessServer(
+		impl,
+		wwr.ServerOptions{
+			Sessions: wwr.Disabled,
+		},
+	)
+	require.NoError(t, err)
+	require.Nil(t, server.Addr())
+
+	// Expect headless servers to refuse running
+	require.IsType(t, wwr.HeadlessServerErr{}, server.Run())
+
+	// Host the headless server by an external HTTP server
+	httpServer := httptest.NewServer(server)
+	defer httpServer.Close()
+	defer server.Shutdown()
+
+	hostURL, err := url.Parse(httpServer.URL)
+	require.NoError(t, err)
+
+	client := newCallbackPoweredClient(
+		hostURL.Host,
+		wwrclt.Options{
+			Autoconnect:           wwr.Disabled,
+			DefaultRequestTimeout: 2 * time.Second,
+		},
+		callbackPoweredClientHooks{},
+	)
+	defer client.connection.Close()
+
+	require.NoError(t, client.connection.Connect())
+	_, err = client.connection.Request(context.Background(), "test", nil)
+	require.NoError(t, err)
+}
@@ -37,7 +37,7 @@ func TestServerSideSessionClosureInexistent(t *testing.T) {
 				conn wwr.Connection,
 				_ wwr.Message,
 			) (wwr.Payload, error) {
-				err := conn.CreateSession(nil)
+				err := conn.CreateSession(context.Background(), nil)
 				assert.NoError(t, err)
 				return nil, err
 			},
@@ -99,7 +99,11 @@ func TestServerSideSessionClosureInexistent(t *testing.T) {
 	for i, c := range sessionKey {
 		inexistentSessionKey[i] = byte(c)
 	}
-	inexistentSessionKey[0] = '0'
+	if inexistentSessionKey[0] == '0' {
+		inexistentSessionKey[0] = '1'
+	} else {
+		inexistentSessionKey[0] = '0'
+	}
 
 	// Try to close an inexistent session
 	affectedConnections, closeErrors, err := server.CloseSession(
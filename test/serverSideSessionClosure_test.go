@@ -36,7 +36,7 @@ func TestServerSideSessionClosure(t *testing.T) {
 				conn wwr.Connection,
 				_ wwr.Message,
 			) (wwr.Payload, error) {
-				err := conn.CreateSession(nil)
+				err := conn.CreateSession(context.Background(), nil)
 				assert.NoError(t, err)
 				return nil, err
 			},
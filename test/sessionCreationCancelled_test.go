@@ -0,0 +1,130 @@
+package test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	tmdwg "github.com/qbeon/tmdwg-go"
+	wwr "github.com/qbeon/webwire-go"
+	wwrclt "github.com/qbeon/webwire-go/client"
+)
+
+// blockingSessionManager returns a session manager blocking the creation
+// of sessions until the creation context is cancelled
+func blockingSessionManager(
+	creationStarted *tmdwg.TimedWaitGroup,
+) *callbackPoweredSessionManager {
+	return &callbackPoweredSessionManager{
+		SessionCreated: func(ctx context.Context, _ wwr.Connection) error {
+			creationStarted.Progress(1)
+			<-ctx.Done()
+			return ctx.Err()
+		},
+	}
+}
+
+// TestSessionCreationCancelled tests the cancellation of a session creation
+// due to the context being cancelled
+func TestSessionCreationCancelled(t *testing.T) {
+	creationStarted := tmdwg.NewTimedWaitGroup(1, 1*time.Second)
+	sessionClosed := tmdwg.NewTimedWaitGroup(1, 1*time.Second)
+
+	// Initialize server
+	server := setupServer(
+		t,
+		&serverImpl{
+			onRequest: func(
+				ctx context.Context,
+				conn wwr.Connection,
+				_ wwr.Message,
+			) (wwr.Payload, error) {
+				ctx, cancel := context.WithTimeout(ctx, 50*time.Millisecond)
+				defer cancel()
+
+				err := conn.CreateSession(ctx, nil)
+				assert.IsType(t, wwr.SessionCreationCancelledErr{}, err)
+				assert.False(t, conn.HasSession())
+				return nil, nil
+			},
+		},
+		wwr.ServerOptions{
+			SessionManager: blockingSessionManager(creationStarted),
+		},
+	)
+
+	// Initialize client
+	client := newCallbackPoweredClient(
+		server.Addr().String(),
+		wwrclt.Options{
+			DefaultRequestTimeout: 2 * time.Second,
+		},
+		callbackPoweredClientHooks{
+			OnSessionClosed: func() {
+				sessionClosed.Progress(1)
+			},
+		},
+	)
+	defer client.connection.Close()
+
+	require.NoError(t, client.connection.Connect())
+
+	_, err := client.connection.Request(context.Background(), "create", nil)
+	require.NoError(t, err)
+	require.NoError(t, creationStarted.Wait())
+	require.NoError(t, sessionClosed.Wait())
+	require.Nil(t, client.connection.Session())
+}
+
+// TestSessionCreationCancelledOnDisconnect tests the cancellation
+// of a session creation due to the connection being closed
+func TestSessionCreationCancelledOnDisconnect(t *testing.T) {
+	creationStarted := tmdwg.NewTimedWaitGroup(1, 1*time.Second)
+	creationCancelled := tmdwg.NewTimedWaitGroup(1, 2*time.Second)
+
+	// Initialize server
+	server := setupServer(
+		t,
+		&serverImpl{
+			onRequest: func(
+				_ context.Context,
+				conn wwr.Connection,
+				_ wwr.Message,
+			) (wwr.Payload, error) {
+				err := conn.CreateSession(context.Background(), nil)
+				assert.IsType(t, wwr.SessionCreationCancelledErr{}, err)
+				creationCancelled.Progress(1)
+				return nil, nil
+			},
+		},
+		wwr.ServerOptions{
+			SessionManager: blockingSessionManager(creationStarted),
+		},
+	)
+
+	// Initialize client
+	client := newCallbackPoweredClient(
+		server.Addr().String(),
+		wwrclt.Options{
+			Autoconnect:           wwr.Disabled,
+			DefaultRequestTimeout: 200 * time.Millisecond,
+		},
+		callbackPoweredClientHooks{},
+	)
+
+	require.NoError(t, client.connection.Connect())
+
+	go func() {
+		client.connection.Request(context.Background(), "create", nil)
+	}()
+
+	// Disconnect during the session creation,
+	// closing the client awaits the request to time out first
+	require.NoError(t, creationStarted.Wait())
+	client.connection.Close()
+
+	require.NoError(t, creationCancelled.Wait())
+}
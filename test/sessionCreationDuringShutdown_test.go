@@ -0,0 +1,103 @@
+package test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	wwr "github.com/qbeon/webwire-go"
+	wwrclt "github.com/qbeon/webwire-go/client"
+	"github.com/qbeon/webwire-go/wwrtest"
+)
+
+// TestSessionCreationDuringShutdown tests whether session creation
+// fails with a ReqSrvShutdownErr while the server is being shut down
+func TestSessionCreationDuringShutdown(t *testing.T) {
+	handlerEntered := make(chan struct{})
+	serverShuttingDown := make(chan struct{})
+
+	// Initialize webwire server
+	server := setupServer(
+		t,
+		&serverImpl{
+			onRequest: func(
+				_ context.Context,
+				conn wwr.Connection,
+				msg wwr.Message,
+			) (wwr.Payload, error) {
+				if msg.Name() != "login" {
+					return nil, nil
+				}
+				close(handlerEntered)
+				<-serverShuttingDown
+
+				// Try to create sessions during the shutdown
+				assert.IsType(t,
+					wwr.ReqSrvShutdownErr{},
+					conn.CreateEphemeralSession(nil),
+				)
+				err := conn.CreateSession(context.Background(), nil)
+				assert.IsType(t, wwr.ReqSrvShutdownErr{}, err)
+				return nil, err
+			},
+		},
+		wwr.ServerOptions{
+			SessionManager: wwrtest.NewInMemSessionManager(),
+		},
+	)
+
+	cltOpts := wwrclt.Options{
+		DefaultRequestTimeout: 2 * time.Second,
+		Autoconnect:           wwr.Disabled,
+	}
+	client := newCallbackPoweredClient(
+		server.Addr().String(),
+		cltOpts,
+		callbackPoweredClientHooks{},
+	)
+	defer client.connection.Close()
+	probeClient := newCallbackPoweredClient(
+		server.Addr().String(),
+		cltOpts,
+		callbackPoweredClientHooks{},
+	)
+	defer probeClient.connection.Close()
+	require.NoError(t, client.connection.Connect())
+	require.NoError(t, probeClient.connection.Connect())
+
+	// Send the session creation request
+	replied := make(chan error, 1)
+	go func() {
+		_, err := client.connection.Request(
+			context.Background(),
+			"login",
+			nil,
+		)
+		replied <- err
+	}()
+
+	// Start the shutdown while the handler is executed
+	<-handlerEntered
+	shutDown := make(chan error, 1)
+	go func() { shutDown <- server.Shutdown() }()
+
+	// Wait until the server rejects requests due to the shutdown
+	awaitCondition(t, func() bool {
+		_, err := probeClient.connection.Request(
+			context.Background(),
+			"probe",
+			nil,
+		)
+		_, isShutdownErr := err.(wwr.ReqSrvShutdownErr)
+		return isShutdownErr
+	})
+	close(serverShuttingDown)
+
+	// Expect the session creation to have failed
+	require.IsType(t, wwr.ReqSrvShutdownErr{}, <-replied)
+	require.Nil(t, client.connection.Session())
+	require.NoError(t, <-shutDown)
+}
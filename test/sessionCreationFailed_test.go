@@ -0,0 +1,71 @@
+package test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	tmdwg "github.com/qbeon/tmdwg-go"
+	wwr "github.com/qbeon/webwire-go"
+	wwrclt "github.com/qbeon/webwire-go/client"
+)
+
+// TestSessionCreationFailed tests whether the session creation is rolled back
+// when the OnSessionCreated hook of the session manager fails
+func TestSessionCreationFailed(t *testing.T) {
+	sessionClosed := tmdwg.NewTimedWaitGroup(1, 1*time.Second)
+
+	// Initialize server
+	server := setupServer(
+		t,
+		&serverImpl{
+			onRequest: func(
+				ctx context.Context,
+				conn wwr.Connection,
+				_ wwr.Message,
+			) (wwr.Payload, error) {
+				err := conn.CreateSession(ctx, nil)
+				assert.IsType(t, wwr.SessionCreationFailedErr{}, err)
+				assert.False(t, conn.HasSession())
+				return nil, nil
+			},
+		},
+		wwr.ServerOptions{
+			SessionManager: &callbackPoweredSessionManager{
+				SessionCreated: func(context.Context, wwr.Connection) error {
+					return fmt.Errorf("persistence failure")
+				},
+				SessionClosed: func(string) error {
+					t.Error("unexpected OnSessionClosed hook call")
+					return nil
+				},
+			},
+		},
+	)
+
+	// Initialize client
+	client := newCallbackPoweredClient(
+		server.Addr().String(),
+		wwrclt.Options{
+			DefaultRequestTimeout: 2 * time.Second,
+		},
+		callbackPoweredClientHooks{
+			OnSessionClosed: func() {
+				sessionClosed.Progress(1)
+			},
+		},
+	)
+	defer client.connection.Close()
+
+	require.NoError(t, client.connection.Connect())
+
+	_, err := client.connection.Request(context.Background(), "create", nil)
+	require.NoError(t, err)
+	require.NoError(t, sessionClosed.Wait())
+	require.Nil(t, client.connection.Session())
+	require.Equal(t, 0, server.ActiveSessionsNum())
+}
@@ -1,6 +1,7 @@
 package test
 
 import (
+	"context"
 	"testing"
 	"time"
 
@@ -21,12 +22,15 @@ func TestSessionCreationOnClosedConn(t *testing.T) {
 		&serverImpl{
 			onClientConnected: func(conn wwr.Connection) {
 				conn.Close()
-				err := conn.CreateSession(nil)
+				err := conn.CreateSession(context.Background(), nil)
 				assert.Error(t, err)
 				assert.IsType(t, wwr.DisconnectedErr{}, err)
 			},
-			onClientDisconnected: func(conn wwr.Connection) {
-				err := conn.CreateSession(nil)
+			onClientDisconnected: func(
+				conn wwr.Connection,
+				_ wwr.DisconnectReason,
+			) {
+				err := conn.CreateSession(context.Background(), nil)
 				assert.Error(t, err)
 				assert.IsType(t, wwr.DisconnectedErr{}, err)
 			},
@@ -0,0 +1,64 @@
+package test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	wwr "github.com/qbeon/webwire-go"
+	msg "github.com/qbeon/webwire-go/message"
+)
+
+// testSessionEncoding tests whether a raw client receives the session
+// creation notification of the expected message type
+func testSessionEncoding(
+	t *testing.T,
+	binarySessionEncoding wwr.OptionValue,
+	expectedType byte,
+) {
+	// Initialize server
+	server := setupServer(
+		t,
+		&serverImpl{
+			onClientConnected: func(conn wwr.Connection) {
+				assert.NoError(t, conn.CreateSession(
+					context.Background(),
+					nil,
+				))
+			},
+		},
+		wwr.ServerOptions{
+			BinarySessionEncoding: binarySessionEncoding,
+		},
+	)
+
+	conn := dialRaw(t, server)
+	defer conn.Close()
+
+	// Await the session creation notification
+	require.NoError(t, conn.SetReadDeadline(time.Now().Add(2*time.Second)))
+	for {
+		_, message, err := conn.ReadMessage()
+		require.NoError(t, err)
+		if message[0] == msg.MsgSessionCreated ||
+			message[0] == msg.MsgSessionCreatedUtf8 {
+			require.Equal(t, expectedType, message[0])
+			return
+		}
+	}
+}
+
+// TestSessionEncodingDefault tests whether session creation notifications
+// are UTF8 encoded by default
+func TestSessionEncodingDefault(t *testing.T) {
+	testSessionEncoding(t, wwr.OptionUnset, msg.MsgSessionCreatedUtf8)
+}
+
+// TestSessionEncodingBinary tests whether session creation notifications
+// are binary encoded when ServerOptions.BinarySessionEncoding is enabled
+func TestSessionEncodingBinary(t *testing.T) {
+	testSessionEncoding(t, wwr.Enabled, msg.MsgSessionCreated)
+}
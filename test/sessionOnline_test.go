@@ -0,0 +1,64 @@
+package test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	tmdwg "github.com/qbeon/tmdwg-go"
+	wwr "github.com/qbeon/webwire-go"
+	wwrclt "github.com/qbeon/webwire-go/client"
+)
+
+// TestSessionOnline tests querying whether a session is currently connected
+func TestSessionOnline(t *testing.T) {
+	disconnected := tmdwg.NewTimedWaitGroup(1, 1*time.Second)
+
+	// Initialize webwire server creating a session on request
+	server := setupServer(
+		t,
+		&serverImpl{
+			onClientDisconnected: func(
+				_ wwr.Connection,
+				_ wwr.DisconnectReason,
+			) {
+				disconnected.Progress(1)
+			},
+			onRequest: func(
+				ctx context.Context,
+				conn wwr.Connection,
+				_ wwr.Message,
+			) (wwr.Payload, error) {
+				return nil, conn.CreateSession(ctx, nil)
+			},
+		},
+		wwr.ServerOptions{},
+	)
+
+	// Initialize client
+	client := newCallbackPoweredClient(
+		server.Addr().String(),
+		wwrclt.Options{
+			Autoconnect:           wwr.Disabled,
+			DefaultRequestTimeout: 2 * time.Second,
+		},
+		callbackPoweredClientHooks{},
+	)
+
+	require.NoError(t, client.connection.Connect())
+	_, err := client.connection.Request(context.Background(), "login", nil)
+	require.NoError(t, err)
+
+	sessionKey := client.connection.Session().Key
+	require.True(t, server.IsOnline(sessionKey))
+	require.Equal(t, 1, server.ActiveSessionConnections(sessionKey))
+	require.False(t, server.IsOnline("inexistent"))
+
+	// Expect the session to be offline after the client disconnected
+	client.connection.Close()
+	require.NoError(t, disconnected.Wait())
+	require.False(t, server.IsOnline(sessionKey))
+	require.Equal(t, 0, server.ActiveSessionConnections(sessionKey))
+}
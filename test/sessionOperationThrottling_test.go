@@ -0,0 +1,87 @@
+package test
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	wwr "github.com/qbeon/webwire-go"
+	webwireClient "github.com/qbeon/webwire-go/client"
+)
+
+// TestSessionOperationThrottling tests whether session operations
+// exceeding ServerOptions.MaxSessionOperations are rejected
+// with a SessionOperationThrottledErr until the interval elapsed
+// while session closures initiated by the server aren't throttled
+func TestSessionOperationThrottling(t *testing.T) {
+	clock := &manualClock{
+		lock: &sync.Mutex{},
+		now:  time.Date(2000, 1, 1, 0, 0, 0, 0, time.UTC),
+	}
+
+	// Initialize webwire server
+	server := setupServer(
+		t,
+		&serverImpl{
+			onRequest: func(
+				ctx context.Context,
+				conn wwr.Connection,
+				msg wwr.Message,
+			) (wwr.Payload, error) {
+				if msg.Name() == "close" {
+					return nil, conn.CloseSession()
+				}
+				return nil, conn.CreateSession(ctx, nil)
+			},
+		},
+		wwr.ServerOptions{
+			Clock:                     clock,
+			MaxSessionOperations:      3,
+			SessionOperationsInterval: 1 * time.Second,
+		},
+	)
+
+	// Initialize client
+	client := newCallbackPoweredClient(
+		server.Addr().String(),
+		webwireClient.Options{
+			DefaultRequestTimeout: 2 * time.Second,
+		},
+		callbackPoweredClientHooks{},
+	)
+	require.NoError(t, client.connection.Connect())
+
+	request := func(name string) error {
+		_, err := client.connection.Request(context.Background(), name, nil)
+		return err
+	}
+
+	// Create and close sessions in a loop until the limit is exceeded
+	require.NoError(t, request("create"))
+	require.NoError(t, client.connection.CloseSession())
+	require.NoError(t, request("create"))
+
+	// Expect client-side session closures to be throttled
+	err := client.connection.CloseSession()
+	require.IsType(t, wwr.SessionOperationThrottledErr{}, err)
+	require.NotNil(t, client.connection.Session())
+
+	// Expect session closures initiated by the server not to be throttled
+	_, _, err = server.CloseSession(client.connection.Session().Key)
+	require.NoError(t, err)
+	awaitCondition(t, func() bool {
+		return client.connection.Session() == nil
+	})
+
+	// Expect session creations to be throttled as well
+	require.IsType(t, wwr.SessionOperationThrottledErr{}, request("create"))
+
+	// Expect session operations to be allowed again
+	// after the interval elapsed
+	clock.Advance(1 * time.Second)
+	require.NoError(t, request("create"))
+	require.NoError(t, request("close"))
+}
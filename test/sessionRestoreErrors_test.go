@@ -0,0 +1,101 @@
+//go:build go1.13
+// +build go1.13
+
+package test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	wwr "github.com/qbeon/webwire-go"
+	wwrclt "github.com/qbeon/webwire-go/client"
+)
+
+// restoreSession connects a new client to the given server
+// and tries to restore the session identified by the given key
+func restoreSession(t *testing.T, server wwr.Server, key []byte) error {
+	client := newCallbackPoweredClient(
+		server.Addr().String(),
+		wwrclt.Options{
+			DefaultRequestTimeout: 2 * time.Second,
+		},
+		callbackPoweredClientHooks{},
+	)
+	defer client.connection.Close()
+
+	require.NoError(t, client.connection.Connect())
+	return client.connection.RestoreSession(key)
+}
+
+// TestSessionRestoreErrSessionsDisabled tests whether the error returned
+// by a session restoration on a server with sessions disabled
+// can be caught by errors.As
+func TestSessionRestoreErrSessionsDisabled(t *testing.T) {
+	server := setupServer(
+		t,
+		&serverImpl{},
+		wwr.ServerOptions{
+			Sessions: wwr.Disabled,
+		},
+	)
+
+	err := restoreSession(t, server, []byte("inexistent"))
+
+	var expected wwrclt.SessionsDisabledErr
+	require.True(t, errors.As(err, &expected), err)
+}
+
+// TestSessionRestoreErrSessNotFound tests whether the error returned
+// by the restoration of an inexistent session can be caught by errors.As
+func TestSessionRestoreErrSessNotFound(t *testing.T) {
+	server := setupServer(t, &serverImpl{}, wwr.ServerOptions{})
+
+	err := restoreSession(t, server, []byte("inexistent"))
+
+	var expected wwrclt.SessNotFoundErr
+	require.True(t, errors.As(err, &expected), err)
+}
+
+// TestSessionRestoreErrMaxSessConnsReached tests whether the error returned
+// by the restoration of a session that reached the maximum number
+// of concurrent connections can be caught by errors.As
+func TestSessionRestoreErrMaxSessConnsReached(t *testing.T) {
+	server := setupServer(
+		t,
+		&serverImpl{
+			onRequest: func(
+				ctx context.Context,
+				conn wwr.Connection,
+				_ wwr.Message,
+			) (wwr.Payload, error) {
+				return nil, conn.CreateSession(ctx, nil)
+			},
+		},
+		wwr.ServerOptions{
+			MaxSessionConnections: 1,
+		},
+	)
+
+	// Create a session on a first client
+	client := newCallbackPoweredClient(
+		server.Addr().String(),
+		wwrclt.Options{
+			DefaultRequestTimeout: 2 * time.Second,
+		},
+		callbackPoweredClientHooks{},
+	)
+	defer client.connection.Close()
+
+	require.NoError(t, client.connection.Connect())
+	_, err := client.connection.Request(context.Background(), "login", nil)
+	require.NoError(t, err)
+
+	err = restoreSession(t, server, []byte(client.connection.Session().Key))
+
+	var expected wwrclt.MaxSessConnsReachedErr
+	require.True(t, errors.As(err, &expected), err)
+}
@@ -0,0 +1,93 @@
+package test
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/stretchr/testify/require"
+
+	wwr "github.com/qbeon/webwire-go"
+	msg "github.com/qbeon/webwire-go/message"
+)
+
+// rejectingRegistryBackend is a session registry backend
+// rejecting the registration of connections of the given session
+type rejectingRegistryBackend struct {
+	wwr.SessionRegistryBackend
+	rejectedKey string
+}
+
+// Register implements the SessionRegistryBackend interface
+func (bck *rejectingRegistryBackend) Register(
+	sessionKey string,
+	maxConns uint,
+) error {
+	if sessionKey == bck.rejectedKey {
+		return fmt.Errorf("rejected")
+	}
+	return bck.SessionRegistryBackend.Register(sessionKey, maxConns)
+}
+
+// TestSessionRestoreRegistrationFailure tests whether the previously active
+// session is kept when the registration of the restored session fails
+func TestSessionRestoreRegistrationFailure(t *testing.T) {
+	// Initialize webwire server finding any session
+	server := setupServer(
+		t,
+		&serverImpl{},
+		wwr.ServerOptions{
+			SessionRegistryBackend: &rejectingRegistryBackend{
+				SessionRegistryBackend: wwr.NewInMemSessionRegistryBackend(),
+				rejectedKey:            "session_b",
+			},
+			SessionManager: &callbackPoweredSessionManager{
+				SessionLookup: func(
+					_ context.Context,
+					_ string,
+				) (wwr.SessionLookupResult, error) {
+					return wwr.NewSessionLookupResult(
+						time.Now(),
+						time.Now(),
+						nil,
+					), nil
+				},
+			},
+		},
+	)
+
+	// Connect a raw websocket
+	conn, _, err := websocket.DefaultDialer.Dial(
+		(&url.URL{Scheme: "ws", Host: server.Addr().String()}).String(),
+		nil,
+	)
+	require.NoError(t, err)
+	defer conn.Close()
+
+	// restore restores the session of the given key
+	// and returns the type of the reply
+	restore := func(key string) byte {
+		require.NoError(t, conn.WriteMessage(
+			websocket.BinaryMessage,
+			msg.NewNamelessRequestMessage(
+				msg.MsgRestoreSession,
+				[8]byte{1},
+				[]byte(key),
+			),
+		))
+		_, reply, err := conn.ReadMessage()
+		require.NoError(t, err)
+		return reply[0]
+	}
+
+	require.Equal(t, msg.MsgReplyUtf8, restore("session_a"))
+
+	// Expect the previous session to remain active
+	require.Equal(t, msg.MsgMaxSessConnsReached, restore("session_b"))
+	require.Equal(t, 1, server.ActiveSessionConnections("session_a"))
+	require.Equal(t, 1, server.SessionConnectionsNum("session_a"))
+	require.Equal(t, 0, server.ActiveSessionConnections("session_b"))
+}
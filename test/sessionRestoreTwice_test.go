@@ -0,0 +1,76 @@
+package test
+
+import (
+	"context"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/stretchr/testify/require"
+
+	wwr "github.com/qbeon/webwire-go"
+	msg "github.com/qbeon/webwire-go/message"
+)
+
+// TestSessionRestoreTwice tests restoring sessions multiple times
+// on the same connection expecting the connection to be registered
+// only once
+func TestSessionRestoreTwice(t *testing.T) {
+	// Initialize webwire server finding any session
+	server := setupServer(
+		t,
+		&serverImpl{},
+		wwr.ServerOptions{
+			MaxSessionConnections: 1,
+			SessionManager: &callbackPoweredSessionManager{
+				SessionLookup: func(
+					_ context.Context,
+					_ string,
+				) (wwr.SessionLookupResult, error) {
+					return wwr.NewSessionLookupResult(
+						time.Now(),
+						time.Now(),
+						nil,
+					), nil
+				},
+			},
+		},
+	)
+
+	// Connect a raw websocket
+	conn, _, err := websocket.DefaultDialer.Dial(
+		(&url.URL{Scheme: "ws", Host: server.Addr().String()}).String(),
+		nil,
+	)
+	require.NoError(t, err)
+	defer conn.Close()
+
+	// restore restores the session of the given key
+	// expecting it to succeed
+	restore := func(key string) {
+		require.NoError(t, conn.WriteMessage(
+			websocket.BinaryMessage,
+			msg.NewNamelessRequestMessage(
+				msg.MsgRestoreSession,
+				[8]byte{1},
+				[]byte(key),
+			),
+		))
+		_, reply, err := conn.ReadMessage()
+		require.NoError(t, err)
+		require.Equal(t, msg.MsgReplyUtf8, reply[0], "restoring %s", key)
+	}
+
+	// Expect the connection to be registered only once
+	restore("session_a")
+	restore("session_a")
+	require.Equal(t, 1, server.ActiveSessionConnections("session_a"))
+	require.Equal(t, 1, server.SessionConnectionsNum("session_a"))
+
+	// Expect the previous session to be replaced
+	restore("session_b")
+	require.Equal(t, 0, server.ActiveSessionConnections("session_a"))
+	require.Equal(t, 1, server.ActiveSessionConnections("session_b"))
+	require.Equal(t, 1, server.SessionConnectionsNum("session_b"))
+}
@@ -22,7 +22,7 @@ func TestSessionStatus(t *testing.T) {
 				_ wwr.Message,
 			) (wwr.Payload, error) {
 				// Try to create a new session
-				if err := conn.CreateSession(nil); err != nil {
+				if err := conn.CreateSession(context.Background(), nil); err != nil {
 					return nil, err
 				}
 
@@ -0,0 +1,85 @@
+package test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	tmdwg "github.com/qbeon/tmdwg-go"
+	wwr "github.com/qbeon/webwire-go"
+	wwrclt "github.com/qbeon/webwire-go/client"
+)
+
+// TestSetImplementation tests whether requests issued after
+// the implementation was replaced are dispatched to the new implementation
+// while in-flight requests are completed by the previous one
+func TestSetImplementation(t *testing.T) {
+	inFlight := tmdwg.NewTimedWaitGroup(1, 1*time.Second)
+	release := make(chan struct{})
+
+	replyWith := func(reply string) func(
+		context.Context,
+		wwr.Connection,
+		wwr.Message,
+	) (wwr.Payload, error) {
+		return func(
+			_ context.Context,
+			_ wwr.Connection,
+			msg wwr.Message,
+		) (wwr.Payload, error) {
+			if msg.Name() == "blocking" {
+				inFlight.Progress(1)
+				<-release
+			}
+			return wwr.NewPayload(wwr.EncodingUtf8, []byte(reply)), nil
+		}
+	}
+
+	// Initialize webwire server
+	server := setupServer(
+		t,
+		&serverImpl{onRequest: replyWith("previous")},
+		wwr.ServerOptions{},
+	)
+
+	// Initialize client
+	client := newCallbackPoweredClient(
+		server.Addr().String(),
+		wwrclt.Options{
+			DefaultRequestTimeout: 2 * time.Second,
+		},
+		callbackPoweredClientHooks{},
+	)
+	defer client.connection.Close()
+	require.NoError(t, client.connection.Connect())
+
+	// Issue a request blocking the previous implementation
+	inFlightReply := make(chan wwr.Payload, 1)
+	go func() {
+		reply, err := client.connection.Request(
+			context.Background(),
+			"blocking",
+			nil,
+		)
+		assert.NoError(t, err)
+		inFlightReply <- reply
+	}()
+	require.NoError(t, inFlight.Wait())
+
+	server.SetImplementation(&serverImpl{onRequest: replyWith("new")})
+
+	// Expect new requests to be dispatched to the new implementation
+	reply, err := client.connection.Request(context.Background(), "r", nil)
+	require.NoError(t, err)
+	require.Equal(t, []byte("new"), reply.Data())
+
+	// Expect the in-flight request to be completed
+	// by the previous implementation
+	close(release)
+	require.Equal(t, []byte("previous"), (<-inFlightReply).Data())
+
+	require.Panics(t, func() { server.SetImplementation(nil) })
+}
@@ -0,0 +1,77 @@
+package test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	wwr "github.com/qbeon/webwire-go"
+	wwrclt "github.com/qbeon/webwire-go/client"
+	"github.com/qbeon/webwire-go/wwrtest"
+)
+
+// TestSharedSessRegBackend tests enforcing the maximum number of concurrent
+// session connections across two servers sharing a session registry backend
+func TestSharedSessRegBackend(t *testing.T) {
+	sessionManager := wwrtest.NewInMemSessionManager()
+	backend := wwr.NewInMemSessionRegistryBackend()
+	opts := wwr.ServerOptions{
+		MaxSessionConnections:  1,
+		SessionManager:         sessionManager,
+		SessionRegistryBackend: backend,
+	}
+
+	// Initialize two servers sharing the session registry backend
+	serverA := setupServer(
+		t,
+		&serverImpl{
+			onClientConnected: func(conn wwr.Connection) {
+				assert.NoError(t, conn.CreateSession(context.Background(), nil))
+			},
+		},
+		opts,
+	)
+	serverB := setupServer(t, &serverImpl{}, opts)
+
+	// Create a session on server A
+	sessionCreated := make(chan *wwr.Session, 1)
+	clientA := newCallbackPoweredClient(
+		serverA.Addr().String(),
+		wwrclt.Options{
+			DefaultRequestTimeout: 2 * time.Second,
+		},
+		callbackPoweredClientHooks{
+			OnSessionCreated: func(session *wwr.Session) {
+				sessionCreated <- session
+			},
+		},
+	)
+	defer clientA.connection.Close()
+	require.NoError(t, clientA.connection.Connect())
+
+	var session *wwr.Session
+	select {
+	case session = <-sessionCreated:
+	case <-time.After(1 * time.Second):
+		t.Fatal("Session not created")
+	}
+	require.Equal(t, 1, serverB.SessionConnectionsNum(session.Key))
+
+	// Try to restore the session on server B
+	// and expect the shared connections limit to be enforced
+	clientB := newCallbackPoweredClient(
+		serverB.Addr().String(),
+		wwrclt.Options{
+			DefaultRequestTimeout: 2 * time.Second,
+		},
+		callbackPoweredClientHooks{},
+	)
+	defer clientB.connection.Close()
+	require.NoError(t, clientB.connection.Connect())
+
+	err := clientB.connection.RestoreSession([]byte(session.Key))
+	require.IsType(t, wwr.MaxSessConnsReachedErr{}, err)
+}
@@ -0,0 +1,74 @@
+package test
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	tmdwg "github.com/qbeon/tmdwg-go"
+	wwr "github.com/qbeon/webwire-go"
+	wwrclt "github.com/qbeon/webwire-go/client"
+)
+
+// TestShutdownProgress tests the reporting of the shutdown progress
+// while pending operations are drained
+func TestShutdownProgress(t *testing.T) {
+	handlerExecutionDuration := 300 * time.Millisecond
+	requestArrived := tmdwg.NewTimedWaitGroup(1, 1*time.Second)
+	progressReports := uint32(0)
+
+	// Initialize webwire server
+	server := setupServer(
+		t,
+		&serverImpl{
+			onRequest: func(
+				_ context.Context,
+				_ wwr.Connection,
+				_ wwr.Message,
+			) (wwr.Payload, error) {
+				requestArrived.Progress(1)
+				time.Sleep(handlerExecutionDuration)
+				return nil, nil
+			},
+		},
+		wwr.ServerOptions{
+			ShutdownProgressInterval: 50 * time.Millisecond,
+			OnShutdownProgress: func(remaining uint32) {
+				// The last report may race the completion of the handler
+				assert.True(t, remaining <= 1)
+				atomic.AddUint32(&progressReports, 1)
+			},
+		},
+	)
+
+	// Initialize client
+	client := newCallbackPoweredClient(
+		server.Addr().String(),
+		wwrclt.Options{
+			DefaultRequestTimeout: 2 * time.Second,
+			Autoconnect:           wwr.Disabled,
+		},
+		callbackPoweredClientHooks{},
+	)
+	require.NoError(t, client.connection.Connect())
+
+	go func() {
+		_, err := client.connection.Request(
+			context.Background(),
+			"test",
+			nil,
+		)
+		assert.NoError(t, err)
+	}()
+
+	require.NoError(t, requestArrived.Wait())
+	require.Equal(t, uint32(1), server.PendingOps())
+
+	require.NoError(t, server.Shutdown())
+	require.Equal(t, uint32(0), server.PendingOps())
+	require.True(t, atomic.LoadUint32(&progressReports) > 0)
+}
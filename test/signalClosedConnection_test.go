@@ -0,0 +1,96 @@
+package test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	tmdwg "github.com/qbeon/tmdwg-go"
+	wwr "github.com/qbeon/webwire-go"
+	wwrclt "github.com/qbeon/webwire-go/client"
+)
+
+// TestSignalClosedConnection tests whether signaling a just-closed
+// connection returns a DisconnectedErr
+func TestSignalClosedConnection(t *testing.T) {
+	connected := tmdwg.NewTimedWaitGroup(1, 1*time.Second)
+	var serverSideConn wwr.Connection
+
+	// Initialize webwire server
+	server := setupServer(
+		t,
+		&serverImpl{
+			onClientConnected: func(conn wwr.Connection) {
+				serverSideConn = conn
+				connected.Progress(1)
+			},
+		},
+		wwr.ServerOptions{},
+	)
+
+	// Initialize client
+	client := newCallbackPoweredClient(
+		server.Addr().String(),
+		wwrclt.Options{
+			DefaultRequestTimeout: 2 * time.Second,
+		},
+		callbackPoweredClientHooks{},
+	)
+	defer client.connection.Close()
+	require.NoError(t, client.connection.Connect())
+	require.NoError(t, connected.Wait())
+
+	// Close the connection and signal it right away
+	serverSideConn.Close()
+	err := serverSideConn.Signal(
+		"test",
+		wwr.NewPayload(wwr.EncodingBinary, []byte("test")),
+	)
+	require.Error(t, err)
+	require.IsType(t, wwr.DisconnectedErr{}, err)
+}
+
+// TestSignalClosedConnectionPendingTasks tests whether signaling a connection
+// closed during the handling of a request returns a DisconnectedErr
+// even though its socket isn't closed before the request is handled
+func TestSignalClosedConnectionPendingTasks(t *testing.T) {
+	// Initialize webwire server
+	server := setupServer(
+		t,
+		&serverImpl{
+			onRequest: func(
+				_ context.Context,
+				conn wwr.Connection,
+				_ wwr.Message,
+			) (wwr.Payload, error) {
+				conn.Close()
+				err := conn.Signal(
+					"test",
+					wwr.NewPayload(wwr.EncodingBinary, []byte("test")),
+				)
+				assert.Error(t, err)
+				assert.IsType(t, wwr.DisconnectedErr{}, err)
+				return nil, nil
+			},
+		},
+		wwr.ServerOptions{},
+	)
+
+	// Initialize client
+	client := newCallbackPoweredClient(
+		server.Addr().String(),
+		wwrclt.Options{
+			DefaultRequestTimeout: 2 * time.Second,
+			Autoconnect:           wwr.Disabled,
+		},
+		callbackPoweredClientHooks{},
+	)
+	defer client.connection.Close()
+	require.NoError(t, client.connection.Connect())
+
+	// Send the request, its reply is irrelevant
+	client.connection.Request(context.Background(), "close", nil)
+}
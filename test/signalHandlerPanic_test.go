@@ -0,0 +1,74 @@
+package test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	tmdwg "github.com/qbeon/tmdwg-go"
+	wwr "github.com/qbeon/webwire-go"
+	wwrclt "github.com/qbeon/webwire-go/client"
+)
+
+// TestSignalHandlerPanic tests recovering from panics in the signal handler
+func TestSignalHandlerPanic(t *testing.T) {
+	hookCalled := tmdwg.NewTimedWaitGroup(1, 1*time.Second)
+
+	// Initialize webwire server with a panicking signal handler
+	server := setupServer(
+		t,
+		&serverImpl{
+			onSignal: func(
+				_ context.Context,
+				_ wwr.Connection,
+				_ wwr.Message,
+			) {
+				panic("expected panic")
+			},
+		},
+		wwr.ServerOptions{
+			OnSignalError: func(
+				conn wwr.Connection,
+				message wwr.Message,
+				recovered interface{},
+			) {
+				assert.NotNil(t, conn)
+				assert.Equal(t, "panic", message.Name())
+				assert.Equal(t, "expected panic", recovered)
+				hookCalled.Progress(1)
+			},
+		},
+	)
+
+	// Initialize client
+	client := newCallbackPoweredClient(
+		server.Addr().String(),
+		wwrclt.Options{
+			DefaultRequestTimeout: 2 * time.Second,
+		},
+		callbackPoweredClientHooks{},
+	)
+	defer client.connection.Close()
+
+	require.NoError(t, client.connection.Connect())
+
+	require.NoError(t, client.connection.Signal(
+		"panic",
+		wwr.NewPayload(wwr.EncodingBinary, []byte("test")),
+	))
+	require.NoError(t, hookCalled.Wait())
+
+	// Expect the pending operation of the signal to be released
+	deadline := time.Now().Add(1 * time.Second)
+	for server.PendingOps() > 0 && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+	}
+	require.Equal(t, uint32(0), server.PendingOps())
+
+	// Expect the connection to remain usable
+	_, err := client.connection.Request(context.Background(), "test", nil)
+	require.NoError(t, err)
+}
@@ -0,0 +1,84 @@
+package test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	tmdwg "github.com/qbeon/tmdwg-go"
+	wwr "github.com/qbeon/webwire-go"
+	wwrclt "github.com/qbeon/webwire-go/client"
+)
+
+// TestSignalSession tests signaling all connections of a single session
+func TestSignalSession(t *testing.T) {
+	signaled := tmdwg.NewTimedWaitGroup(2, 1*time.Second)
+	expectedPayload := wwr.NewPayload(wwr.EncodingUtf8, []byte("test"))
+
+	// Initialize webwire server
+	server := setupServer(
+		t,
+		&serverImpl{
+			onRequest: func(
+				_ context.Context,
+				conn wwr.Connection,
+				_ wwr.Message,
+			) (wwr.Payload, error) {
+				return nil, conn.CreateSession(context.Background(), nil)
+			},
+		},
+		wwr.ServerOptions{},
+	)
+
+	newClient := func(expectSignal bool) *callbackPoweredClient {
+		client := newCallbackPoweredClient(
+			server.Addr().String(),
+			wwrclt.Options{
+				DefaultRequestTimeout: 2 * time.Second,
+				Autoconnect:           wwr.Disabled,
+			},
+			callbackPoweredClientHooks{
+				OnSignal: func(msg wwr.Message) {
+					assert.True(t, expectSignal, "unexpected signal")
+					assert.Equal(t, "notify", msg.Name())
+					comparePayload(t, expectedPayload, msg.Payload())
+					signaled.Progress(1)
+				},
+			},
+		)
+		require.NoError(t, client.connection.Connect())
+		return client
+	}
+
+	// Create a session and connect a second client to it
+	target := newClient(true)
+	defer target.connection.Close()
+	_, err := target.connection.Request(context.Background(), "login", nil)
+	require.NoError(t, err)
+	sessionKey := target.connection.Session().Key
+
+	targetSecond := newClient(true)
+	defer targetSecond.connection.Close()
+	require.NoError(t, targetSecond.connection.RestoreSession(
+		[]byte(sessionKey),
+	))
+
+	// Create another session that mustn't be signaled
+	other := newClient(false)
+	defer other.connection.Close()
+	_, err = other.connection.Request(context.Background(), "login", nil)
+	require.NoError(t, err)
+
+	count, err := server.SignalSession(sessionKey, "notify", expectedPayload)
+	require.NoError(t, err)
+	require.Equal(t, 2, count)
+	require.NoError(t, signaled.Wait())
+
+	// Signaling an inexistent session signals no connections
+	count, err = server.SignalSession("inexistent", "notify", expectedPayload)
+	require.NoError(t, err)
+	require.Equal(t, 0, count)
+}
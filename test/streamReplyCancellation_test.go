@@ -0,0 +1,95 @@
+package test
+
+import (
+	"context"
+	"io"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	wwr "github.com/qbeon/webwire-go"
+	wwrclt "github.com/qbeon/webwire-go/client"
+)
+
+// TestStreamReplyCancellation tests whether the producer of a streamed reply
+// observes the cancellation when the client disconnects during the stream
+func TestStreamReplyCancellation(t *testing.T) {
+	streaming := make(chan struct{})
+	producerErr := make(chan error, 1)
+	producerCtxErr := make(chan error, 1)
+
+	// Initialize webwire server
+	server := setupServer(
+		t,
+		&serverImpl{
+			onRequest: func(
+				_ context.Context,
+				_ wwr.Connection,
+				_ wwr.Message,
+			) (wwr.Payload, error) {
+				return wwr.NewStreamWriterPayload(
+					wwr.EncodingBinary,
+					func(ctx context.Context, writer io.Writer) error {
+						chunk := make([]byte, 64)
+						once := sync.Once{}
+						for {
+							if _, err := writer.Write(chunk); err != nil {
+								producerErr <- err
+								<-ctx.Done()
+								producerCtxErr <- ctx.Err()
+								return err
+							}
+							once.Do(func() { close(streaming) })
+						}
+					},
+				), nil
+			},
+		},
+		wwr.ServerOptions{
+			ReplyChunkSize: 64,
+		},
+	)
+
+	// Initialize client
+	client := newCallbackPoweredClient(
+		server.Addr().String(),
+		wwrclt.Options{
+			DefaultRequestTimeout: 5 * time.Second,
+		},
+		callbackPoweredClientHooks{},
+	)
+
+	require.NoError(t, client.connection.Connect())
+
+	// Send a request streaming an endless reply
+	requestCtx, cancelRequest := context.WithCancel(context.Background())
+	go func() {
+		_, err := client.connection.Request(
+			requestCtx,
+			"stream",
+			nil,
+		)
+		assert.Error(t, err)
+	}()
+
+	// Disconnect the client while the reply is being streamed
+	select {
+	case <-streaming:
+	case <-time.After(2 * time.Second):
+		t.Fatal("Stream didn't start")
+	}
+	cancelRequest()
+	client.connection.Close()
+
+	// Verify the producer observed the disconnection
+	select {
+	case err := <-producerErr:
+		require.IsType(t, wwr.DisconnectedErr{}, err)
+	case <-time.After(2 * time.Second):
+		t.Fatal("Producer didn't observe the disconnection")
+	}
+	require.Equal(t, context.Canceled, <-producerCtxErr)
+}
@@ -0,0 +1,65 @@
+package test
+
+import (
+	"bytes"
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	wwr "github.com/qbeon/webwire-go"
+	wwrclt "github.com/qbeon/webwire-go/client"
+)
+
+// TestStreamReplyMaxSize tests whether the client fails requests
+// whose streamed replies exceed Options.MaxReplySize
+func TestStreamReplyMaxSize(t *testing.T) {
+	// Initialize webwire server
+	server := setupServer(
+		t,
+		&serverImpl{
+			onRequest: func(
+				_ context.Context,
+				_ wwr.Connection,
+				msg wwr.Message,
+			) (wwr.Payload, error) {
+				size := 100
+				if msg.Name() == "large" {
+					size = 1000
+				}
+				return wwr.NewStreamPayload(
+					wwr.EncodingBinary,
+					bytes.NewReader(make([]byte, size)),
+				), nil
+			},
+		},
+		wwr.ServerOptions{
+			ReplyChunkSize: 64,
+		},
+	)
+
+	// Initialize client accepting replies of up to 128 bytes
+	client := newCallbackPoweredClient(
+		server.Addr().String(),
+		wwrclt.Options{
+			DefaultRequestTimeout: 2 * time.Second,
+			MaxReplySize:          128,
+		},
+		callbackPoweredClientHooks{},
+	)
+	defer client.connection.Close()
+	require.NoError(t, client.connection.Connect())
+
+	_, err := client.connection.Request(context.Background(), "large", nil)
+	require.Equal(t, wwr.ReplyTooLargeErr{MaxSize: 128}, err)
+
+	// Expect replies within the limit to be reassembled
+	reply, err := client.connection.Request(
+		context.Background(),
+		"small",
+		nil,
+	)
+	require.NoError(t, err)
+	require.Len(t, reply.Data(), 100)
+}
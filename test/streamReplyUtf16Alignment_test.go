@@ -0,0 +1,59 @@
+package test
+
+import (
+	"bytes"
+	"context"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/stretchr/testify/require"
+
+	wwr "github.com/qbeon/webwire-go"
+	msg "github.com/qbeon/webwire-go/message"
+	pld "github.com/qbeon/webwire-go/payload"
+)
+
+// TestStreamReplyUtf16Alignment tests whether misaligned UTF16 encoded
+// streamed replies are failed before the misaligned chunk is sent
+func TestStreamReplyUtf16Alignment(t *testing.T) {
+	// Initialize webwire server streaming 65 bytes in chunks of 64 bytes
+	server := setupServer(
+		t,
+		&serverImpl{
+			onRequest: func(
+				_ context.Context,
+				_ wwr.Connection,
+				_ wwr.Message,
+			) (wwr.Payload, error) {
+				return wwr.NewStreamPayload(
+					wwr.EncodingUtf16,
+					bytes.NewReader(make([]byte, 65)),
+				), nil
+			},
+		},
+		wwr.ServerOptions{
+			ReplyChunkSize: 64,
+		},
+	)
+
+	conn := dialRaw(t, server)
+	defer conn.Close()
+	require.NoError(t, conn.SetReadDeadline(time.Now().Add(2*time.Second)))
+
+	require.NoError(t, conn.WriteMessage(
+		websocket.BinaryMessage,
+		msg.NewRequestMessage([8]byte{1}, "stream", pld.Binary, nil),
+	))
+
+	// Expect the aligned chunk to be sent
+	_, reply, err := conn.ReadMessage()
+	require.NoError(t, err)
+	require.Equal(t, msg.MsgReplyChunk, reply[0])
+	require.Len(t, reply, 9+64)
+
+	// Expect the request to be failed instead of the misaligned chunk
+	_, reply, err = conn.ReadMessage()
+	require.NoError(t, err)
+	require.Equal(t, msg.MsgInternalError, reply[0])
+}
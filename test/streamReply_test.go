@@ -0,0 +1,66 @@
+package test
+
+import (
+	"bytes"
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	wwr "github.com/qbeon/webwire-go"
+	wwrclt "github.com/qbeon/webwire-go/client"
+)
+
+// TestStreamReply tests streaming a reply in chunks
+// and its reassembly on the client
+func TestStreamReply(t *testing.T) {
+	expectedReplyData := make([]byte, 1000)
+	for i := range expectedReplyData {
+		expectedReplyData[i] = byte(i % 256)
+	}
+
+	// Initialize webwire server
+	server := setupServer(
+		t,
+		&serverImpl{
+			onRequest: func(
+				_ context.Context,
+				_ wwr.Connection,
+				_ wwr.Message,
+			) (wwr.Payload, error) {
+				return wwr.NewStreamPayload(
+					wwr.EncodingBinary,
+					bytes.NewReader(expectedReplyData),
+				), nil
+			},
+		},
+		wwr.ServerOptions{
+			// Split the reply into several chunks
+			ReplyChunkSize: 64,
+		},
+	)
+
+	// Initialize client
+	client := newCallbackPoweredClient(
+		server.Addr().String(),
+		wwrclt.Options{
+			DefaultRequestTimeout: 2 * time.Second,
+		},
+		callbackPoweredClientHooks{},
+	)
+
+	require.NoError(t, client.connection.Connect())
+
+	// Send request and await the reassembled reply
+	reply, err := client.connection.Request(
+		context.Background(),
+		"stream",
+		nil,
+	)
+	require.NoError(t, err)
+
+	// Verify reply
+	require.Equal(t, wwr.EncodingBinary, reply.Encoding())
+	require.Equal(t, expectedReplyData, reply.Data())
+}
@@ -2,13 +2,13 @@ package test
 
 import (
 	"context"
-	"fmt"
 	"net/http"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
 
 	wwr "github.com/qbeon/webwire-go"
+	"github.com/qbeon/webwire-go/wwrtest"
 )
 
 // setupServer helps setting up and launching the server
@@ -20,7 +20,14 @@ func setupServer(
 	opts wwr.ServerOptions,
 ) wwr.Server {
 	// Setup headed server on arbitrary port
+	setupServerImplDefaults(impl)
+	// The servers are left running until the test binary exits
+	server, _ := wwrtest.NewServer(t, impl, opts)
+	return server
+}
 
+// setupServerImplDefaults sets no-op defaults for undefined server hooks
+func setupServerImplDefaults(impl *serverImpl) {
 	if impl.beforeUpgrade == nil {
 		impl.beforeUpgrade = func(
 			_ http.ResponseWriter,
@@ -33,7 +40,11 @@ func setupServer(
 		impl.onClientConnected = func(_ wwr.Connection) {}
 	}
 	if impl.onClientDisconnected == nil {
-		impl.onClientDisconnected = func(_ wwr.Connection) {}
+		impl.onClientDisconnected = func(
+			_ wwr.Connection,
+			_ wwr.DisconnectReason,
+		) {
+		}
 	}
 	if impl.onSignal == nil {
 		impl.onSignal = func(
@@ -52,37 +63,6 @@ func setupServer(
 			return nil, nil
 		}
 	}
-
-	// Use default session manager if no specific one is defined
-	if opts.SessionManager == nil {
-		opts.SessionManager = newInMemSessManager()
-	}
-
-	// Use default address
-	opts.Address = "127.0.0.1:0"
-
-	// Use default heartbeat configuration if not set
-	if opts.Heartbeat == wwr.OptionUnset {
-		opts.Heartbeat = wwr.Disabled
-	}
-
-	server, err := wwr.NewServer(
-		impl,
-		opts,
-	)
-	if err != nil {
-		t.Fatalf("Failed setting up server instance: %s", err)
-	}
-
-	// Run server in a separate goroutine
-	go func() {
-		if err := server.Run(); err != nil {
-			panic(fmt.Errorf("Server failed: %s", err))
-		}
-	}()
-
-	// Return reference to the server and the address its bound to
-	return server
 }
 
 func comparePayload(t *testing.T, expected, actual wwr.Payload) {
@@ -0,0 +1,84 @@
+package test
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	wwr "github.com/qbeon/webwire-go"
+	wwrclt "github.com/qbeon/webwire-go/client"
+)
+
+type spanKey struct{}
+
+// recordingTracer implements the webwire.Tracer interface
+// recording all finished spans
+type recordingTracer struct {
+	lock     sync.Mutex
+	finished []string
+	errors   []error
+}
+
+// StartSpan implements the webwire.Tracer interface
+func (tracer *recordingTracer) StartSpan(
+	ctx context.Context,
+	name string,
+	message wwr.Message,
+) (context.Context, func(err error)) {
+	spanName := name + ":" + message.Name()
+	return context.WithValue(ctx, spanKey{}, spanName), func(err error) {
+		tracer.lock.Lock()
+		tracer.finished = append(tracer.finished, spanName)
+		tracer.errors = append(tracer.errors, err)
+		tracer.lock.Unlock()
+	}
+}
+
+// TestTracer tests starting spans around request handlers
+func TestTracer(t *testing.T) {
+	tracer := &recordingTracer{}
+	handlerErr := wwr.ReqErr{Code: "SAMPLE_ERR", Message: "sample error"}
+
+	// Initialize webwire server
+	server := setupServer(
+		t,
+		&serverImpl{
+			onRequest: func(
+				ctx context.Context,
+				_ wwr.Connection,
+				_ wwr.Message,
+			) (wwr.Payload, error) {
+				// Expect the span context to be propagated to the handler
+				assert.Equal(t, "request:traced", ctx.Value(spanKey{}))
+				return nil, handlerErr
+			},
+		},
+		wwr.ServerOptions{
+			Tracer: tracer,
+		},
+	)
+
+	// Initialize client
+	client := newCallbackPoweredClient(
+		server.Addr().String(),
+		wwrclt.Options{
+			DefaultRequestTimeout: 2 * time.Second,
+		},
+		callbackPoweredClientHooks{},
+	)
+	defer client.connection.Close()
+
+	require.NoError(t, client.connection.Connect())
+
+	_, err := client.connection.Request(context.Background(), "traced", nil)
+	require.Error(t, err)
+
+	tracer.lock.Lock()
+	defer tracer.lock.Unlock()
+	require.Equal(t, []string{"request:traced"}, tracer.finished)
+	require.Equal(t, []error{handlerErr}, tracer.errors)
+}
@@ -0,0 +1,72 @@
+//go:build go1.18
+// +build go1.18
+
+package test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	wwr "github.com/qbeon/webwire-go"
+	wwrclt "github.com/qbeon/webwire-go/client"
+)
+
+type typedRequest struct {
+	A int `json:"a"`
+	B int `json:"b"`
+}
+
+type typedReply struct {
+	Sum int `json:"sum"`
+}
+
+// TestTypedRequest tests typed requests handled by a typed handler
+func TestTypedRequest(t *testing.T) {
+	// Initialize webwire server
+	server := setupServer(
+		t,
+		&serverImpl{
+			onRequest: wwr.Handle(func(
+				_ context.Context,
+				_ wwr.Connection,
+				request typedRequest,
+			) (typedReply, error) {
+				return typedReply{Sum: request.A + request.B}, nil
+			}),
+		},
+		wwr.ServerOptions{},
+	)
+
+	// Initialize client
+	client := newCallbackPoweredClient(
+		server.Addr().String(),
+		wwrclt.Options{
+			DefaultRequestTimeout: 2 * time.Second,
+		},
+		callbackPoweredClientHooks{},
+	)
+	require.NoError(t, client.connection.Connect())
+
+	// Send a typed request
+	reply, err := wwrclt.Request[typedRequest, typedReply](
+		context.Background(),
+		client.connection,
+		"sum",
+		typedRequest{A: 2, B: 3},
+	)
+	require.NoError(t, err)
+	require.Equal(t, typedReply{Sum: 5}, reply)
+
+	// Send a request the typed handler can't decode
+	_, err = client.connection.Request(
+		context.Background(),
+		"sum",
+		wwr.NewPayload(wwr.EncodingUtf8, []byte("not json")),
+	)
+	require.Error(t, err)
+	require.IsType(t, wwr.ReqErr{}, err)
+	require.Equal(t, "DECODING_FAILURE", err.(wwr.ReqErr).Code)
+}
@@ -0,0 +1,56 @@
+package test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	wwr "github.com/qbeon/webwire-go"
+	wwrclt "github.com/qbeon/webwire-go/client"
+)
+
+// TestUnderlyingConn tests whether the underlying gorilla/websocket
+// connection is exposed by the default connection upgrader
+func TestUnderlyingConn(t *testing.T) {
+	// Initialize webwire server
+	server := setupServer(
+		t,
+		&serverImpl{
+			onRequest: func(
+				_ context.Context,
+				conn wwr.Connection,
+				_ wwr.Message,
+			) (wwr.Payload, error) {
+				underlying, isWsConn := conn.UnderlyingConn().(*websocket.Conn)
+				assert.True(t, isWsConn)
+				if isWsConn {
+					assert.Equal(
+						t,
+						conn.Info().RemoteAddr.String(),
+						underlying.RemoteAddr().String(),
+					)
+				}
+				return nil, nil
+			},
+		},
+		wwr.ServerOptions{},
+	)
+
+	// Initialize client
+	client := newCallbackPoweredClient(
+		server.Addr().String(),
+		wwrclt.Options{
+			DefaultRequestTimeout: 2 * time.Second,
+		},
+		callbackPoweredClientHooks{},
+	)
+	defer client.connection.Close()
+	require.NoError(t, client.connection.Connect())
+
+	_, err := client.connection.Request(context.Background(), "test", nil)
+	require.NoError(t, err)
+}
@@ -0,0 +1,96 @@
+package test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	tmdwg "github.com/qbeon/tmdwg-go"
+	wwr "github.com/qbeon/webwire-go"
+	wwrclt "github.com/qbeon/webwire-go/client"
+	msg "github.com/qbeon/webwire-go/message"
+)
+
+// TestUnicodeNames tests sending requests and signals with UTF8 names
+// when the UTF8 name validator is explicitly enabled
+func TestUnicodeNames(t *testing.T) {
+	const requestName = "api.ユーザー.取得"
+	const signalName = "api.ユーザー.更新"
+	signalArrived := tmdwg.NewTimedWaitGroup(1, 1*time.Second)
+
+	// Initialize webwire server replying with a signal
+	server := setupServer(
+		t,
+		&serverImpl{
+			onRequest: func(
+				_ context.Context,
+				conn wwr.Connection,
+				message wwr.Message,
+			) (wwr.Payload, error) {
+				assert.Equal(t, requestName, message.Name())
+				assert.NoError(t, conn.Signal(
+					signalName,
+					wwr.NewPayload(wwr.EncodingUtf8, []byte("test")),
+				))
+				return nil, nil
+			},
+		},
+		wwr.ServerOptions{
+			NameValidator: msg.ValidateNameUTF8,
+		},
+	)
+
+	// Initialize client
+	client := newCallbackPoweredClient(
+		server.Addr().String(),
+		wwrclt.Options{
+			DefaultRequestTimeout: 2 * time.Second,
+			NameValidator:         msg.ValidateNameUTF8,
+		},
+		callbackPoweredClientHooks{
+			OnSignal: func(message wwr.Message) {
+				assert.Equal(t, signalName, message.Name())
+				signalArrived.Progress(1)
+			},
+		},
+	)
+	defer client.connection.Close()
+
+	require.NoError(t, client.connection.Connect())
+
+	_, err := client.connection.Request(context.Background(), requestName, nil)
+	require.NoError(t, err)
+	require.NoError(t, signalArrived.Wait())
+}
+
+// TestUnicodeNamesDefault tests the rejection of UTF8 names
+// by the default name validator
+func TestUnicodeNamesDefault(t *testing.T) {
+	// Initialize webwire server
+	server := setupServer(t, &serverImpl{}, wwr.ServerOptions{})
+
+	// Initialize client
+	client := newCallbackPoweredClient(
+		server.Addr().String(),
+		wwrclt.Options{
+			DefaultRequestTimeout: 2 * time.Second,
+		},
+		callbackPoweredClientHooks{},
+	)
+	defer client.connection.Close()
+
+	require.NoError(t, client.connection.Connect())
+
+	_, err := client.connection.Request(
+		context.Background(),
+		"api.ユーザー.取得",
+		nil,
+	)
+	require.IsType(t, wwr.ProtocolErr{}, err)
+
+	err = client.connection.Signal("api.ユーザー.更新", nil)
+	require.IsType(t, wwr.ProtocolErr{}, err)
+}
@@ -0,0 +1,75 @@
+package test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	tmdwg "github.com/qbeon/tmdwg-go"
+	wwr "github.com/qbeon/webwire-go"
+)
+
+// TestUnknownMessageTypeIgnore tests whether messages of unknown types
+// are dropped without closing the connection by default
+func TestUnknownMessageTypeIgnore(t *testing.T) {
+	connected := tmdwg.NewTimedWaitGroup(1, 1*time.Second)
+	var serverSideConn wwr.Connection
+
+	// Initialize webwire server
+	server := setupServer(
+		t,
+		&serverImpl{
+			onClientConnected: func(conn wwr.Connection) {
+				serverSideConn = conn
+				connected.Progress(1)
+			},
+		},
+		wwr.ServerOptions{},
+	)
+
+	conn := dialRaw(t, server)
+	defer conn.Close()
+	require.NoError(t, connected.Wait())
+	require.NoError(t, conn.WriteMessage(
+		websocket.BinaryMessage,
+		[]byte{byte(200)},
+	))
+
+	// Expect the connection to stay open
+	time.Sleep(50 * time.Millisecond)
+	require.True(t, serverSideConn.IsActive())
+}
+
+// TestUnknownMessageTypeDisconnect tests whether clients sending messages
+// of unknown types are disconnected if configured
+func TestUnknownMessageTypeDisconnect(t *testing.T) {
+	disconnected := tmdwg.NewTimedWaitGroup(1, 1*time.Second)
+
+	// Initialize webwire server
+	server := setupServer(
+		t,
+		&serverImpl{
+			onClientDisconnected: func(
+				_ wwr.Connection,
+				reason wwr.DisconnectReason,
+			) {
+				assert.Equal(t, wwr.DisconnectServerInitiated, reason)
+				disconnected.Progress(1)
+			},
+		},
+		wwr.ServerOptions{
+			OnUnknownMessageType: wwr.UnknownMessageTypeDisconnect,
+		},
+	)
+
+	conn := dialRaw(t, server)
+	defer conn.Close()
+	require.NoError(t, conn.WriteMessage(
+		websocket.BinaryMessage,
+		[]byte{byte(200)},
+	))
+	require.NoError(t, disconnected.Wait())
+}
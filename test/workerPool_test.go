@@ -0,0 +1,213 @@
+package test
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	tmdwg "github.com/qbeon/tmdwg-go"
+	wwr "github.com/qbeon/webwire-go"
+	wwrclt "github.com/qbeon/webwire-go/client"
+)
+
+// TestWorkerPool tests whether the number of concurrently handled
+// messages across all connections is bounded by the worker pool size
+func TestWorkerPool(t *testing.T) {
+	poolSize := 2
+	clientsNum := 4
+	poolSaturated := tmdwg.NewTimedWaitGroup(poolSize, 1*time.Second)
+	release := make(chan struct{})
+	var active int32
+	var maxActive int32
+
+	// Initialize webwire server
+	server := setupServer(
+		t,
+		&serverImpl{
+			onRequest: func(
+				_ context.Context,
+				_ wwr.Connection,
+				_ wwr.Message,
+			) (wwr.Payload, error) {
+				current := atomic.AddInt32(&active, 1)
+				defer atomic.AddInt32(&active, -1)
+				for {
+					max := atomic.LoadInt32(&maxActive)
+					if current <= max || atomic.CompareAndSwapInt32(
+						&maxActive,
+						max,
+						current,
+					) {
+						break
+					}
+				}
+				poolSaturated.Progress(1)
+				<-release
+				return nil, nil
+			},
+		},
+		wwr.ServerOptions{
+			WorkerPoolSize: uint(poolSize),
+		},
+	)
+
+	// Send a blocking request from each client concurrently
+	finished := sync.WaitGroup{}
+	finished.Add(clientsNum)
+	for i := 0; i < clientsNum; i++ {
+		client := newCallbackPoweredClient(
+			server.Addr().String(),
+			wwrclt.Options{
+				DefaultRequestTimeout: 2 * time.Second,
+			},
+			callbackPoweredClientHooks{},
+		)
+		defer client.connection.Close()
+		require.NoError(t, client.connection.Connect())
+
+		go func() {
+			defer finished.Done()
+			_, err := client.connection.Request(
+				context.Background(),
+				"block",
+				nil,
+			)
+			assert.NoError(t, err)
+		}()
+	}
+
+	// Expect only poolSize requests to be handled concurrently
+	require.NoError(t, poolSaturated.Wait())
+	time.Sleep(50 * time.Millisecond)
+	require.Equal(t, int32(poolSize), atomic.LoadInt32(&active))
+
+	// Expect all queued requests to be handled eventually
+	close(release)
+	finished.Wait()
+	require.Equal(t, int32(poolSize), atomic.LoadInt32(&maxActive))
+}
+
+// TestWorkerPoolConnectionClosed tests whether a connection awaiting
+// a free worker slot stops being served when it's closed
+func TestWorkerPoolConnectionClosed(t *testing.T) {
+	started := tmdwg.NewTimedWaitGroup(1, 1*time.Second)
+	disconnected := tmdwg.NewTimedWaitGroup(1, 1*time.Second)
+	release := make(chan struct{})
+	defer close(release)
+	var blockedConn wwr.Connection
+
+	// Initialize webwire server
+	server := setupServer(
+		t,
+		&serverImpl{
+			onClientDisconnected: func(
+				_ wwr.Connection,
+				_ wwr.DisconnectReason,
+			) {
+				disconnected.Progress(1)
+			},
+			onRequest: func(
+				_ context.Context,
+				conn wwr.Connection,
+				_ wwr.Message,
+			) (wwr.Payload, error) {
+				blockedConn = conn
+				started.Progress(1)
+				<-release
+				return nil, nil
+			},
+		},
+		wwr.ServerOptions{
+			WorkerPoolSize: 1,
+		},
+	)
+
+	client := newCallbackPoweredClient(
+		server.Addr().String(),
+		wwrclt.Options{
+			DefaultRequestTimeout: 2 * time.Second,
+		},
+		callbackPoweredClientHooks{},
+	)
+	defer client.connection.Close()
+	require.NoError(t, client.connection.Connect())
+
+	// Occupy the only worker slot
+	go client.connection.Request(context.Background(), "block", nil)
+	require.NoError(t, started.Wait())
+
+	// Block the read loop of the connection awaiting a free worker slot
+	go client.connection.Request(context.Background(), "queued", nil)
+	time.Sleep(50 * time.Millisecond)
+
+	// Expect the connection to be disconnected
+	// even though the worker slot is still occupied
+	blockedConn.Close()
+	require.NoError(t, disconnected.Wait())
+}
+
+// TestWorkerPoolShutdown tests whether a connection awaiting
+// a free worker slot stops being served when the server is shut down
+func TestWorkerPoolShutdown(t *testing.T) {
+	started := tmdwg.NewTimedWaitGroup(1, 1*time.Second)
+	disconnected := tmdwg.NewTimedWaitGroup(1, 1*time.Second)
+	release := make(chan struct{})
+
+	// Initialize webwire server
+	server := setupServer(
+		t,
+		&serverImpl{
+			onClientDisconnected: func(
+				_ wwr.Connection,
+				_ wwr.DisconnectReason,
+			) {
+				disconnected.Progress(1)
+			},
+			onRequest: func(
+				_ context.Context,
+				_ wwr.Connection,
+				_ wwr.Message,
+			) (wwr.Payload, error) {
+				started.Progress(1)
+				<-release
+				return nil, nil
+			},
+		},
+		wwr.ServerOptions{
+			WorkerPoolSize: 1,
+		},
+	)
+
+	client := newCallbackPoweredClient(
+		server.Addr().String(),
+		wwrclt.Options{
+			DefaultRequestTimeout: 2 * time.Second,
+		},
+		callbackPoweredClientHooks{},
+	)
+	defer client.connection.Close()
+	require.NoError(t, client.connection.Connect())
+
+	// Occupy the only worker slot
+	go client.connection.Request(context.Background(), "block", nil)
+	require.NoError(t, started.Wait())
+
+	// Block the read loop of the connection awaiting a free worker slot
+	go client.connection.Request(context.Background(), "queued", nil)
+	time.Sleep(50 * time.Millisecond)
+
+	// Expect the connection to be disconnected during the shutdown
+	// even though the worker slot is still occupied
+	shutdownErr := make(chan error, 1)
+	go func() { shutdownErr <- server.Shutdown() }()
+	require.NoError(t, disconnected.Wait())
+
+	// Expect the shutdown to complete after the handler returned
+	close(release)
+	require.NoError(t, <-shutdownErr)
+}
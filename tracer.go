@@ -0,0 +1,16 @@
+package webwire
+
+import "context"
+
+// nopTracer represents the default no-op implementation
+// of the Tracer interface
+type nopTracer struct{}
+
+// StartSpan implements the Tracer interface
+func (tracer nopTracer) StartSpan(
+	ctx context.Context,
+	_ string,
+	_ Message,
+) (context.Context, func(err error)) {
+	return ctx, func(error) {}
+}
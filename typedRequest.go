@@ -0,0 +1,48 @@
+//go:build go1.18
+// +build go1.18
+
+package webwire
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+)
+
+// Handle wraps the given typed request handler into a function matching
+// the signature of the OnRequest hook. The request payload is JSON-decoded
+// into a Req before the handler is invoked and the returned Resp is
+// JSON-encoded into a UTF8 encoded reply payload (see Reply).
+// Empty request payloads are passed to the handler as the zero value of Req.
+// Payloads that can't be decoded are rejected with a DECODING_FAILURE
+// request error without invoking the handler
+func Handle[Req, Resp any](
+	handler func(ctx context.Context, client Connection, request Req) (Resp, error),
+) func(context.Context, Connection, Message) (Payload, error) {
+	return func(
+		ctx context.Context,
+		client Connection,
+		message Message,
+	) (Payload, error) {
+		var request Req
+		if payload := message.Payload(); payload != nil &&
+			len(payload.Data()) > 0 {
+			text, err := payload.Utf8()
+			if err == nil {
+				err = json.Unmarshal([]byte(text), &request)
+			}
+			if err != nil {
+				return nil, ReqErr{
+					Code:    "DECODING_FAILURE",
+					Message: fmt.Sprintf("Failed decoding request: %s", err),
+				}
+			}
+		}
+
+		response, err := handler(ctx, client, request)
+		if err != nil {
+			return nil, err
+		}
+		return Reply(response)
+	}
+}
@@ -0,0 +1,31 @@
+package webwire
+
+// UnknownMessageTypePolicy defines how the server treats incoming messages
+// of types it doesn't know, see ServerOptions.OnUnknownMessageType
+type UnknownMessageTypePolicy int
+
+const (
+	// UnknownMessageTypeIgnore silently drops messages of unknown types
+	UnknownMessageTypeIgnore UnknownMessageTypePolicy = iota
+
+	// UnknownMessageTypeWarn drops messages of unknown types
+	// logging a warning
+	UnknownMessageTypeWarn
+
+	// UnknownMessageTypeDisconnect closes the connection of clients
+	// sending messages of unknown types logging a warning
+	UnknownMessageTypeDisconnect
+)
+
+// String stringifies the unknown message type policy
+func (policy UnknownMessageTypePolicy) String() string {
+	switch policy {
+	case UnknownMessageTypeIgnore:
+		return "ignore"
+	case UnknownMessageTypeWarn:
+		return "warn"
+	case UnknownMessageTypeDisconnect:
+		return "disconnect"
+	}
+	return ""
+}
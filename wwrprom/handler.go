@@ -0,0 +1,119 @@
+// Package wwrprom exposes the live counters of a webwire server
+// in the Prometheus text exposition format without depending
+// on the Prometheus client library
+package wwrprom
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	wwr "github.com/qbeon/webwire-go"
+)
+
+// contentType is the content type of the Prometheus text exposition format
+const contentType = "text/plain; version=0.0.4; charset=utf-8"
+
+// Handler returns an HTTP handler exposing the request counts, request
+// latencies and the number of active connections and sessions
+// of the given server in the Prometheus text exposition format.
+// The counters are read from Server.Metrics on each scrape
+func Handler(server wwr.Server) http.Handler {
+	return http.HandlerFunc(func(
+		resp http.ResponseWriter,
+		_ *http.Request,
+	) {
+		resp.Header().Set("Content-Type", contentType)
+		resp.Write(Encode(server.Metrics()))
+	})
+}
+
+// Encode encodes the given metrics in the Prometheus text exposition format
+func Encode(metrics wwr.Metrics) []byte {
+	buf := &bytes.Buffer{}
+
+	writeHeader(
+		buf,
+		"webwire_requests_total",
+		"Number of requests handled.",
+		"counter",
+	)
+	fmt.Fprintf(buf, "webwire_requests_total %d\n", metrics.RequestsTotal)
+
+	writeHeader(
+		buf,
+		"webwire_requests_failed_total",
+		"Number of requests failed with an error.",
+		"counter",
+	)
+	fmt.Fprintf(
+		buf,
+		"webwire_requests_failed_total %d\n",
+		metrics.RequestsFailed,
+	)
+
+	writeHeader(
+		buf,
+		"webwire_request_duration_seconds",
+		"Latency of handled requests.",
+		"histogram",
+	)
+	for i, bound := range metrics.RequestLatencyBounds {
+		fmt.Fprintf(
+			buf,
+			"webwire_request_duration_seconds_bucket{le=\"%s\"} %d\n",
+			formatSeconds(bound),
+			metrics.RequestLatencyBuckets[i],
+		)
+	}
+	fmt.Fprintf(
+		buf,
+		"webwire_request_duration_seconds_bucket{le=\"+Inf\"} %d\n",
+		metrics.RequestsTotal,
+	)
+	fmt.Fprintf(
+		buf,
+		"webwire_request_duration_seconds_sum %s\n",
+		formatSeconds(metrics.RequestLatencySum),
+	)
+	fmt.Fprintf(
+		buf,
+		"webwire_request_duration_seconds_count %d\n",
+		metrics.RequestsTotal,
+	)
+
+	writeHeader(
+		buf,
+		"webwire_active_connections",
+		"Number of currently connected clients.",
+		"gauge",
+	)
+	fmt.Fprintf(
+		buf,
+		"webwire_active_connections %d\n",
+		metrics.ActiveConnections,
+	)
+
+	writeHeader(
+		buf,
+		"webwire_active_sessions",
+		"Number of sessions with at least one connection.",
+		"gauge",
+	)
+	fmt.Fprintf(buf, "webwire_active_sessions %d\n", metrics.ActiveSessions)
+
+	return buf.Bytes()
+}
+
+// writeHeader writes the HELP and TYPE lines of a metric
+func writeHeader(buf *bytes.Buffer, name, help, metricType string) {
+	fmt.Fprintf(buf, "# HELP %s %s\n", name, help)
+	fmt.Fprintf(buf, "# TYPE %s %s\n", name, metricType)
+}
+
+// formatSeconds formats the given duration as a number of seconds
+func formatSeconds(duration time.Duration) string {
+	return strconv.FormatFloat(duration.Seconds(), 'g', -1, 64)
+}
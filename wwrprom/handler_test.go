@@ -0,0 +1,45 @@
+package wwrprom
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	wwr "github.com/qbeon/webwire-go"
+	"github.com/stretchr/testify/require"
+)
+
+// TestEncode tests encoding metrics in the Prometheus text exposition format
+func TestEncode(t *testing.T) {
+	encoded := string(Encode(wwr.Metrics{
+		RequestsTotal:     3,
+		RequestsFailed:    1,
+		RequestLatencySum: 1500 * time.Millisecond,
+		RequestLatencyBounds: []time.Duration{
+			5 * time.Millisecond,
+			1 * time.Second,
+		},
+		RequestLatencyBuckets: []uint64{1, 2},
+		ActiveConnections:     4,
+		ActiveSessions:        2,
+	}))
+
+	expectedLines := []string{
+		"# TYPE webwire_requests_total counter",
+		"webwire_requests_total 3",
+		"webwire_requests_failed_total 1",
+		"# TYPE webwire_request_duration_seconds histogram",
+		`webwire_request_duration_seconds_bucket{le="0.005"} 1`,
+		`webwire_request_duration_seconds_bucket{le="1"} 2`,
+		`webwire_request_duration_seconds_bucket{le="+Inf"} 3`,
+		"webwire_request_duration_seconds_sum 1.5",
+		"webwire_request_duration_seconds_count 3",
+		"# TYPE webwire_active_connections gauge",
+		"webwire_active_connections 4",
+		"webwire_active_sessions 2",
+	}
+	lines := strings.Split(encoded, "\n")
+	for _, expected := range expectedLines {
+		require.Contains(t, lines, expected)
+	}
+}
@@ -0,0 +1,86 @@
+package wwrtest
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	wwr "github.com/qbeon/webwire-go"
+)
+
+type session struct {
+	Key        string
+	Creation   time.Time
+	LastLookup time.Time
+	Info       wwr.SessionInfo
+}
+
+// InMemSessionManager is an in-memory session manager for testing purposes
+type InMemSessionManager struct {
+	sessions map[string]session
+	lock     sync.Mutex
+}
+
+// NewInMemSessionManager constructs a new in-memory session manager
+// instance for testing purposes
+func NewInMemSessionManager() *InMemSessionManager {
+	return &InMemSessionManager{
+		sessions: make(map[string]session),
+		lock:     sync.Mutex{},
+	}
+}
+
+// OnSessionCreated implements the session manager interface.
+// It keeps the created session in memory
+func (mng *InMemSessionManager) OnSessionCreated(
+	_ context.Context,
+	conn wwr.Connection,
+) error {
+	mng.lock.Lock()
+	sess := conn.Session()
+	var sessInfo wwr.SessionInfo
+	if sess.Info != nil {
+		sessInfo = sess.Info.Copy()
+	}
+	mng.sessions[sess.Key] = session{
+		Key:      sess.Key,
+		Creation: sess.Creation,
+		Info:     sessInfo,
+	}
+	mng.lock.Unlock()
+	return nil
+}
+
+// OnSessionLookup implements the session manager interface.
+// It looks the session up in memory
+func (mng *InMemSessionManager) OnSessionLookup(
+	_ context.Context,
+	key string,
+) (wwr.SessionLookupResult, error) {
+	mng.lock.Lock()
+	defer mng.lock.Unlock()
+	if session, exists := mng.sessions[key]; exists {
+		// Update last lookup field
+		session.LastLookup = time.Now().UTC()
+		mng.sessions[key] = session
+
+		// Session found
+		return wwr.NewSessionLookupResult(
+			session.Creation,                      // Creation
+			session.LastLookup,                    // LastLookup
+			wwr.SessionInfoToVarMap(session.Info), // Info
+		), nil
+	}
+
+	// Session not found
+	return nil, nil
+}
+
+// OnSessionClosed implements the session manager interface.
+// It removes the session from memory
+func (mng *InMemSessionManager) OnSessionClosed(sessionKey string) error {
+	mng.lock.Lock()
+	delete(mng.sessions, sessionKey)
+	mng.lock.Unlock()
+	return nil
+}
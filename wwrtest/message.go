@@ -0,0 +1,55 @@
+package wwrtest
+
+import (
+	"fmt"
+
+	wwr "github.com/qbeon/webwire-go"
+	msg "github.com/qbeon/webwire-go/message"
+)
+
+// NewTestMessage creates a request message of the given name and payload
+// identical to the one the server passes to the OnRequest hook
+// after parsing the request, which allows for unit testing request handlers
+// in isolation without a socket. The connection isn't part of the message,
+// it's passed to the handler separately.
+// Panics if the message is invalid, for example if it has neither a name
+// nor a payload
+func NewTestMessage(name string, payload wwr.Payload) wwr.Message {
+	encoding := wwr.EncodingBinary
+	var data []byte
+	var contentType string
+	if payload != nil {
+		encoding = payload.Encoding()
+		data = payload.Data()
+		if typed, isTyped := payload.(wwr.ContentTyped); isTyped {
+			contentType = typed.ContentType()
+		}
+	}
+
+	// Pass the message through the parser for it to be consistent
+	// with parsed messages
+	var encoded []byte
+	if contentType != "" {
+		encoded = msg.NewTypedRequestMessage(
+			[8]byte{},
+			name,
+			contentType,
+			data,
+			msg.ValidateNameUTF8,
+		)
+	} else {
+		encoded = msg.NewRequestMessage(
+			[8]byte{},
+			name,
+			encoding,
+			data,
+			msg.ValidateNameUTF8,
+		)
+	}
+
+	parsed := &msg.Message{}
+	if _, err := parsed.Parse(encoded); err != nil {
+		panic(fmt.Errorf("Couldn't parse test message: %s", err))
+	}
+	return wwr.NewMessageWrapper(parsed)
+}
@@ -0,0 +1,19 @@
+package wwrtest
+
+import wwr "github.com/qbeon/webwire-go"
+
+// nopClientImplementation implements the wwrclt.Implementation interface
+// ignoring all events
+type nopClientImplementation struct{}
+
+// OnDisconnected implements the wwrclt.Implementation interface
+func (clt *nopClientImplementation) OnDisconnected() {}
+
+// OnSignal implements the wwrclt.Implementation interface
+func (clt *nopClientImplementation) OnSignal(_ wwr.Message) {}
+
+// OnSessionCreated implements the wwrclt.Implementation interface
+func (clt *nopClientImplementation) OnSessionCreated(_ *wwr.Session) {}
+
+// OnSessionClosed implements the wwrclt.Implementation interface
+func (clt *nopClientImplementation) OnSessionClosed() {}
@@ -0,0 +1,41 @@
+package wwrtest
+
+import (
+	"encoding/base64"
+	"math/rand"
+	"sync"
+
+	wwr "github.com/qbeon/webwire-go"
+)
+
+// deterministicSessionKeyGenerator implements
+// the webwire.SessionKeyGenerator interface
+type deterministicSessionKeyGenerator struct {
+	lock sync.Mutex
+	rand *rand.Rand
+}
+
+// NewDeterministicSessionKeyGenerator constructs a new session key generator
+// deriving the keys from the given source of pseudo-random numbers.
+// Generators constructed from identically seeded sources generate
+// identical sequences of keys making test failures reproducible.
+//
+// WARNING: the generated keys are predictable, this generator must only
+// be used for testing purposes, servers default to a crypto-random generator
+func NewDeterministicSessionKeyGenerator(
+	source rand.Source,
+) wwr.SessionKeyGenerator {
+	return &deterministicSessionKeyGenerator{
+		lock: sync.Mutex{},
+		rand: rand.New(source),
+	}
+}
+
+// Generate implements the webwire.SessionKeyGenerator interface
+func (gen *deterministicSessionKeyGenerator) Generate() string {
+	bytes := make([]byte, 48)
+	gen.lock.Lock()
+	gen.rand.Read(bytes)
+	gen.lock.Unlock()
+	return base64.URLEncoding.EncodeToString(bytes)
+}
@@ -0,0 +1,23 @@
+package wwrtest
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// TestDeterministicSessionKeyGenerator tests whether identically seeded
+// generators generate identical sequences of unique keys
+func TestDeterministicSessionKeyGenerator(t *testing.T) {
+	genA := NewDeterministicSessionKeyGenerator(rand.NewSource(42))
+	genB := NewDeterministicSessionKeyGenerator(rand.NewSource(42))
+
+	keys := make(map[string]struct{})
+	for i := 0; i < 8; i++ {
+		key := genA.Generate()
+		require.Equal(t, key, genB.Generate())
+		require.NotContains(t, keys, key)
+		keys[key] = struct{}{}
+	}
+}
@@ -0,0 +1,105 @@
+// Package wwrtest provides helpers for integration testing
+// webwire server implementations against a real client
+// over a loopback connection and for unit testing request handlers
+// in isolation
+package wwrtest
+
+import (
+	"testing"
+
+	wwr "github.com/qbeon/webwire-go"
+	wwrclt "github.com/qbeon/webwire-go/client"
+)
+
+// NewServer sets up and launches a headed server on a randomly assigned
+// loopback port unless an address is specified. An in-memory session
+// manager is used unless another one is specified.
+// The returned function shuts the server down
+// and reports its failures, it must be called before the test ends
+func NewServer(
+	t testing.TB,
+	impl wwr.ServerImplementation,
+	opts wwr.ServerOptions,
+) (wwr.Server, func()) {
+	// Use an in-memory session manager if no specific one is defined
+	if opts.SessionManager == nil {
+		opts.SessionManager = NewInMemSessionManager()
+	}
+
+	// Listen on an arbitrary loopback port unless specified otherwise
+	if opts.Address == "" {
+		opts.Address = "127.0.0.1:0"
+	}
+
+	// Disable the heartbeat if not set
+	if opts.Heartbeat == wwr.OptionUnset {
+		opts.Heartbeat = wwr.Disabled
+	}
+
+	server, err := wwr.NewServer(impl, opts)
+	if err != nil {
+		t.Fatalf("Failed setting up server instance: %s", err)
+	}
+
+	// Run server in a separate goroutine, its failure is reported
+	// by the shutdown function since t must not be used
+	// after the test ended
+	runErr := make(chan error, 1)
+	go func() {
+		runErr <- server.Run()
+	}()
+
+	shutdown := func() {
+		if err := server.Shutdown(); err != nil {
+			t.Errorf("Server shutdown failed: %s", err)
+		}
+		if err := <-runErr; err != nil {
+			t.Errorf("Server failed: %s", err)
+		}
+	}
+
+	return server, shutdown
+}
+
+// NewClient sets up a client connected to the given server.
+// If no implementation is given then a no-op implementation is used.
+// The client must be closed by the caller
+func NewClient(
+	t testing.TB,
+	server wwr.Server,
+	impl wwrclt.Implementation,
+	opts wwrclt.Options,
+) wwrclt.Client {
+	if impl == nil {
+		impl = &nopClientImplementation{}
+	}
+
+	// Connect explicitly
+	if opts.Autoconnect == wwr.OptionUnset {
+		opts.Autoconnect = wwr.Disabled
+	}
+
+	client := wwrclt.NewClient(server.Addr().String(), impl, opts)
+	if err := client.Connect(); err != nil {
+		t.Fatalf("Failed connecting client: %s", err)
+	}
+
+	return client
+}
+
+// NewPair sets up a server using the given implementation and options
+// together with a client connected to it.
+// The returned function closes the client and shuts the server down,
+// it must be called before the test ends
+func NewPair(
+	t testing.TB,
+	impl wwr.ServerImplementation,
+	opts wwr.ServerOptions,
+) (wwr.Server, wwrclt.Client, func()) {
+	server, shutdown := NewServer(t, impl, opts)
+	client := NewClient(t, server, nil, wwrclt.Options{})
+	return server, client, func() {
+		client.Close()
+		shutdown()
+	}
+}
@@ -0,0 +1,93 @@
+package wwrtest
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	wwr "github.com/qbeon/webwire-go"
+)
+
+// echoServer implements the webwire.ServerImplementation interface
+// replying to requests with the request payload
+type echoServer struct{}
+
+func (srv *echoServer) OnOptions(_ http.ResponseWriter) {}
+
+func (srv *echoServer) BeforeUpgrade(
+	_ http.ResponseWriter,
+	_ *http.Request,
+) wwr.ConnectionOptions {
+	return wwr.AcceptConnection(wwr.UnlimitedConcurrency)
+}
+
+func (srv *echoServer) OnClientConnected(_ wwr.Connection) {}
+
+func (srv *echoServer) OnClientDisconnected(
+	_ wwr.Connection,
+	_ wwr.DisconnectReason,
+) {
+}
+
+func (srv *echoServer) OnSignal(
+	_ context.Context,
+	_ wwr.Connection,
+	_ wwr.Message,
+) {
+}
+
+func (srv *echoServer) OnRequest(
+	_ context.Context,
+	_ wwr.Connection,
+	message wwr.Message,
+) (wwr.Payload, error) {
+	return message.Payload(), nil
+}
+
+// TestNewPair tests setting up a connected server and client pair
+func TestNewPair(t *testing.T) {
+	_, client, cleanup := NewPair(t, &echoServer{}, wwr.ServerOptions{})
+	defer cleanup()
+
+	reply, err := client.Request(
+		context.Background(),
+		"echo",
+		wwr.NewPayload(wwr.EncodingUtf8, []byte("sample")),
+	)
+	require.NoError(t, err)
+	require.Equal(t, wwr.EncodingUtf8, reply.Encoding())
+	require.Equal(t, []byte("sample"), reply.Data())
+}
+
+// TestNewTestMessage tests invoking a request handler directly
+// with a test message
+func TestNewTestMessage(t *testing.T) {
+	message := NewTestMessage(
+		"echo",
+		wwr.NewPayload(wwr.EncodingUtf16, []byte{'s', 0, 'a', 0}),
+	)
+	require.Equal(t, "echo", message.Name())
+
+	reply, err := (&echoServer{}).OnRequest(
+		context.Background(),
+		nil,
+		message,
+	)
+	require.NoError(t, err)
+	require.Equal(t, wwr.EncodingUtf16, reply.Encoding())
+	require.Equal(t, []byte{'s', 0, 'a', 0}, reply.Data())
+
+	// Expect content-typed payloads to be preserved
+	typed := NewTestMessage(
+		"upload",
+		wwr.NewTypedPayload("image/png", []byte{1, 2, 3}),
+	)
+	require.Equal(
+		t,
+		"image/png",
+		typed.Payload().(wwr.ContentTyped).ContentType(),
+	)
+	require.Equal(t, []byte{1, 2, 3}, typed.Payload().Data())
+}